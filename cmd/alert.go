@@ -0,0 +1,248 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ohader/gh-hookmon/internal/alert"
+	"github.com/ohader/gh-hookmon/internal/cache"
+	"github.com/ohader/gh-hookmon/internal/github"
+	"github.com/ohader/gh-hookmon/internal/output"
+	"github.com/ohader/gh-hookmon/internal/stats"
+)
+
+// alerter opens incidents for hooks exceeding the failure threshold and resolves them when the hooks recover
+type alerter struct {
+	sink     alert.Sink
+	store    *cache.Cache    // Remembers open alerts between runs, nil if the cache is unavailable
+	stateKey string          // Cache key of the open alerts
+	open     map[string]bool // Keys of open alerts
+	seen     map[string]bool // Keys of the hooks evaluated by this run
+	failed   int             // Events that could not be sent
+}
+
+// newAlerter creates the alerter of the --alert provider or the --alert-url webhook, nil without either
+// Provider credentials are read from the environment, so they do not show up in process lists or shell histories
+func newAlerter() (*alerter, error) {
+	provider := checkOpts.Alert
+	if checkOpts.AlertURL != "" {
+		if provider != "" {
+			return nil, fmt.Errorf("validation error: --alert and --alert-url cannot be combined")
+		}
+		provider = "webhook"
+	} else if len(checkOpts.AlertHeaders) > 0 {
+		return nil, fmt.Errorf("validation error: --alert-header requires --alert-url")
+	}
+	if provider == "" {
+		return nil, nil
+	}
+
+	transport, err := github.NewTransport(cfg.CABundle)
+	if err != nil {
+		return nil, err
+	}
+	client := &http.Client{Transport: traceTransport(deadline.transport(transport)), Timeout: cfg.RequestTimeout}
+
+	var sink alert.Sink
+	switch provider {
+	case "pagerduty":
+		key := os.Getenv("PAGERDUTY_ROUTING_KEY")
+		if key == "" {
+			return nil, fmt.Errorf("validation error: --alert=pagerduty requires the integration key in PAGERDUTY_ROUTING_KEY")
+		}
+		sink = &alert.PagerDuty{HTTP: client, RoutingKey: key, URL: os.Getenv("PAGERDUTY_EVENTS_URL")}
+	case "opsgenie":
+		key := os.Getenv("OPSGENIE_API_KEY")
+		if key == "" {
+			return nil, fmt.Errorf("validation error: --alert=opsgenie requires the API key in OPSGENIE_API_KEY")
+		}
+		sink = &alert.Opsgenie{HTTP: client, APIKey: key, URL: os.Getenv("OPSGENIE_API_URL")}
+	case "webhook":
+		u, err := url.Parse(checkOpts.AlertURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("validation error: --alert-url must be an http or https URL, got %q", checkOpts.AlertURL)
+		}
+		if checkOpts.AlertRetries < 0 {
+			return nil, fmt.Errorf("validation error: --alert-retries must be a non-negative integer")
+		}
+		headers, err := parseHeaders(checkOpts.AlertHeaders)
+		if err != nil {
+			return nil, fmt.Errorf("validation error: --alert-header: %w", err)
+		}
+		sink = &alert.Webhook{HTTP: client, URL: checkOpts.AlertURL, Headers: headers, Retries: checkOpts.AlertRetries, Backoff: checkOpts.AlertBackoff}
+	default:
+		return nil, fmt.Errorf("validation error: --alert must be one of %s, got %q", strings.Join(alert.Providers, ", "), provider)
+	}
+
+	// Runs with another --filter evaluate other hooks, their open alerts are remembered separately
+	stateKey := "alerts:" + provider + ":" + collectTarget()
+	if cfg.Filter != "" {
+		stateKey += ":" + cfg.Filter
+	}
+	a := &alerter{sink: sink, stateKey: stateKey, open: map[string]bool{}, seen: map[string]bool{}}
+	if cfg.NoCache {
+		fmt.Fprintln(os.Stderr, "Warning: --no-cache forgets which alerts are open, hooks that recover are not resolved")
+		return a, nil
+	}
+	store, err := cache.New()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: cache unavailable, hooks that recover are not resolved: %v\n", err)
+		return a, nil
+	}
+	a.store = store
+	if _, _, err := store.Load(a.stateKey, &a.open); err != nil {
+		return nil, fmt.Errorf("failed to read open alerts: %w", err)
+	}
+	return a, nil
+}
+
+// failing opens or updates the incident of a hook exceeding the threshold
+func (a *alerter) failing(key stats.HookKey, summary *stats.Summary, window string, maxRate float64) {
+	id := alertKey(key)
+	a.seen[id] = true
+	err := a.sink.Trigger(alert.Alert{
+		Key: id,
+		Summary: fmt.Sprintf("Webhook %d of %s failing: %d of %d deliveries failed (%.1f%%) in the last %s",
+			key.HookID, key.Repository, summary.Failures, summary.Deliveries, summary.FailureRate()*100, window),
+		Source:  key.Repository,
+		Details: alertDetails(key, summary, window, maxRate),
+	})
+	if err != nil {
+		a.warn(err)
+		return
+	}
+	a.open[id] = true
+}
+
+// healthy resolves the open incident of a hook within the threshold
+// Only alerts known to be open are resolved, without the cache no alert is known
+func (a *alerter) healthy(key stats.HookKey, summary *stats.Summary, window string, maxRate float64) {
+	id := alertKey(key)
+	a.seen[id] = true
+	if !a.open[id] {
+		return
+	}
+	a.resolve(alert.Alert{
+		Key: id,
+		Summary: fmt.Sprintf("Webhook %d of %s recovered: %d of %d deliveries failed (%.1f%%) in the last %s",
+			key.HookID, key.Repository, summary.Failures, summary.Deliveries, summary.FailureRate()*100, window),
+		Source:  key.Repository,
+		Details: alertDetails(key, summary, window, maxRate),
+	})
+}
+
+// resolve resolves an open incident and forgets it
+func (a *alerter) resolve(recovery alert.Alert) {
+	if err := a.sink.Resolve(recovery); err != nil {
+		a.warn(err)
+		return
+	}
+	delete(a.open, recovery.Key)
+}
+
+// finish resolves the open alerts of hooks this run did not evaluate, remembers the open alerts
+// and returns an error if any event could not be sent
+// A hook is not evaluated if it had fewer than --min-deliveries deliveries in the window or was removed,
+// neither is failing any longer. Alerts of repositories that could not be scanned stay open.
+func (a *alerter) finish(window string) error {
+	unscanned := diag.failedRepositories(output.IssueRepositoryFailed, output.IssueRepositoryTimeout, output.IssueForbidden, output.IssueHookFailed)
+	for _, id := range sortedAlertKeys(a.open) {
+		if a.seen[id] {
+			continue
+		}
+		repo, hookID, ok := parseAlertKey(id)
+		if !ok || unscanned[repo] || deadline.exceeded() {
+			fmt.Fprintf(os.Stderr, "Warning: alert %s stays open, the hook was not checked\n", id)
+			continue
+		}
+		a.resolve(alert.Alert{
+			Key:     id,
+			Summary: fmt.Sprintf("Webhook %d of %s recovered: too few deliveries to check in the last %s, or the hook was removed", hookID, repo, window),
+			Source:  repo,
+			Details: map[string]interface{}{
+				"repository": repo,
+				"hook_id":    hookID,
+				"window":     window,
+			},
+		})
+	}
+
+	if a.store != nil {
+		if err := a.store.Set(a.stateKey, a.open); err != nil {
+			return fmt.Errorf("failed to remember open alerts: %w", err)
+		}
+	}
+	if a.failed > 0 {
+		return fmt.Errorf("%d alert events could not be sent", a.failed)
+	}
+	if cfg.Verbose && len(a.open) > 0 {
+		fmt.Fprintf(os.Stderr, "Open alerts: %s\n", strings.Join(sortedAlertKeys(a.open), ", "))
+	}
+	return nil
+}
+
+func (a *alerter) warn(err error) {
+	a.failed++
+	fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+}
+
+// parseHeaders parses HTTP headers given as "Name: value"
+func parseHeaders(specs []string) (map[string]string, error) {
+	headers := make(map[string]string, len(specs))
+	for _, spec := range specs {
+		name, value, ok := strings.Cut(spec, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" || strings.ContainsAny(name, " \t") {
+			return nil, fmt.Errorf("invalid header %q, expected \"Name: value\"", spec)
+		}
+		headers[name] = strings.TrimSpace(value)
+	}
+	return headers, nil
+}
+
+// alertKey identifies the incident of a hook across runs
+func alertKey(key stats.HookKey) string {
+	return fmt.Sprintf("gh-hookmon/%s/hooks/%d", key.Repository, key.HookID)
+}
+
+// parseAlertKey returns the repository and hook ID of an alert key
+func parseAlertKey(id string) (string, int, bool) {
+	repo, hook, ok := strings.Cut(strings.TrimPrefix(id, "gh-hookmon/"), "/hooks/")
+	if !ok {
+		return "", 0, false
+	}
+	hookID, err := strconv.Atoi(hook)
+	if err != nil {
+		return "", 0, false
+	}
+	return repo, hookID, true
+}
+
+// sortedAlertKeys returns the keys of the alerts in alphabetical order
+func sortedAlertKeys(alerts map[string]bool) []string {
+	keys := make([]string, 0, len(alerts))
+	for key := range alerts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// alertDetails describes the deliveries of a hook in the window for the alerting services
+func alertDetails(key stats.HookKey, summary *stats.Summary, window string, maxRate float64) map[string]interface{} {
+	return map[string]interface{}{
+		"repository":       key.Repository,
+		"hook_id":          key.HookID,
+		"url":              key.URL,
+		"deliveries":       summary.Deliveries,
+		"failures":         summary.Failures,
+		"failure_rate":     summary.FailureRate(),
+		"max_failure_rate": maxRate,
+		"window":           window,
+	}
+}
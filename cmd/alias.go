@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ohader/gh-hookmon/internal/config"
+)
+
+// expandAlias replaces an @name first argument with the arguments of that alias of the config file
+// The config file is not loaded yet, so its path is taken from the arguments directly
+func expandAlias(args []string) ([]string, error) {
+	if len(args) == 0 || !strings.HasPrefix(args[0], config.AliasPrefix) {
+		return args, nil
+	}
+
+	path := configFlagValue(args[1:])
+	required := path != ""
+	if !required {
+		defaultPath, err := config.DefaultConfigPath()
+		if err != nil {
+			return nil, err
+		}
+		path = defaultPath
+	}
+	file, err := config.LoadFile(path, required)
+	if err != nil {
+		return nil, err
+	}
+
+	expanded, err := file.ExpandAlias(args)
+	if err != nil {
+		return nil, err
+	}
+	if hasVerboseFlag(expanded) {
+		fmt.Fprintf(os.Stderr, "Expanded %s to: %s\n", args[0], strings.Join(expanded, " "))
+	}
+	return expanded, nil
+}
+
+// configFlagValue returns the value of --config in args, either --config=path or --config path
+func configFlagValue(args []string) string {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if value, ok := strings.CutPrefix(arg, "--config="); ok {
+			return value
+		}
+		if arg == "--config" && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return ""
+}
+
+// hasVerboseFlag reports whether args enable --verbose
+func hasVerboseFlag(args []string) bool {
+	for _, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if arg == "-v" || arg == "--verbose" || arg == "--verbose=true" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ohader/gh-hookmon/internal/config"
+	"github.com/ohader/gh-hookmon/internal/github"
+	"github.com/ohader/gh-hookmon/internal/output"
+	"github.com/ohader/gh-hookmon/internal/stats"
+	"github.com/spf13/cobra"
+)
+
+// anomaliesOptions holds the flags of the anomalies subcommand
+type anomaliesOptions struct {
+	Window        string
+	Baseline      string
+	MinRatio      float64
+	MinZScore     float64
+	MinDeliveries int
+	JSON          bool
+}
+
+var anomaliesOpts anomaliesOptions
+
+var anomaliesCmd = &cobra.Command{
+	Use:   "anomalies",
+	Short: "Detect hooks whose failure rate spiked compared to their baseline",
+	Long: `Compare each hook's failure rate in the current window against its trailing
+baseline and report statistically significant spikes.
+
+A spike requires both a minimum ratio between the current and the baseline
+failure rate and a minimum z-score of a two-proportion test, so hooks with
+few deliveries do not trigger on noise. Exits with exit code 2 if any spike
+is detected, suitable for alerting from scheduled jobs.
+
+Examples:
+  # Compare the last hour against the preceding 7 days
+  gh hookmon anomalies --org=myorg
+
+  # Only report hooks failing at least 10x more than usual
+  gh hookmon anomalies --org=myorg --window=6h --baseline=14d --min-ratio=10`,
+	SilenceUsage: true,
+	RunE:         runAnomalies,
+}
+
+func init() {
+	anomaliesCmd.Flags().StringVar(&anomaliesOpts.Window, "window", "1h", "Current window to evaluate, e.g. 1h or 6h")
+	anomaliesCmd.Flags().StringVar(&anomaliesOpts.Baseline, "baseline", "7d", "Trailing baseline preceding the current window, e.g. 7d")
+	anomaliesCmd.Flags().Float64Var(&anomaliesOpts.MinRatio, "min-ratio", 3, "Minimum ratio of current to baseline failure rate")
+	anomaliesCmd.Flags().Float64Var(&anomaliesOpts.MinZScore, "min-z", 3, "Minimum z-score for a spike to be significant")
+	anomaliesCmd.Flags().IntVar(&anomaliesOpts.MinDeliveries, "min-deliveries", 5, "Skip hooks with fewer deliveries in the current window")
+	anomaliesCmd.Flags().BoolVar(&anomaliesOpts.JSON, "json", false, "Output in JSON format")
+	anomaliesCmd.Flags().StringVar(&cfg.Filter, "filter", "", "Filter webhook URLs by pattern")
+	rootCmd.AddCommand(anomaliesCmd)
+}
+
+func runAnomalies(cmd *cobra.Command, args []string) error {
+	window, err := config.ParseDuration(anomaliesOpts.Window)
+	if err != nil {
+		return fmt.Errorf("validation error: --window: %w", err)
+	}
+	baseline, err := config.ParseDuration(anomaliesOpts.Baseline)
+	if err != nil {
+		return fmt.Errorf("validation error: --baseline: %w", err)
+	}
+	if err := validateTarget(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	baselineStart := now.Add(-window - baseline)
+	coverWindow(baselineStart)
+	deliveries, err := fetchDeliveries(client)
+	if err != nil {
+		return err
+	}
+
+	// A baseline missing its start would compare the window against too few deliveries
+	incomplete := incompleteWindow(deliveries, baselineStart, "it is skipped for lack of a full baseline")
+	complete := make([]github.Delivery, 0, len(deliveries))
+	for _, d := range deliveries {
+		if !incomplete[stats.ByHook(d)] {
+			complete = append(complete, d)
+		}
+	}
+
+	anomalies := stats.DetectAnomalies(complete, stats.AnomalyOptions{
+		Window:        window,
+		Baseline:      baseline,
+		MinRatio:      anomaliesOpts.MinRatio,
+		MinZScore:     anomaliesOpts.MinZScore,
+		MinDeliveries: anomaliesOpts.MinDeliveries,
+	}, now)
+
+	if anomaliesOpts.JSON {
+		if err := output.FormatAnomaliesJSON(anomalies, os.Stdout); err != nil {
+			return err
+		}
+	} else {
+		output.FormatAnomalies(anomalies, os.Stdout)
+	}
+
+	if len(anomalies) > 0 {
+		return &ExitError{
+			Code: exitCodeViolation,
+			Err:  fmt.Errorf("%d failure spikes detected", len(anomalies)),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ohader/gh-hookmon/internal/config"
+	"github.com/ohader/gh-hookmon/internal/github"
+	"github.com/ohader/gh-hookmon/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// auditOptions holds the flags of the audit subcommand
+type auditOptions struct {
+	RequireSecret bool
+	RequireJSON   bool
+	RequireSSL    bool
+	Stale         string
+	Fix           bool
+	Yes           bool
+	JSON          bool
+}
+
+var auditOpts auditOptions
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Audit webhook configurations",
+	Long: `Run configuration checks against all webhooks and report findings grouped
+by target domain.
+
+Exits with exit code 2 if any check reports a finding that was not fixed.
+With --fix, findings that can be fixed automatically (currently disabled
+SSL verification) are fixed after confirmation.
+
+Examples:
+  # List every active hook without a shared secret
+  gh hookmon audit --org=myorg --require-secret
+
+  # List every hook still sending form-encoded payloads
+  gh hookmon audit --org=myorg --require-json
+
+  # List active hooks without any delivery in the last 30 days
+  gh hookmon audit --org=myorg --stale=30d
+
+  # Enable SSL certificate verification for every hook that disabled it
+  gh hookmon audit --org=myorg --require-ssl --fix`,
+	SilenceUsage: true,
+	RunE:         runAudit,
+}
+
+func init() {
+	auditCmd.Flags().BoolVar(&auditOpts.RequireSecret, "require-secret", false, "Report active hooks without a shared secret")
+	auditCmd.Flags().BoolVar(&auditOpts.RequireJSON, "require-json", false, "Report hooks sending application/x-www-form-urlencoded payloads")
+	auditCmd.Flags().BoolVar(&auditOpts.RequireSSL, "require-ssl", false, "Report hooks with SSL certificate verification disabled (insecure_ssl=1)")
+	auditCmd.Flags().StringVar(&auditOpts.Stale, "stale", "", "Report active hooks without deliveries in this window, e.g. 30d")
+	auditCmd.Flags().BoolVar(&auditOpts.Fix, "fix", false, "Enable SSL certificate verification for hooks reported by --require-ssl")
+	auditCmd.Flags().BoolVarP(&auditOpts.Yes, "yes", "y", false, "Do not ask for confirmation with --fix")
+	auditCmd.Flags().BoolVar(&auditOpts.JSON, "json", false, "Output in JSON format")
+	auditCmd.Flags().StringVar(&cfg.Filter, "filter", "", "Filter webhook URLs by pattern")
+	rootCmd.AddCommand(auditCmd)
+}
+
+func runAudit(cmd *cobra.Command, args []string) error {
+	if !auditOpts.RequireSecret && !auditOpts.RequireJSON && !auditOpts.RequireSSL && auditOpts.Stale == "" {
+		return fmt.Errorf("validation error: no audit check selected (use --require-secret, --require-json, --require-ssl or --stale)")
+	}
+	var staleWindow time.Duration
+	if auditOpts.Stale != "" {
+		window, err := config.ParseDuration(auditOpts.Stale)
+		if err != nil {
+			return fmt.Errorf("validation error: --stale: %w", err)
+		}
+		staleWindow = window
+	}
+	if auditOpts.Fix && !auditOpts.RequireSSL {
+		return fmt.Errorf("validation error: --fix requires --require-ssl")
+	}
+	if auditOpts.Fix {
+		if err := requireOnline("--fix"); err != nil {
+			return err
+		}
+	}
+	if err := validateTarget(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	// Deliveries are only needed for the stale check
+	var hooks []github.RepoHook
+	lastDelivery := make(map[int]time.Time)
+	if staleWindow > 0 {
+		if err := requireFeature(client, github.FeatureHookDeliveries); err != nil {
+			return fmt.Errorf("--stale: %w", err)
+		}
+		withDeliveries, err := scanTargets(client, fetchRepoHookDeliveries)
+		if err != nil {
+			return err
+		}
+		for _, h := range withDeliveries {
+			hooks = append(hooks, github.RepoHook{Repository: h.Repository, Hook: h.Hook})
+			for _, d := range h.Deliveries {
+				if d.DeliveredAt.After(lastDelivery[h.Hook.ID]) {
+					lastDelivery[h.Hook.ID] = d.DeliveredAt
+				}
+			}
+		}
+	} else {
+		hooks, err = fetchHooks(client)
+		if err != nil {
+			return err
+		}
+	}
+	staleSince := time.Now().Add(-staleWindow)
+
+	findings := make([]output.Finding, 0)
+	var insecure []github.RepoHook
+	for _, h := range hooks {
+		if auditOpts.RequireSecret && h.Hook.Active && !h.Hook.HasSecret() {
+			findings = append(findings, newFinding("secret", h, "no shared secret configured"))
+		}
+		if auditOpts.RequireJSON && h.Hook.GetContentType() != "json" {
+			findings = append(findings, newFinding("content-type", h, "payloads are sent as application/x-www-form-urlencoded"))
+		}
+		if auditOpts.RequireSSL && h.Hook.IsInsecureSSL() {
+			findings = append(findings, newFinding("ssl", h, "SSL certificate verification is disabled"))
+			insecure = append(insecure, h)
+		}
+		if staleWindow > 0 && h.Hook.Active && lastDelivery[h.ID].Before(staleSince) {
+			message := fmt.Sprintf("no deliveries in the last %s", auditOpts.Stale)
+			if last, ok := lastDelivery[h.ID]; ok {
+				message += fmt.Sprintf(", last delivery %s", last.Format(time.RFC3339))
+			}
+			findings = append(findings, newFinding("stale", h, message))
+		}
+	}
+
+	if auditOpts.JSON {
+		if err := output.FormatFindingsJSON(findings, os.Stdout); err != nil {
+			return err
+		}
+	} else {
+		output.FormatFindings(findings, os.Stdout)
+	}
+
+	remaining := len(findings)
+	if auditOpts.Fix && len(insecure) > 0 {
+		fixed, err := fixInsecureSSL(client, insecure)
+		if err != nil {
+			return err
+		}
+		remaining -= fixed
+	}
+
+	if remaining > 0 {
+		return &ExitError{
+			Code: exitCodeViolation,
+			Err:  fmt.Errorf("%d audit findings in %d hooks", remaining, len(hooks)),
+		}
+	}
+	return nil
+}
+
+// fixInsecureSSL enables SSL certificate verification for the hooks after confirmation
+// Returns the number of hooks that were fixed
+func fixInsecureSSL(client *github.Client, hooks []github.RepoHook) (int, error) {
+	// Keep stdout clean for JSON output
+	w := os.Stdout
+	if auditOpts.JSON {
+		w = os.Stderr
+	}
+
+	if !auditOpts.Yes {
+		ok, err := confirm(fmt.Sprintf("Enable SSL certificate verification for %d webhooks?", len(hooks)))
+		if err != nil {
+			return 0, err
+		}
+		if !ok {
+			return 0, nil
+		}
+	}
+
+	fixed := 0
+	for _, h := range hooks {
+		if err := client.UpdateRepoWebhookConfig(h.Repository, h.ID, github.HookConfigUpdate{InsecureSSL: "0"}); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", h.Repository, err)
+			continue
+		}
+		fixed++
+		fmt.Fprintf(w, "Fixed %s hook %d %s: SSL certificate verification enabled\n", h.Repository, h.ID, displayURL(h.GetTargetURL()))
+	}
+	return fixed, nil
+}
+
+// newFinding creates an audit finding for a hook
+func newFinding(check string, h github.RepoHook, message string) output.Finding {
+	return output.Finding{
+		Check:      check,
+		Repository: h.Repository,
+		HookID:     h.Hook.ID,
+		URL:        h.Hook.GetTargetURL(),
+		Domain:     h.Hook.GetTargetHost(),
+		Message:    message,
+	}
+}
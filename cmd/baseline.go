@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ohader/gh-hookmon/internal/config"
+	"github.com/ohader/gh-hookmon/internal/github"
+	"github.com/ohader/gh-hookmon/internal/stats"
+	"github.com/spf13/cobra"
+)
+
+// baselineOptions holds the flags of the baseline subcommands
+type baselineOptions struct {
+	File                   string
+	Window                 string
+	MaxFailureRateIncrease string
+	MaxLatencyIncrease     string
+	JSON                   bool
+}
+
+var baselineOpts baselineOptions
+
+var baselineCmd = &cobra.Command{
+	Use:   "baseline",
+	Short: "Save health baselines and report regressions against them",
+	Long: `Store a summary snapshot of every hook's success rate and latency, and later
+report regressions against it, e.g. before and after a receiver deployment.
+
+Examples:
+  # Save a baseline before deploying the receiver
+  gh hookmon baseline save --org=myorg --file=before-deploy.json
+
+  # Compare the current state against it
+  gh hookmon baseline diff --org=myorg --file=before-deploy.json`,
+}
+
+var baselineSaveCmd = &cobra.Command{
+	Use:          "save",
+	Short:        "Save a health snapshot of all hooks",
+	SilenceUsage: true,
+	RunE:         runBaselineSave,
+}
+
+var baselineDiffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Report regressions against a saved health snapshot",
+	Long: `Compare the current health of all hooks against a saved baseline.
+
+Exits with exit code 2 if any hook regressed.`,
+	SilenceUsage: true,
+	RunE:         runBaselineDiff,
+}
+
+func init() {
+	baselineCmd.PersistentFlags().StringVar(&baselineOpts.File, "file", "hookmon-baseline.json", "Baseline snapshot file")
+	baselineCmd.PersistentFlags().StringVar(&baselineOpts.Window, "window", "24h", "Time window summarized by the snapshot, e.g. 24h or 7d")
+	baselineCmd.PersistentFlags().StringVar(&cfg.Filter, "filter", "", "Filter webhook URLs by pattern")
+	baselineDiffCmd.Flags().StringVar(&baselineOpts.MaxFailureRateIncrease, "max-failure-rate-increase", "5%", "Allowed increase of the failure rate in percentage points")
+	baselineDiffCmd.Flags().StringVar(&baselineOpts.MaxLatencyIncrease, "max-latency-increase", "50%", "Allowed relative increase of the p95 latency")
+	baselineDiffCmd.Flags().BoolVar(&baselineOpts.JSON, "json", false, "Output in JSON format")
+	baselineCmd.AddCommand(baselineSaveCmd)
+	baselineCmd.AddCommand(baselineDiffCmd)
+	rootCmd.AddCommand(baselineCmd)
+}
+
+// currentSnapshot fetches deliveries and summarizes the configured window
+func currentSnapshot() (stats.Snapshot, error) {
+	window, err := config.ParseDuration(baselineOpts.Window)
+	if err != nil {
+		return stats.Snapshot{}, fmt.Errorf("validation error: --window: %w", err)
+	}
+	if err := validateTarget(); err != nil {
+		return stats.Snapshot{}, fmt.Errorf("validation error: %w", err)
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return stats.Snapshot{}, err
+	}
+
+	now := time.Now()
+	since := now.Add(-window)
+	coverWindow(since)
+	deliveries, err := fetchDeliveries(client)
+	if err != nil {
+		return stats.Snapshot{}, err
+	}
+	inWindow := make([]github.Delivery, 0, len(deliveries))
+	for _, d := range deliveries {
+		if !d.DeliveredAt.Before(since) {
+			inWindow = append(inWindow, d)
+		}
+	}
+	incompleteWindow(deliveries, since, "the snapshot misses the start of the window")
+
+	return stats.NewSnapshot(inWindow, baselineOpts.Window, now), nil
+}
+
+func runBaselineSave(cmd *cobra.Command, args []string) error {
+	snapshot, err := currentSnapshot()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode baseline: %w", err)
+	}
+	if err := os.WriteFile(baselineOpts.File, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write baseline: %w", err)
+	}
+
+	fmt.Printf("Saved baseline of %d hooks to %s\n", len(snapshot.Hooks), baselineOpts.File)
+	return nil
+}
+
+func runBaselineDiff(cmd *cobra.Command, args []string) error {
+	maxFailureRateIncrease, err := config.ParsePercentage(baselineOpts.MaxFailureRateIncrease)
+	if err != nil {
+		return fmt.Errorf("validation error: --max-failure-rate-increase: %w", err)
+	}
+	maxLatencyIncrease, err := parseRelativeIncrease(baselineOpts.MaxLatencyIncrease)
+	if err != nil {
+		return fmt.Errorf("validation error: --max-latency-increase: %w", err)
+	}
+
+	data, err := os.ReadFile(baselineOpts.File)
+	if err != nil {
+		return fmt.Errorf("failed to read baseline: %w", err)
+	}
+	var before stats.Snapshot
+	if err := json.Unmarshal(data, &before); err != nil {
+		return fmt.Errorf("failed to parse baseline %s: %w", baselineOpts.File, err)
+	}
+
+	after, err := currentSnapshot()
+	if err != nil {
+		return err
+	}
+
+	diff := stats.CompareSnapshots(before, after, stats.RegressionOptions{
+		MaxFailureRateIncrease: maxFailureRateIncrease,
+		MaxLatencyIncrease:     maxLatencyIncrease,
+	})
+
+	if baselineOpts.JSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(diff); err != nil {
+			return err
+		}
+	} else {
+		printSnapshotDiff(diff, before)
+	}
+
+	if len(diff.Regressions) > 0 {
+		return &ExitError{
+			Code: exitCodeViolation,
+			Err:  fmt.Errorf("%d hooks regressed compared to the baseline", len(diff.Regressions)),
+		}
+	}
+	return nil
+}
+
+// printSnapshotDiff prints one line per regression, added and removed hook
+func printSnapshotDiff(diff stats.SnapshotDiff, before stats.Snapshot) {
+	for _, r := range diff.Regressions {
+		fmt.Printf("REGRESSION %s hook %d %s: %s\n", r.After.Repository, r.After.HookID, displayURL(r.After.URL), strings.Join(r.Reasons, ", "))
+	}
+	for _, h := range diff.AddedHooks {
+		fmt.Printf("ADDED %s hook %d %s\n", h.Repository, h.HookID, displayURL(h.URL))
+	}
+	for _, h := range diff.RemovedHooks {
+		fmt.Printf("REMOVED %s hook %d %s\n", h.Repository, h.HookID, displayURL(h.URL))
+	}
+	fmt.Printf("Compared against baseline from %s: %d regressions, %d added, %d removed hooks\n",
+		before.CreatedAt.Format(time.RFC3339), len(diff.Regressions), len(diff.AddedHooks), len(diff.RemovedHooks))
+}
+
+// parseRelativeIncrease parses an increase like "50%" or "200%" into a fraction (0.5, 2)
+// Unlike ParsePercentage, values above 100% are allowed
+func parseRelativeIncrease(s string) (float64, error) {
+	value, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(s), "%"), 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid percentage %q", s)
+	}
+	return value / 100, nil
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ohader/gh-hookmon/internal/cache"
+	"github.com/ohader/gh-hookmon/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// cacheOptions holds the flags of the cache subcommands
+type cacheOptions struct {
+	JSON bool
+}
+
+var cacheOpts cacheOptions
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the on-disk response cache",
+	Long: `Show where the on-disk response cache is stored, summarize its entries or clear it.
+
+The cache holds the repository lists of organizations, which are fetched again once they
+are older than --cache-ttl, and the webhooks and deliveries of the last run for --offline.
+--no-cache bypasses the cache for a single run.
+
+Examples:
+  # Show the cache directory
+  gh hookmon cache path
+
+  # Show the number, size and age of cache entries
+  gh hookmon cache stats
+
+  # Remove all cache entries
+  gh hookmon cache clear`,
+}
+
+var cachePathCmd = &cobra.Command{
+	Use:          "path",
+	Short:        "Print the cache directory",
+	SilenceUsage: true,
+	RunE:         runCachePath,
+}
+
+var cacheStatsCmd = &cobra.Command{
+	Use:          "stats",
+	Short:        "Summarize the cache entries",
+	SilenceUsage: true,
+	RunE:         runCacheStats,
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:          "clear",
+	Short:        "Remove all cache entries",
+	SilenceUsage: true,
+	RunE:         runCacheClear,
+}
+
+func init() {
+	cacheStatsCmd.Flags().BoolVar(&cacheOpts.JSON, "json", false, "Output in JSON format")
+
+	cacheCmd.AddCommand(cachePathCmd, cacheStatsCmd, cacheClearCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
+
+func runCachePath(cmd *cobra.Command, args []string) error {
+	store, err := cache.New()
+	if err != nil {
+		return err
+	}
+	fmt.Println(store.Dir())
+	return nil
+}
+
+func runCacheStats(cmd *cobra.Command, args []string) error {
+	if err := cfg.ValidateCache(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+	store, err := cache.New()
+	if err != nil {
+		return err
+	}
+	s, err := store.Stats(cfg.CacheTTL)
+	if err != nil {
+		return err
+	}
+
+	if cacheOpts.JSON {
+		return output.FormatCacheStatsJSON(s, os.Stdout)
+	}
+	output.FormatCacheStats(s, os.Stdout)
+	return nil
+}
+
+func runCacheClear(cmd *cobra.Command, args []string) error {
+	store, err := cache.New()
+	if err != nil {
+		return err
+	}
+	removed, err := store.Clear()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Removed %d cache entries from %s\n", removed, store.Dir())
+	return nil
+}
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ohader/gh-hookmon/internal/config"
+	"github.com/ohader/gh-hookmon/internal/github"
+	"github.com/ohader/gh-hookmon/internal/stats"
+	"github.com/spf13/cobra"
+)
+
+// checkOptions holds the flags of the check subcommand
+type checkOptions struct {
+	MaxFailureRate string
+	Window         string
+	MinDeliveries  int
+	Alert          string
+	AlertURL       string
+	AlertHeaders   []string
+	AlertRetries   int
+	AlertBackoff   time.Duration
+}
+
+var checkOpts checkOptions
+
+// exitCodeViolation is the exit code used when health thresholds are breached
+const exitCodeViolation = 2
+
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check webhook health against failure-rate thresholds",
+	Long: `Evaluate the failure rate of every webhook within a time window and exit
+with a non-zero exit code (2) if any webhook exceeds the threshold.
+
+Suitable for scheduled CI jobs guarding webhook health.
+
+With --alert, an incident is opened in PagerDuty or Opsgenie for every hook
+exceeding the threshold and resolved once the hook is within the threshold
+again. The integration key is read from PAGERDUTY_ROUTING_KEY or
+OPSGENIE_API_KEY. --alert-url posts the same events as JSON documents to any
+URL instead, for alerting systems without a dedicated integration.
+
+Examples:
+  # Fail if any hook of the repository failed more than 5% in the last 24 hours
+  gh hookmon check --repo=owner/repo --max-failure-rate=5% --window=24h
+
+  # Check all hooks of an organization over the last week, ignoring quiet hooks
+  gh hookmon check --org=myorg --window=7d --min-deliveries=10
+
+  # Page the on-call engineer via PagerDuty
+  PAGERDUTY_ROUTING_KEY=... gh hookmon check --org=myorg --window=1h --alert=pagerduty
+
+  # Post alerts to an internal alerting service
+  gh hookmon check --org=myorg --alert-url=https://alerts.example.com/hookmon --alert-header="Authorization: Bearer $TOKEN"`,
+	SilenceUsage: true,
+	RunE:         runCheck,
+}
+
+func init() {
+	checkCmd.Flags().StringVar(&checkOpts.MaxFailureRate, "max-failure-rate", "5%", "Maximum failure rate per hook, e.g. 5% or 0.5%")
+	checkCmd.Flags().StringVar(&checkOpts.Window, "window", "24h", "Time window to evaluate, e.g. 6h, 24h or 7d")
+	checkCmd.Flags().IntVar(&checkOpts.MinDeliveries, "min-deliveries", 1, "Skip hooks with fewer deliveries in the window")
+	checkCmd.Flags().StringVar(&checkOpts.Alert, "alert", "", "Open and resolve incidents of failing hooks in pagerduty or opsgenie")
+	checkCmd.Flags().StringVar(&checkOpts.AlertURL, "alert-url", "", "Post a JSON document to this URL when a hook starts failing and when it recovers")
+	checkCmd.Flags().StringArrayVar(&checkOpts.AlertHeaders, "alert-header", nil, "HTTP header of --alert-url requests as \"Name: value\" (repeatable)")
+	checkCmd.Flags().IntVar(&checkOpts.AlertRetries, "alert-retries", 3, "Maximum number of retries of failed --alert-url requests")
+	checkCmd.Flags().DurationVar(&checkOpts.AlertBackoff, "alert-backoff", time.Second, "Initial wait between --alert-url retries, doubled after each attempt")
+	checkCmd.Flags().StringVar(&cfg.Filter, "filter", "", "Filter webhook URLs by pattern")
+	rootCmd.AddCommand(checkCmd)
+}
+
+func runCheck(cmd *cobra.Command, args []string) error {
+	maxRate, err := config.ParsePercentage(checkOpts.MaxFailureRate)
+	if err != nil {
+		return fmt.Errorf("validation error: --max-failure-rate: %w", err)
+	}
+	window, err := config.ParseDuration(checkOpts.Window)
+	if err != nil {
+		return fmt.Errorf("validation error: --window: %w", err)
+	}
+	if err := validateTarget(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+	alerts, err := newAlerter()
+	if err != nil {
+		return err
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	since := time.Now().Add(-window)
+	coverWindow(since)
+	deliveries, err := fetchDeliveries(client)
+	if err != nil {
+		return err
+	}
+
+	inWindow := make([]github.Delivery, 0, len(deliveries))
+	for _, d := range deliveries {
+		if !d.DeliveredAt.Before(since) {
+			inWindow = append(inWindow, d)
+		}
+	}
+	incompleteWindow(deliveries, since, "its failure rate only covers part of the window")
+
+	groups := stats.GroupBy(inWindow, stats.ByHook)
+	checked := 0
+	violations := 0
+	for _, key := range stats.SortedHookKeys(groups) {
+		summary := groups[key]
+		if summary.Deliveries < checkOpts.MinDeliveries {
+			continue
+		}
+		checked++
+		if summary.FailureRate() > maxRate {
+			violations++
+			fmt.Printf("FAIL %s hook %d %s: %d of %d deliveries failed (%.1f%%)\n",
+				key.Repository, key.HookID, displayURL(key.URL), summary.Failures, summary.Deliveries, summary.FailureRate()*100)
+			if alerts != nil {
+				alerts.failing(key, summary, checkOpts.Window, maxRate)
+			}
+		} else if alerts != nil {
+			alerts.healthy(key, summary, checkOpts.Window, maxRate)
+		}
+	}
+
+	fmt.Printf("Checked %d hooks (%d deliveries) in the last %s, max failure rate %.1f%%\n",
+		checked, len(inWindow), checkOpts.Window, maxRate*100)
+
+	var alertErr error
+	if alerts != nil {
+		alertErr = alerts.finish(checkOpts.Window)
+	}
+
+	// Violations take precedence, a failed alert is reported as a warning then
+	if violations > 0 {
+		if alertErr != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", alertErr)
+		}
+		return &ExitError{
+			Code: exitCodeViolation,
+			Err:  fmt.Errorf("%d of %d hooks exceeded the maximum failure rate", violations, checked),
+		}
+	}
+
+	if alertErr != nil {
+		return alertErr
+	}
+	if cfg.Verbose {
+		fmt.Fprintln(os.Stderr, "All hooks are within the failure-rate threshold")
+	}
+	return nil
+}
+
+// displayURL returns the URL or a placeholder if it is unknown
+func displayURL(url string) string {
+	if url == "" {
+		return "-"
+	}
+	return url
+}
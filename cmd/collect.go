@@ -0,0 +1,220 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ohader/gh-hookmon/internal/collect"
+	"github.com/ohader/gh-hookmon/internal/github"
+	"github.com/ohader/gh-hookmon/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// collectOptions holds the flags of the collect subcommand
+type collectOptions struct {
+	State        string
+	Delta        bool
+	StatsD       string
+	StatsDPrefix string
+	StatsDFlavor string
+}
+
+var collectOpts collectOptions
+
+// exitCodeFetchErrors is the exit code used by collect when repositories or hooks could not be fetched,
+// and with --on-forbidden=fail when repositories were denied
+const exitCodeFetchErrors = 4
+
+var collectCmd = &cobra.Command{
+	Use:   "collect",
+	Short: "Record new deliveries in the history database, designed for cron",
+	Long: `Fetch the deliveries of all hooks, record them in the history database and
+print a single summary line of the deliveries that are new since the last run.
+
+The hooks and the newest delivery seen of each are kept in the --state file,
+so each run only reports what happened since the previous one. With --delta,
+the summary is replaced by one line per newly failed delivery, recovered,
+added and removed hook, and nothing is printed if nothing changed.
+
+With --statsd, the new deliveries, failures and latencies per repository, hook
+and event are also sent to a StatsD or DogStatsD server, e.g. the Datadog agent.
+
+Exit codes:
+  0  all new deliveries succeeded
+  2  new failed deliveries
+  3  --deadline exceeded, results are partial
+  4  repositories or hooks could not be fetched, or the history could not be recorded
+  1  any other error
+
+Examples:
+  # Collect every 15 minutes (crontab entry)
+  */15 * * * * gh hookmon collect --org=myorg --state=/var/lib/hookmon/state.json
+
+  # Only report changes, e.g. to mail them from cron
+  gh hookmon collect --org=myorg --state=state.json --delta
+
+  # Send metrics to the local Datadog agent
+  gh hookmon collect --org=myorg --state=state.json --statsd=127.0.0.1:8125
+
+  # Collect a single repository with a time limit
+  gh hookmon collect --repo=owner/repo --state=state.json --deadline=5m`,
+	SilenceUsage: true,
+	RunE:         runCollect,
+}
+
+func init() {
+	collectCmd.Flags().StringVar(&collectOpts.State, "state", "", "State file remembering the hooks and the newest delivery seen of each (required)")
+	collectCmd.Flags().BoolVar(&collectOpts.Delta, "delta", false, "Only report newly failed deliveries and recovered, added and removed hooks")
+	collectCmd.Flags().StringVar(&collectOpts.StatsD, "statsd", "", "Send delivery metrics to the StatsD server at HOST:PORT (UDP)")
+	collectCmd.Flags().StringVar(&collectOpts.StatsDPrefix, "statsd-prefix", "hookmon", "Prefix of the metric names")
+	collectCmd.Flags().StringVar(&collectOpts.StatsDFlavor, "statsd-flavor", "dogstatsd", "Wire format: dogstatsd (tags) or statsd (tag values in the metric name)")
+	collectCmd.Flags().StringVar(&cfg.Filter, "filter", "", "Filter webhook URLs by pattern")
+	collectCmd.MarkFlagRequired("state")
+	rootCmd.AddCommand(collectCmd)
+}
+
+func runCollect(cmd *cobra.Command, args []string) error {
+	if err := requireOnline("collect"); err != nil {
+		return err
+	}
+	// Recording the fetched deliveries is the purpose of collect
+	cfg.RecordHistory = true
+	if err := validateTarget(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+
+	metrics, err := newStatsD()
+	if err != nil {
+		return err
+	}
+	if metrics != nil {
+		defer metrics.Close()
+	}
+
+	state, err := collect.Load(collectOpts.State)
+	if err != nil {
+		return err
+	}
+
+	// Fail early if the history cannot be opened, recording itself happens while fetching
+	if _, err := openHistory(); err != nil {
+		return err
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+	if err := requireFeature(client, github.FeatureHookDeliveries); err != nil {
+		return err
+	}
+
+	// Hooks of repositories that could not be scanned must not be reported as removed
+	var mu sync.Mutex
+	scanned := map[string]bool{}
+	hooks, err := scanTargets(client, func(client *github.Client, repo string) ([]hookDeliveries, error) {
+		result, err := fetchRepoHookDeliveries(client, repo)
+		if err == nil {
+			mu.Lock()
+			scanned[repo] = true
+			mu.Unlock()
+		}
+		return result, err
+	})
+	if err != nil {
+		return err
+	}
+	// Hooks whose deliveries could not be listed are left out, their repositories were only partly scanned
+	complete := make(map[string]bool, len(scanned))
+	failed := diag.failedRepositories(output.IssueHookFailed)
+	for repo := range scanned {
+		complete[repo] = !failed[repo]
+	}
+
+	observations := make([]collect.Observation, len(hooks))
+	for i, h := range hooks {
+		observations[i] = collect.Observation{Repository: h.Repository, Hook: h.Hook, Deliveries: h.Deliveries}
+	}
+	changes := state.Update(observations, complete, cfg.Filter)
+	newFailures := len(changes.NewFailures)
+	fetchErrors := diag.failures()
+
+	summary := fmt.Sprintf("%s %s: %d new deliveries, %d new failures from %d hooks in %d repositories, %d fetch errors",
+		time.Now().Format(time.RFC3339), collectTarget(), len(changes.NewDeliveries), newFailures, len(hooks), len(scanned), fetchErrors)
+
+	// Without recorded deliveries the state is kept, so the next run reports them again
+	recordErr := historyRecordError()
+	if recordErr != nil {
+		summary += fmt.Sprintf(", failed to record history: %v", recordErr)
+	} else if err := state.Save(collectOpts.State); err != nil {
+		return err
+	}
+	if metrics != nil {
+		m := collectMetrics{Hooks: len(hooks), Repositories: len(scanned), FetchErrors: fetchErrors}
+		// Deliveries are reported again by the next run if they were not recorded, they must not be counted twice
+		if recordErr == nil {
+			m.Deliveries = changes.NewDeliveries
+		}
+		if err := emitCollectMetrics(metrics, m); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+	if collectOpts.Delta {
+		printCollectChanges(changes)
+		if recordErr != nil {
+			fmt.Printf("ERROR failed to record history: %v\n", recordErr)
+		}
+	} else {
+		fmt.Println(summary)
+	}
+
+	// The summary line explains the exit code, cobra must not repeat it as an error
+	switch {
+	case newFailures > 0:
+		cmd.SilenceErrors = true
+		return &ExitError{Code: exitCodeViolation, Err: fmt.Errorf("%d new failed deliveries", newFailures)}
+	case fetchErrors > 0 || recordErr != nil:
+		cmd.SilenceErrors = true
+		return &ExitError{Code: exitCodeFetchErrors, Err: fmt.Errorf("%d fetch errors", fetchErrors)}
+	}
+	return nil
+}
+
+// printCollectChanges prints one line per newly failed delivery, recovered, added and removed hook
+func printCollectChanges(changes collect.Changes) {
+	for _, d := range changes.NewFailures {
+		status := strconv.Itoa(d.StatusCode)
+		if d.Status != "" {
+			status += " " + d.Status
+		}
+		fmt.Printf("FAILED %s hook %d %s: delivery %d (%s) at %s returned %s\n", d.Repository, d.HookID, displayURL(d.URL),
+			d.ID, d.Event, d.DeliveredAt.Format(time.RFC3339), status)
+	}
+	for _, h := range changes.Recovered {
+		fmt.Printf("RECOVERED %s hook %d %s\n", h.Repository, h.ID, displayURL(h.URL))
+	}
+	for _, h := range changes.Added {
+		fmt.Printf("ADDED %s hook %d %s\n", h.Repository, h.ID, displayURL(h.URL))
+	}
+	for _, h := range changes.Removed {
+		fmt.Printf("REMOVED %s hook %d %s\n", h.Repository, h.ID, displayURL(h.URL))
+	}
+}
+
+// collectTarget returns the organization, repository or repository list being collected
+func collectTarget() string {
+	if cfg.Org != "" {
+		return cfg.Org
+	}
+	if cfg.ReposFile != "" {
+		return cfg.ReposFile
+	}
+	if len(cfg.Repos) > 0 {
+		return strings.Join(cfg.Repos, ",")
+	}
+	return cfg.Repo
+}
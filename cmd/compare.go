@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ohader/gh-hookmon/internal/config"
+	"github.com/ohader/gh-hookmon/internal/github"
+	"github.com/ohader/gh-hookmon/internal/output"
+	"github.com/ohader/gh-hookmon/internal/stats"
+	"github.com/spf13/cobra"
+)
+
+// compareOptions holds the flags of the compare-orgs subcommand
+type compareOptions struct {
+	Window string
+	JSON   bool
+}
+
+var compareOpts compareOptions
+
+var compareCmd = &cobra.Command{
+	Use:   "compare-orgs ORG_A ORG_B",
+	Short: "Compare the webhook health of two organizations side by side",
+	Long: `Scan the repository webhooks of two organizations and compare them side by
+side: the number of webhooks and target URLs, deliveries, failure rates and
+latency. Target URLs receiving webhooks of both organizations are compared
+one by one, those of only one organization are listed, which makes it the
+checklist of organization migrations and consolidations.
+
+Examples:
+  # Compare the organization being migrated with its new home
+  gh hookmon compare-orgs old-org new-org
+
+  # Only the deliveries of the last 24 hours, as JSON
+  gh hookmon compare-orgs old-org new-org --window=24h --json`,
+	Args:         cobra.ExactArgs(2),
+	SilenceUsage: true,
+	RunE:         runCompare,
+}
+
+func init() {
+	compareCmd.Flags().StringVar(&compareOpts.Window, "window", "", "Only include deliveries of this time window, e.g. 24h or 7d (default: all fetched)")
+	compareCmd.Flags().BoolVar(&compareOpts.JSON, "json", false, "Output in JSON format")
+	compareCmd.Flags().StringVar(&cfg.Filter, "filter", "", "Filter webhook URLs by pattern")
+	rootCmd.AddCommand(compareCmd)
+}
+
+func runCompare(cmd *cobra.Command, args []string) error {
+	if args[0] == args[1] {
+		return fmt.Errorf("validation error: compare two different organizations, got %s twice", args[0])
+	}
+	// The organization of a profile is replaced by the arguments, an explicit --org is a mistake
+	if cmd.Flags().Changed("org") || cfg.Team != "" || cfg.Repo != "" || len(cfg.Repos) > 0 {
+		return fmt.Errorf("validation error: compare-orgs scans the organizations given as arguments, --org, --team, --repo, --repos and --repos-file do not apply")
+	}
+	var window time.Duration
+	if compareOpts.Window != "" {
+		var err error
+		if window, err = config.ParseDuration(compareOpts.Window); err != nil {
+			return fmt.Errorf("validation error: --window: %w", err)
+		}
+	}
+
+	healths := make([]*stats.OrgHealth, len(args))
+	for i, org := range args {
+		health, err := scanOrgHealth(org, window)
+		if err != nil {
+			return fmt.Errorf("%s: %w", org, err)
+		}
+		healths[i] = health
+	}
+
+	if compareOpts.JSON {
+		return output.FormatCompareJSON(healths[0], healths[1], os.Stdout)
+	}
+	output.FormatCompareTable(healths[0], healths[1], os.Stdout)
+	return nil
+}
+
+// scanOrgHealth scans the webhooks of an organization's repositories together with their deliveries
+// Organizations are scanned one after the other with a client of their own, e.g. for their GitHub App installations
+func scanOrgHealth(org string, window time.Duration) (*stats.OrgHealth, error) {
+	defer func(previous string) { cfg.Org = previous }(cfg.Org)
+	cfg.Org = org
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return nil, err
+	}
+	if err := requireFeature(client, github.FeatureHookDeliveries); err != nil {
+		return nil, err
+	}
+	withDeliveries, err := scanTargets(client, fetchRepoHookDeliveries)
+	if err != nil {
+		return nil, err
+	}
+
+	since := time.Now().Add(-window)
+	var hooks []github.RepoHook
+	var deliveries []github.Delivery
+	for _, h := range withDeliveries {
+		hooks = append(hooks, github.RepoHook{Repository: h.Repository, Hook: h.Hook})
+		for _, d := range h.Deliveries {
+			if window == 0 || !d.DeliveredAt.Before(since) {
+				deliveries = append(deliveries, d)
+			}
+		}
+	}
+	return stats.NewOrgHealth(org, hooks, deliveries), nil
+}
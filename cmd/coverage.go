@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ohader/gh-hookmon/internal/github"
+	"github.com/ohader/gh-hookmon/internal/output"
+	"github.com/ohader/gh-hookmon/internal/stats"
+	"github.com/spf13/cobra"
+)
+
+// coverageOptions holds the flags of the coverage subcommand
+type coverageOptions struct {
+	Activity bool
+	GapsOnly bool
+	JSON     bool
+}
+
+var coverageOpts coverageOptions
+
+var coverageCmd = &cobra.Command{
+	Use:   "coverage",
+	Short: "Compare subscribed events with the events actually delivered",
+	Long: `Report per hook which subscribed events were never delivered and which
+delivered events the hook is not subscribed to, helping to find over-broad
+or under-scoped hook configurations.
+
+With --activity the repository's recent activity is fetched as well, and
+subscribed events with activity but without any delivery are reported as
+missed. Only the most recent deliveries of each hook are considered.
+
+Examples:
+  # Show the event coverage of all hooks of an organization
+  gh hookmon coverage --org=myorg
+
+  # Only show hooks with gaps, taking repository activity into account
+  gh hookmon coverage --repo=owner/repo --activity --gaps-only`,
+	SilenceUsage: true,
+	RunE:         runCoverage,
+}
+
+func init() {
+	coverageCmd.Flags().BoolVar(&coverageOpts.Activity, "activity", false, "Compare against repository activity (one additional request per repository)")
+	coverageCmd.Flags().BoolVar(&coverageOpts.GapsOnly, "gaps-only", false, "Only show hooks with never delivered, missed or unexpected events")
+	coverageCmd.Flags().BoolVar(&coverageOpts.JSON, "json", false, "Output in JSON format")
+	coverageCmd.Flags().StringVar(&cfg.Filter, "filter", "", "Filter webhook URLs by pattern")
+	rootCmd.AddCommand(coverageCmd)
+}
+
+func runCoverage(cmd *cobra.Command, args []string) error {
+	if coverageOpts.Activity {
+		if err := requireOnline("--activity"); err != nil {
+			return err
+		}
+	}
+	if err := validateTarget(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	if err := requireFeature(client, github.FeatureHookDeliveries); err != nil {
+		return err
+	}
+
+	coverages, err := scanTargets(client, func(client *github.Client, repo string) ([]stats.EventCoverage, error) {
+		hooks, err := fetchRepoHookDeliveries(client, repo)
+		if err != nil || len(hooks) == 0 {
+			return nil, err
+		}
+
+		var activity []github.RepoEvent
+		if coverageOpts.Activity {
+			activity, err = client.ListRepoEvents(repo)
+			if err != nil {
+				diag.warn(output.Issue{Kind: output.IssueActivityFailed, Repository: repo, Message: err.Error()})
+			}
+		}
+
+		result := make([]stats.EventCoverage, 0, len(hooks))
+		for _, h := range hooks {
+			coverage := stats.CoverageOf(repo, h.Hook, h.Deliveries, activity)
+			if coverageOpts.GapsOnly && !coverage.HasGaps() {
+				continue
+			}
+			result = append(result, coverage)
+		}
+		return result, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if coverageOpts.JSON {
+		return output.FormatCoverageJSON(coverages, os.Stdout)
+	}
+	output.FormatCoverage(coverages, os.Stdout)
+	return nil
+}
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ohader/gh-hookmon/internal/history"
+	"github.com/ohader/gh-hookmon/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// dbOptions holds the flags of the db subcommands
+type dbOptions struct {
+	JSON bool
+}
+
+var dbOpts dbOptions
+
+var dbCmd = &cobra.Command{
+	Use:   "db",
+	Short: "Query and maintain the local history database",
+	Long: `Query and maintain the local SQLite database of webhook deliveries.
+
+collect, and every run with --record, records the deliveries it fetches in the history
+database, so deliveries remain queryable after GitHub stopped returning them.
+
+Examples:
+  # Failed deliveries of an organization since 2024, including deliveries GitHub no longer returns
+  gh hookmon db query --org=myorg --failed --since=2024-01-01
+
+  # All recorded deliveries of a hook target, as JSON
+  gh hookmon db query --filter=ci.example.com --json
+
+  # Show the size and time span of the history
+  gh hookmon db stats
+
+  # Compact the database file
+  gh hookmon db vacuum`,
+}
+
+var dbQueryCmd = &cobra.Command{
+	Use:   "query",
+	Short: "List recorded deliveries with the filter, sort and output flags of the main command",
+	Long: `List the deliveries recorded in the history database.
+
+Supports the same filter, sort and output flags as the main command. --org and --repo
+are optional and restrict the result to an organization or repository.`,
+	SilenceUsage: true,
+	RunE:         runDBQuery,
+}
+
+var dbStatsCmd = &cobra.Command{
+	Use:          "stats",
+	Short:        "Summarize the recorded deliveries",
+	SilenceUsage: true,
+	RunE:         runDBStats,
+}
+
+var dbVacuumCmd = &cobra.Command{
+	Use:          "vacuum",
+	Short:        "Compact the database file",
+	SilenceUsage: true,
+	RunE:         runDBVacuum,
+}
+
+func init() {
+	addDeliveryListFlags(dbQueryCmd.Flags())
+	dbStatsCmd.Flags().BoolVar(&dbOpts.JSON, "json", false, "Output in JSON format")
+
+	dbCmd.AddCommand(dbQueryCmd, dbStatsCmd, dbVacuumCmd)
+	rootCmd.AddCommand(dbCmd)
+}
+
+func runDBQuery(cmd *cobra.Command, args []string) error {
+	filters, err := parseDeliveryFilters(cmd)
+	if err != nil {
+		return err
+	}
+	if err := cfg.ValidateOptional(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+
+	store, err := openHistory()
+	if err != nil {
+		return err
+	}
+
+	deliveries, err := store.Deliveries(history.Query{
+		Org:   cfg.Org,
+		Repo:  cfg.Repo,
+		Repos: cfg.Repos,
+		Since: cfg.Since,
+		Until: cfg.Until,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Recorded deliveries carry their target URL, no details need to be fetched
+	return listDeliveries(deliveries, filters, nil)
+}
+
+func runDBStats(cmd *cobra.Command, args []string) error {
+	store, err := openHistory()
+	if err != nil {
+		return err
+	}
+	s, err := store.Stats()
+	if err != nil {
+		return err
+	}
+
+	if dbOpts.JSON {
+		return output.FormatHistoryStatsJSON(s, os.Stdout)
+	}
+	output.FormatHistoryStats(s, os.Stdout)
+	return nil
+}
+
+func runDBVacuum(cmd *cobra.Command, args []string) error {
+	store, err := openHistory()
+	if err != nil {
+		return err
+	}
+	before, after, err := store.Vacuum()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Compacted %s from %s to %s\n", store.Path(), output.FormatBytes(before), output.FormatBytes(after))
+	return nil
+}
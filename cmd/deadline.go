@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/ohader/gh-hookmon/internal/output"
+)
+
+// exitCodeDeadline is the exit code used when --deadline cut the run short and results are partial
+const exitCodeDeadline = 3
+
+// runDeadline bounds the total runtime of a command (--deadline)
+// Work that has not started when the deadline passes is skipped and in-flight API requests are cancelled
+type runDeadline struct {
+	limit  time.Duration
+	ctx    context.Context
+	cancel context.CancelFunc
+	cut    atomic.Bool // Set once skipped or cancelled work made the results partial
+}
+
+var deadline = &runDeadline{ctx: context.Background()}
+
+// start begins the countdown, a zero limit never expires
+func (d *runDeadline) start(limit time.Duration) {
+	if limit <= 0 {
+		return
+	}
+	d.limit = limit
+	d.ctx, d.cancel = context.WithTimeout(context.Background(), limit)
+}
+
+// stop releases the timer of the deadline
+func (d *runDeadline) stop() {
+	if d.cancel != nil {
+		d.cancel()
+	}
+}
+
+// exceeded reports whether the deadline has passed
+func (d *runDeadline) exceeded() bool {
+	return d.limit > 0 && d.ctx.Err() != nil
+}
+
+// interrupted reports whether err is an API request cancelled by the deadline
+func (d *runDeadline) interrupted(err error) bool {
+	return d.exceeded() && errors.Is(err, context.DeadlineExceeded)
+}
+
+// warnPartial reports work that was skipped because the deadline passed
+// The warning is printed even without --verbose, as the results are incomplete
+func (d *runDeadline) warnPartial(skipped, total int, unit string) {
+	d.cut.Store(true)
+	message := fmt.Sprintf("--deadline of %s exceeded, %d of %d %s were not processed, results are partial", d.limit, skipped, total, unit)
+	warnAlways(output.Issue{Kind: output.IssueDeadline, Message: message})
+}
+
+// exitError turns the outcome of a run cut short by the deadline into the deadline exit code
+// Errors that already carry an exit code keep it
+func (d *runDeadline) exitError(err error) error {
+	if !d.cut.Load() && (err == nil || !d.exceeded()) {
+		return err
+	}
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		return err
+	}
+	if err == nil {
+		err = fmt.Errorf("--deadline of %s exceeded, results are partial", d.limit)
+	}
+	return &ExitError{Code: exitCodeDeadline, Err: err}
+}
+
+// transport binds every API request to the deadline
+// go-gh issues its requests without a context, so the deadline context replaces it
+func (d *runDeadline) transport(base http.RoundTripper) http.RoundTripper {
+	if d.limit <= 0 {
+		return base
+	}
+	return &deadlineTransport{base: base, ctx: d.ctx}
+}
+
+type deadlineTransport struct {
+	base http.RoundTripper
+	ctx  context.Context
+}
+
+func (t *deadlineTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.base.RoundTrip(req.WithContext(t.ctx))
+}
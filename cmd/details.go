@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/ohader/gh-hookmon/internal/github"
+)
+
+// responseSummaryBytes is how much of the response body is kept as summary
+const responseSummaryBytes = 200
+
+// withDetails replaces deliveries with their detailed version, e.g. with the target URL at the time of the delivery
+// Deliveries whose details cannot be fetched are kept with the target URL of their hook
+func withDetails(deliveries []github.Delivery, fetchDetails func([]github.Delivery) ([]github.Delivery, error)) ([]github.Delivery, error) {
+	var missing []github.Delivery
+	for _, d := range deliveries {
+		if d.PayloadSize == 0 {
+			missing = append(missing, d)
+		}
+	}
+	if len(missing) == 0 {
+		return deliveries, nil
+	}
+
+	detailed, err := fetchDetails(missing)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[int]github.Delivery, len(detailed))
+	for _, d := range detailed {
+		byID[d.ID] = d
+	}
+	result := make([]github.Delivery, len(deliveries))
+	for i, d := range deliveries {
+		result[i] = d
+		if v, ok := byID[d.ID]; ok {
+			result[i] = v
+		}
+	}
+	return result, nil
+}
+
+// withTargetURLs fetches the details of the deliveries without a target URL, e.g. of hooks without config.url
+// Deliveries with one keep the URL of their hook, so the URL filter costs no API request for them
+// Deliveries whose details cannot be fetched are dropped, as the URL filter cannot be decided for them
+func withTargetURLs(deliveries []github.Delivery, fetchDetails func([]github.Delivery) ([]github.Delivery, error)) ([]github.Delivery, error) {
+	var missing []github.Delivery
+	for _, d := range deliveries {
+		if d.URL == "" {
+			missing = append(missing, d)
+		}
+	}
+	if len(missing) == 0 {
+		return deliveries, nil
+	}
+
+	detailed, err := fetchDetails(missing)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[int]github.Delivery, len(detailed))
+	for _, d := range detailed {
+		byID[d.ID] = d
+	}
+	result := make([]github.Delivery, 0, len(deliveries))
+	for _, d := range deliveries {
+		if d.URL != "" {
+			result = append(result, d)
+		} else if v, ok := byID[d.ID]; ok {
+			result = append(result, v)
+		}
+	}
+	return result, nil
+}
+
+// payloadSize returns the size of a request payload encoded as JSON
+func payloadSize(payload interface{}) int {
+	if payload == nil {
+		return 0
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return 0
+	}
+	return len(data)
+}
+
+// responseSummary returns the beginning of a response body on a single line, redacted unless --no-redact is set
+func responseSummary(body string) string {
+	return strings.Join(strings.Fields(responseExcerpt(outputText(body), responseSummaryBytes)), " ")
+}
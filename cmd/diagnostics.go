@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/ohader/gh-hookmon/internal/github"
+	"github.com/ohader/gh-hookmon/internal/output"
+)
+
+// diagnostics collects non-fatal problems encountered during a run
+// Workers report concurrently, so access is guarded by a mutex
+type diagnostics struct {
+	mu       sync.Mutex
+	warnings []output.Issue
+	errors   []output.Issue
+	skipped  []output.Issue
+}
+
+var diag = &diagnostics{}
+
+// warn records a condition that may make the result incomplete
+func (d *diagnostics) warn(issue output.Issue) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.warnings = append(d.warnings, issue)
+	if cfg.Verbose {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", issue.Message)
+	}
+}
+
+// warnAlways records a warning that is printed even without --verbose, e.g. as the results are incomplete
+func warnAlways(issue output.Issue) {
+	diag.warn(issue)
+	if !cfg.Verbose {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", issue.Message)
+	}
+}
+
+// fail records a failed operation whose data is missing from the result
+func (d *diagnostics) fail(issue output.Issue) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.errors = append(d.errors, issue)
+	if cfg.Verbose {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", issue.Message)
+	}
+}
+
+// skip records a repository left out of the result on purpose, e.g. for missing permissions
+func (d *diagnostics) skip(issue output.Issue) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.skipped = append(d.skipped, issue)
+}
+
+// failures returns the number of failed operations recorded so far
+func (d *diagnostics) failures() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.errors)
+}
+
+// failedRepositories returns the repositories with a recorded failure of any of the given kinds
+func (d *diagnostics) failedRepositories(kinds ...string) map[string]bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	repos := make(map[string]bool)
+	for _, issue := range d.errors {
+		for _, kind := range kinds {
+			if issue.Kind == kind {
+				repos[issue.Repository] = true
+			}
+		}
+	}
+	return repos
+}
+
+// report builds the JSON envelope for the given deliveries
+func (d *diagnostics) report(deliveries []github.Delivery) output.Report {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return output.Report{
+		FormatVersion: cfg.FormatVersion,
+		Deliveries:    deliveries,
+		Warnings:      append([]output.Issue(nil), d.warnings...),
+		Errors:        append([]output.Issue(nil), d.errors...),
+		Skipped:       append([]output.Issue(nil), d.skipped...),
+	}
+}
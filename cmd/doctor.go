@@ -0,0 +1,298 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ohader/gh-hookmon/internal/config"
+	"github.com/ohader/gh-hookmon/internal/github"
+	"github.com/ohader/gh-hookmon/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// doctorOptions holds the flags of the doctor subcommand
+type doctorOptions struct {
+	JSON bool
+}
+
+var doctorOpts doctorOptions
+
+// doctorConfigErr is the error of loading the configuration, reported as a check instead of failing the command
+var doctorConfigErr error
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose authentication, token scopes and API connectivity",
+	Long: `Check the setup gh hookmon depends on and print how to fix each problem:
+
+  - the configuration file can be loaded
+  - the gh CLI login, token or GitHub App credentials authenticate
+  - the token has the admin:repo_hook and admin:org_hook scopes
+  - the API of the host is reachable
+  - the server provides the webhook deliveries API (GitHub Enterprise Server 3.2 or later)
+
+Exits with exit code 1 if any check failed, warnings do not fail.
+
+Examples:
+  # Check the setup of the default host
+  gh hookmon doctor
+
+  # Check a GitHub Enterprise Server profile
+  gh hookmon doctor --profile=ghes`,
+	SilenceUsage: true,
+	// Configuration errors are reported as a failed check
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		doctorConfigErr = preRun(cmd, args)
+		return nil
+	},
+	RunE: runDoctor,
+}
+
+func init() {
+	doctorCmd.Flags().BoolVar(&doctorOpts.JSON, "json", false, "Output in JSON format")
+	rootCmd.AddCommand(doctorCmd)
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	if err := requireOnline("doctor"); err != nil {
+		return err
+	}
+
+	checks := []output.DoctorCheck{checkConfigFile()}
+	if doctorConfigErr == nil {
+		checks = append(checks, checkConnection()...)
+	}
+
+	if doctorOpts.JSON {
+		if err := output.FormatDoctorJSON(checks, os.Stdout); err != nil {
+			return err
+		}
+	} else {
+		output.FormatDoctor(checks, os.Stdout, !unicodeLocale())
+	}
+
+	failed := 0
+	for _, check := range checks {
+		if check.Status == output.CheckFailed {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return &ExitError{Code: 1, Err: fmt.Errorf("%d of %d checks failed", failed, len(checks))}
+	}
+	return nil
+}
+
+// checkConfigFile reports the configuration file and whether it could be loaded
+func checkConfigFile() output.DoctorCheck {
+	check := output.DoctorCheck{Name: "Configuration"}
+	path := configPath
+	if path == "" {
+		path, _ = config.DefaultConfigPath()
+	}
+	switch {
+	case doctorConfigErr != nil:
+		check.Status = output.CheckFailed
+		check.Detail = doctorConfigErr.Error()
+		check.Fix = "Correct the reported setting, or point --config to a valid configuration file"
+	case path == "":
+		check.Status = output.CheckOK
+		check.Detail = "no configuration directory, using defaults"
+	default:
+		check.Status = output.CheckOK
+		if _, err := os.Stat(path); err != nil {
+			check.Detail = path + " does not exist, using defaults"
+		} else {
+			check.Detail = path
+		}
+	}
+	return check
+}
+
+// checkConnection checks authentication, token scopes and the server, later checks are skipped once authentication failed
+func checkConnection() []output.DoctorCheck {
+	auth := output.DoctorCheck{Name: "Authentication"}
+	source := authSource()
+	client, err := newClient()
+	if err != nil {
+		auth.Status = output.CheckFailed
+		auth.Detail = strings.SplitN(err.Error(), "\n", 2)[0]
+		auth.Fix = authFix()
+		return []output.DoctorCheck{auth}
+	}
+
+	// Installation tokens belong to no user, minting one already proved the credentials
+	if cfg.AppID != 0 {
+		auth.Status = output.CheckOK
+		auth.Detail = fmt.Sprintf("as an installation of GitHub App %d on %s", cfg.AppID, client.Host())
+	} else {
+		login, err := client.CurrentUser()
+		if err != nil {
+			auth.Status = output.CheckFailed
+			auth.Detail = err.Error()
+			if github.IsUnauthorized(err) {
+				auth.Fix = authFix()
+			} else {
+				auth.Fix = connectionFix()
+			}
+			return []output.DoctorCheck{auth}
+		}
+		auth.Status = output.CheckOK
+		auth.Detail = fmt.Sprintf("as %s on %s via %s", login, client.Host(), source)
+	}
+
+	checks := []output.DoctorCheck{auth, checkTokenScopes(client), checkAPI(client)}
+	return append(checks, checkServer(client))
+}
+
+// authSource describes where the credentials of the client come from
+func authSource() string {
+	switch {
+	case cfg.AppID != 0:
+		return "GitHub App credentials"
+	case cfg.Token != "":
+		if cfg.Profile != "" {
+			return "the token of profile " + cfg.Profile
+		}
+		return "--token"
+	}
+	for _, name := range []string{"GH_TOKEN", "GITHUB_TOKEN"} {
+		if os.Getenv(name) != "" {
+			return name
+		}
+	}
+	return "the gh CLI login"
+}
+
+// authFix suggests how to renew the credentials of the configured source
+func authFix() string {
+	if cfg.AppID != 0 {
+		return "Check the app ID, private key and installation of the GitHub App, and that the app is installed for the organization"
+	}
+	if apiToken() != "" {
+		return fmt.Sprintf("Replace the token of %s, it is invalid, expired or revoked", authSource())
+	}
+	return "Run 'gh auth login' to authenticate, or 'gh auth status' to inspect the current login"
+}
+
+// connectionFix suggests how to resolve network problems
+func connectionFix() string {
+	return "Check the network connection and HTTPS_PROXY, pass the certificates of an inspecting proxy with --ca-bundle, and the host of a GitHub Enterprise Server with GH_HOST or a --profile"
+}
+
+// checkTokenScopes verifies that the token may read repository and organization webhooks
+// Missing organization scopes only affect --org-hooks, so they are a warning
+func checkTokenScopes(client *github.Client) output.DoctorCheck {
+	check := output.DoctorCheck{Name: "Token scopes"}
+	scopes, reported, err := client.TokenScopes()
+	switch {
+	case err != nil:
+		check.Status = output.CheckWarning
+		check.Detail = err.Error()
+		return check
+	case cfg.AppID != 0:
+		check.Status = output.CheckOK
+		check.Detail = "GitHub Apps need the webhooks repository permission, and the webhooks organization permission for --org-hooks"
+		return check
+	case !reported:
+		check.Status = output.CheckWarning
+		check.Detail = "not reported, fine-grained tokens cannot be checked upfront"
+		check.Fix = "Grant the token read access to the webhooks repository permission, and the webhooks organization permission for --org-hooks"
+		return check
+	}
+
+	var missing []github.ScopeRequirement
+	for _, required := range []github.ScopeRequirement{github.ScopeRepoHooks, github.ScopeOrgHooks} {
+		if !required.SatisfiedBy(scopes) {
+			missing = append(missing, required)
+		}
+	}
+	granted := strings.Join(scopes, ", ")
+	if granted == "" {
+		granted = "none"
+	}
+	if len(missing) == 0 {
+		check.Status = output.CheckOK
+		check.Detail = "granted " + granted
+		return check
+	}
+
+	names := make([]string, len(missing))
+	for i, scope := range missing {
+		names[i] = scope.Name
+	}
+	check.Status = output.CheckFailed
+	check.Detail = fmt.Sprintf("missing %s (granted: %s)", strings.Join(names, ", "), granted)
+	if len(missing) == 1 && missing[0].Name == github.ScopeOrgHooks.Name {
+		// Repository webhooks work, organization webhooks do not
+		check.Status = output.CheckWarning
+		check.Detail = fmt.Sprintf("missing %s, needed for --org-hooks only (granted: %s)", github.ScopeOrgHooks.Name, granted)
+	}
+	check.Fix = scopeFix(client, names)
+	return check
+}
+
+// scopeFix suggests how to obtain a token with the missing scopes
+func scopeFix(client *github.Client, missing []string) string {
+	if apiToken() != "" {
+		noun := "scope"
+		if len(missing) > 1 {
+			noun = "scopes"
+		}
+		return fmt.Sprintf("Create a token with the %s %s and pass it with %s", strings.Join(missing, " and "), noun, authSource())
+	}
+	return fmt.Sprintf("Run 'gh auth refresh -h %s -s %s'", client.Host(), strings.Join(missing, ","))
+}
+
+// checkAPI measures the response time of the API and shows the remaining budget
+func checkAPI(client *github.Client) output.DoctorCheck {
+	check := output.DoctorCheck{Name: "API"}
+	start := time.Now()
+	_, err := client.GetServerInfo()
+	if err != nil {
+		check.Status = output.CheckFailed
+		check.Detail = err.Error()
+		check.Fix = connectionFix()
+		return check
+	}
+
+	check.Status = output.CheckOK
+	check.Detail = fmt.Sprintf("%s reachable in %s", client.Host(), time.Since(start).Round(time.Millisecond))
+	if budget, ok := rateLimits.Get(rateLimitResource); ok {
+		check.Detail += ", " + formatRateLimit(budget)
+		if budget.Remaining == 0 {
+			check.Status = output.CheckWarning
+			check.Fix = "Wait until the API budget resets, or use a GitHub App, whose budget is separate"
+		}
+	}
+	return check
+}
+
+// checkServer verifies that the server provides the webhook deliveries API
+func checkServer(client *github.Client) output.DoctorCheck {
+	check := output.DoctorCheck{Name: "Server"}
+	info, err := client.GetServerInfo()
+	if err != nil {
+		check.Status = output.CheckWarning
+		check.Detail = "version unknown, " + err.Error()
+		return check
+	}
+	if !info.IsEnterprise() {
+		check.Status = output.CheckOK
+		check.Detail = "GitHub.com, all features available"
+		return check
+	}
+
+	check.Detail = "GitHub Enterprise Server " + info.InstalledVersion
+	if err := info.Require(github.FeatureHookDeliveries); err != nil {
+		check.Status = output.CheckFailed
+		check.Detail += ", " + err.Error()
+		check.Fix = fmt.Sprintf("Upgrade the server to %s or later, until then only the hooks subcommand works", github.FeatureHookDeliveries.MinVersion)
+		return check
+	}
+	check.Status = output.CheckOK
+	check.Detail += ", webhook deliveries API available"
+	return check
+}
@@ -0,0 +1,15 @@
+package cmd
+
+// ExitError is returned when a command needs a specific process exit code
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
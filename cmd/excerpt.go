@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"unicode/utf8"
+
+	"github.com/ohader/gh-hookmon/internal/filter"
+	"github.com/ohader/gh-hookmon/internal/github"
+)
+
+// withResponseExcerpts adds the response excerpt to failed deliveries that do not have one yet
+// Deliveries whose details cannot be fetched are kept without an excerpt
+func withResponseExcerpts(deliveries []github.Delivery, fetchDetails func([]github.Delivery) ([]github.Delivery, error)) ([]github.Delivery, error) {
+	var missing []github.Delivery
+	for _, d := range deliveries {
+		if filter.IsFailed(d.StatusCode) && d.ResponseExcerpt == "" {
+			missing = append(missing, d)
+		}
+	}
+	if len(missing) == 0 {
+		// Excerpts fetched together with the target URLs for --filter still need to be recorded
+		recordResponseExcerpts(deliveries)
+		return deliveries, nil
+	}
+
+	detailed, err := fetchDetails(missing)
+	if err != nil {
+		return nil, err
+	}
+
+	excerpts := make(map[int]string, len(detailed))
+	for _, d := range detailed {
+		excerpts[d.ID] = d.ResponseExcerpt
+	}
+	result := make([]github.Delivery, len(deliveries))
+	for i, d := range deliveries {
+		result[i] = d
+		if excerpt, ok := excerpts[d.ID]; ok && excerpt != "" {
+			result[i].ResponseExcerpt = excerpt
+		}
+	}
+	recordResponseExcerpts(result)
+	return result, nil
+}
+
+// responseExcerpt returns the first n bytes of a response body without cutting a UTF-8 character in half
+func responseExcerpt(body string, n int) string {
+	if len(body) <= n {
+		return body
+	}
+	for n > 0 && !utf8.RuneStart(body[n]) {
+		n--
+	}
+	return body[:n]
+}
@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/ohader/gh-hookmon/internal/archive"
+	"github.com/ohader/gh-hookmon/internal/export"
+	"github.com/ohader/gh-hookmon/internal/github"
+	"github.com/spf13/cobra"
+)
+
+// exportOptions holds the flags of the export subcommand
+type exportOptions struct {
+	Format        string
+	Out           string
+	Compress      bool
+	BigQueryTable string
+	PrintSchema   bool
+	Upload        string
+}
+
+var exportOpts exportOptions
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export webhooks and deliveries for analysis in other tools",
+	Long: `Export the repositories, webhooks and recent deliveries of an organization or
+repository to a file for analysts.
+
+The sqlite format writes a self-contained database with the documented tables
+repositories, hooks and deliveries. Its schema is independent of the history
+database and only changes in a compatible way, so queries keep working across
+releases. An existing file is replaced.
+
+The ndjson format writes one JSON object per delivery, denormalized with its
+hook, matching a published BigQuery table schema (print it with --print-schema).
+With --bigquery-table the file is loaded into the table right away with the bq
+command line tool of the Google Cloud SDK, using its usual credentials.
+
+Examples:
+  # Export an organization to a SQLite database
+  gh hookmon export --org=myorg --format=sqlite --out=report.db
+  sqlite3 report.db "SELECT url, AVG(failed) FROM deliveries GROUP BY url"
+
+  # Export a compressed database, e.g. for archiving
+  gh hookmon export --org=myorg --format=sqlite --out=report.db --compress
+
+  # Export to newline-delimited JSON and append it to a BigQuery table
+  gh hookmon export --org=myorg --format=ndjson --out=deliveries.ndjson.gz --bigquery-table=myproject:webhooks.deliveries
+
+  # Export a compressed database to an S3 bucket
+  gh hookmon export --org=myorg --out=report.db --compress --upload=s3://reports/hookmon/
+
+  # Print the BigQuery schema, e.g. to create the table with other tools
+  gh hookmon export --format=ndjson --print-schema > schema.json`,
+	SilenceUsage: true,
+	RunE:         runExport,
+}
+
+func init() {
+	exportCmd.Flags().StringVar(&exportOpts.Format, "format", "sqlite", "Export format (sqlite, ndjson)")
+	exportCmd.Flags().StringVar(&exportOpts.Out, "out", "", "File to write, gzip-compressed if it ends with .gz (required)")
+	exportCmd.Flags().BoolVar(&exportOpts.Compress, "compress", false, "Gzip-compress the export, adding .gz to the file name if missing")
+	exportCmd.Flags().StringVar(&exportOpts.BigQueryTable, "bigquery-table", "", "Load the ndjson export into this BigQuery table, e.g. project:dataset.table (requires bq)")
+	exportCmd.Flags().StringVar(&exportOpts.Upload, "upload", "", "Upload the export to S3-compatible object storage, e.g. s3://bucket/path/")
+	exportCmd.Flags().BoolVar(&exportOpts.PrintSchema, "print-schema", false, "Print the BigQuery schema of the ndjson format and exit")
+	rootCmd.AddCommand(exportCmd)
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	if exportOpts.Format != "sqlite" && exportOpts.Format != "ndjson" {
+		return fmt.Errorf("validation error: --format must be sqlite or ndjson, got %q", exportOpts.Format)
+	}
+	if exportOpts.PrintSchema {
+		if exportOpts.Format != "ndjson" {
+			return fmt.Errorf("validation error: --print-schema requires --format=ndjson")
+		}
+		_, err := os.Stdout.Write(export.BigQuerySchema)
+		return err
+	}
+	if exportOpts.Out == "" {
+		return fmt.Errorf("validation error: --out is required")
+	}
+	if exportOpts.BigQueryTable != "" {
+		if exportOpts.Format != "ndjson" {
+			return fmt.Errorf("validation error: --bigquery-table requires --format=ndjson")
+		}
+		if _, err := exec.LookPath("bq"); err != nil {
+			return fmt.Errorf("validation error: --bigquery-table requires the bq command line tool of the Google Cloud SDK")
+		}
+	}
+	if err := validateTarget(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+	upload, err := newUploader(exportOpts.Upload)
+	if err != nil {
+		return err
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+	if err := requireFeature(client, github.FeatureHookDeliveries); err != nil {
+		return err
+	}
+
+	snap, err := collectSnapshot(client)
+	if err != nil {
+		return err
+	}
+
+	out := archive.Path(exportOpts.Out, exportOpts.Compress)
+	if exportOpts.Format == "ndjson" {
+		_, err = export.WriteNDJSON(snap, out)
+	} else {
+		err = export.WriteSQLite(snap, out)
+	}
+	if err != nil {
+		return err
+	}
+
+	repoCount, hookCount, deliveryCount := snap.Counts()
+	fmt.Printf("Exported %s to %s: %d repositories, %d hooks, %d deliveries\n",
+		snap.Target(), out, repoCount, hookCount, deliveryCount)
+
+	if err := upload.upload(out); err != nil {
+		return err
+	}
+	if exportOpts.BigQueryTable != "" {
+		if err := loadBigQuery(exportOpts.BigQueryTable, out); err != nil {
+			return err
+		}
+		fmt.Printf("Loaded %d deliveries into %s\n", deliveryCount, exportOpts.BigQueryTable)
+	}
+	return nil
+}
+
+// loadBigQuery appends an ndjson export to a BigQuery table with the bq command line tool
+// The table is created with the published schema if it does not exist
+func loadBigQuery(table, path string) error {
+	schemaFile, err := os.CreateTemp("", "hookmon-bigquery-schema-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to write BigQuery schema: %w", err)
+	}
+	defer os.Remove(schemaFile.Name())
+	if _, err := schemaFile.Write(export.BigQuerySchema); err != nil {
+		schemaFile.Close()
+		return fmt.Errorf("failed to write BigQuery schema: %w", err)
+	}
+	if err := schemaFile.Close(); err != nil {
+		return fmt.Errorf("failed to write BigQuery schema: %w", err)
+	}
+
+	// bq reports its progress on stdout, which is kept for the summary
+	load := exec.Command("bq", "load", "--source_format=NEWLINE_DELIMITED_JSON", table, path, schemaFile.Name())
+	load.Stdout = os.Stderr
+	load.Stderr = os.Stderr
+	if err := load.Run(); err != nil {
+		return fmt.Errorf("failed to load export into BigQuery table %s: %w", table, err)
+	}
+	return nil
+}
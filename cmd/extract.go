@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"github.com/ohader/gh-hookmon/internal/github"
+)
+
+// extractFields returns the values of the --extract paths of a request payload, redacted unless --no-redact is set
+// Every path is present, with a nil value if the payload does not contain it
+func extractFields(payload interface{}) map[string]interface{} {
+	payload = outputPayload(payload)
+	fields := make(map[string]interface{})
+	for _, path := range cfg.GetExtractPaths() {
+		fields[path] = github.PayloadField(payload, path)
+	}
+	return fields
+}
+
+// withExtractedFields adds the --extract payload fields to deliveries that do not have them yet
+// Deliveries whose details cannot be fetched are kept without fields
+func withExtractedFields(deliveries []github.Delivery, fetchDetails func([]github.Delivery) ([]github.Delivery, error)) ([]github.Delivery, error) {
+	var missing []github.Delivery
+	for _, d := range deliveries {
+		if d.Fields == nil {
+			missing = append(missing, d)
+		}
+	}
+	if len(missing) == 0 {
+		return deliveries, nil
+	}
+
+	detailed, err := fetchDetails(missing)
+	if err != nil {
+		return nil, err
+	}
+
+	extracted := make(map[int]map[string]interface{}, len(detailed))
+	for _, d := range detailed {
+		extracted[d.ID] = d.Fields
+	}
+	result := make([]github.Delivery, len(deliveries))
+	for i, d := range deliveries {
+		result[i] = d
+		if fields, ok := extracted[d.ID]; ok && fields != nil {
+			result[i].Fields = fields
+		}
+	}
+	return result, nil
+}
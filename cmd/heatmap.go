@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ohader/gh-hookmon/internal/config"
+	"github.com/ohader/gh-hookmon/internal/github"
+	"github.com/ohader/gh-hookmon/internal/output"
+	"github.com/ohader/gh-hookmon/internal/stats"
+	"github.com/spf13/cobra"
+)
+
+// heatmapOptions holds the flags of the heatmap subcommand
+type heatmapOptions struct {
+	Metric        string
+	Window        string
+	TimeZone      string
+	MinDeliveries int
+	JSON          bool
+}
+
+var heatmapOpts heatmapOptions
+
+var heatmapCmd = &cobra.Command{
+	Use:   "heatmap",
+	Short: "Show delivery volume or failure rate per weekday and hour",
+	Long: `Draw a heatmap of the deliveries of a time window with one row per weekday and
+one column per hour of the day. Failures clustering at the same hours point to
+deploy windows, batch jobs or maintenance of the receivers.
+
+--metric=volume shades every cell by its number of deliveries, --metric=failure-rate
+by its fraction of failed deliveries. Shades are relative to the peak cell.
+
+Deliveries are fetched up to --limit per hook. A warning names the hooks whose
+fetched deliveries do not reach back to the start of the window, raise --limit
+to cover long windows of busy hooks.
+
+Examples:
+  # Delivery volume of the last week in the local time zone
+  gh hookmon heatmap --org=myorg
+
+  # Failure rate of the last four weeks in the time zone of the deploy schedule
+  gh hookmon heatmap --org=myorg --metric=failure-rate --window=4w --limit=1000 --time-zone=Europe/Berlin
+
+  # Ignore hours with fewer than 10 deliveries, whose failure rates are noise
+  gh hookmon heatmap --repo=owner/repo --metric=failure-rate --min-deliveries=10`,
+	SilenceUsage: true,
+	RunE:         runHeatmap,
+}
+
+func init() {
+	heatmapCmd.Flags().StringVar(&heatmapOpts.Metric, "metric", string(stats.HeatmapVolume), "Value shaded in every cell (volume, failure-rate)")
+	heatmapCmd.Flags().StringVar(&heatmapOpts.Window, "window", "7d", "Time window to evaluate, e.g. 7d or 4w")
+	heatmapCmd.Flags().StringVar(&heatmapOpts.TimeZone, "time-zone", "", "Time zone of the weekdays and hours, e.g. UTC or Europe/Berlin (default: local)")
+	heatmapCmd.Flags().IntVar(&heatmapOpts.MinDeliveries, "min-deliveries", 1, "Do not shade the failure rate of hours with fewer deliveries")
+	heatmapCmd.Flags().BoolVar(&heatmapOpts.JSON, "json", false, "Output in JSON format")
+	heatmapCmd.Flags().StringVar(&cfg.Filter, "filter", "", "Filter webhook URLs by pattern")
+	rootCmd.AddCommand(heatmapCmd)
+}
+
+func runHeatmap(cmd *cobra.Command, args []string) error {
+	metric := stats.HeatmapMetric(heatmapOpts.Metric)
+	if metric != stats.HeatmapVolume && metric != stats.HeatmapFailureRate {
+		return fmt.Errorf("validation error: --metric must be volume or failure-rate, got %q", heatmapOpts.Metric)
+	}
+	window, err := config.ParseDuration(heatmapOpts.Window)
+	if err != nil {
+		return fmt.Errorf("validation error: --window: %w", err)
+	}
+	loc := time.Local
+	if heatmapOpts.TimeZone != "" {
+		if loc, err = time.LoadLocation(heatmapOpts.TimeZone); err != nil {
+			return fmt.Errorf("validation error: --time-zone: unknown time zone %q", heatmapOpts.TimeZone)
+		}
+	}
+	if err := validateTarget(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	since := time.Now().Add(-window)
+	coverWindow(since)
+	deliveries, err := fetchDeliveries(client)
+	if err != nil {
+		return err
+	}
+
+	inWindow := make([]github.Delivery, 0, len(deliveries))
+	for _, d := range deliveries {
+		if !d.DeliveredAt.Before(since) {
+			inWindow = append(inWindow, d)
+		}
+	}
+	incompleteWindow(deliveries, since, "the heatmap misses the start of the window")
+
+	heatmap := stats.NewHeatmap(inWindow, loc)
+	if heatmapOpts.JSON {
+		return output.FormatHeatmapJSON(heatmap, os.Stdout)
+	}
+	output.FormatHeatmap(heatmap, output.HeatmapOptions{
+		Metric:        metric,
+		MinDeliveries: heatmapOpts.MinDeliveries,
+		Window:        heatmapOpts.Window,
+		ASCII:         !unicodeLocale(),
+	}, os.Stdout)
+	return nil
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ohader/gh-hookmon/internal/github"
+	"github.com/ohader/gh-hookmon/internal/history"
+	"github.com/ohader/gh-hookmon/internal/output"
+)
+
+// collect and runs with --record (or record_history in the config file) record the deliveries they fetch
+// in the history database, which keeps them after GitHub dropped them and makes them queryable with db query
+
+// historyDB is the history database, opened on first use and shared by all workers
+var historyDB struct {
+	once      sync.Once
+	pruneOnce sync.Once
+	cutoff    time.Time // Deliveries made before are outside --retention
+	store     *history.Store
+	err       error
+
+	mu        sync.Mutex
+	recordErr error // First failure to record deliveries
+}
+
+// openHistory opens the history database at --history-db or its default location
+func openHistory() (*history.Store, error) {
+	historyDB.once.Do(func() {
+		path := cfg.HistoryDB
+		if path == "" {
+			path, historyDB.err = history.DefaultPath()
+			if historyDB.err != nil {
+				return
+			}
+		}
+		historyDB.store, historyDB.err = history.Open(path)
+	})
+	return historyDB.store, historyDB.err
+}
+
+// recordHistory stores fetched deliveries in the history database if recording is enabled
+// Recording is best effort and never fails a run
+func recordHistory(deliveries []github.Delivery) {
+	if !cfg.RecordHistory || len(deliveries) == 0 {
+		return
+	}
+	store, err := openHistory()
+	if err == nil {
+		historyDB.pruneOnce.Do(func() { pruneHistory(store) })
+		_, err = store.Record(withinRetention(deliveries))
+	}
+	if err == nil {
+		return
+	}
+	historyDB.mu.Lock()
+	if historyDB.recordErr == nil {
+		historyDB.recordErr = err
+	}
+	historyDB.mu.Unlock()
+	diag.warn(output.Issue{Kind: output.IssueHistoryFailed, Message: fmt.Sprintf("failed to record history: %v", err)})
+}
+
+// historyRecordError returns the first failure to record deliveries in this run, if any
+func historyRecordError() error {
+	historyDB.mu.Lock()
+	defer historyDB.mu.Unlock()
+	return historyDB.recordErr
+}
+
+// recordResponseExcerpts stores the response excerpts of deliveries in the history database if recording is enabled
+// Recording is best effort and never fails a run
+func recordResponseExcerpts(deliveries []github.Delivery) {
+	if !cfg.RecordHistory || cfg.Offline {
+		return
+	}
+	// Excerpts shown with --no-redact are recorded redacted all the same
+	if cfg.NoRedact {
+		redacted := make([]github.Delivery, len(deliveries))
+		for i, d := range deliveries {
+			d.ResponseExcerpt = redactor.Text(d.ResponseExcerpt)
+			redacted[i] = d
+		}
+		deliveries = redacted
+	}
+	store, err := openHistory()
+	if err == nil {
+		err = store.SetResponseExcerpts(deliveries)
+	}
+	if err != nil && cfg.Verbose {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record history: %v\n", err)
+	}
+}
+
+// pruneHistory enforces --retention once per run before deliveries are recorded
+func pruneHistory(store *history.Store) {
+	retention, err := cfg.GetRetention()
+	if err != nil || retention == 0 {
+		return
+	}
+	historyDB.cutoff = time.Now().Add(-retention)
+	pruned, err := store.Prune(historyDB.cutoff)
+	if err != nil {
+		if cfg.Verbose {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		return
+	}
+	if pruned > 0 && cfg.Verbose {
+		fmt.Fprintf(os.Stderr, "Pruned %d deliveries older than %s from the history\n", pruned, cfg.Retention)
+	}
+}
+
+// withinRetention drops deliveries outside --retention, so pruned deliveries are not recorded again
+func withinRetention(deliveries []github.Delivery) []github.Delivery {
+	if historyDB.cutoff.IsZero() {
+		return deliveries
+	}
+	kept := make([]github.Delivery, 0, len(deliveries))
+	for _, d := range deliveries {
+		if !d.DeliveredAt.Before(historyDB.cutoff) {
+			kept = append(kept, d)
+		}
+	}
+	return kept
+}
+
+// closeHistory closes the history database if it was opened
+func closeHistory() {
+	if historyDB.store != nil {
+		historyDB.store.Close()
+	}
+}
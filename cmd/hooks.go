@@ -0,0 +1,586 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ohader/gh-hookmon/internal/github"
+	"github.com/ohader/gh-hookmon/internal/output"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// hooksOptions holds the flags of the hooks subcommands
+type hooksOptions struct {
+	Hook         int
+	OnlyType     string
+	JSON         bool
+	URL          string
+	ContentType  string
+	Secret       string
+	Events       []string
+	AddEvents    []string
+	RemoveEvents []string
+	DryRun       bool
+	Yes          bool
+}
+
+var hooksOpts hooksOptions
+
+// hooksCreateOptions holds the flags of the hooks create subcommand
+type hooksCreateOptions struct {
+	URL         string
+	Events      []string
+	ContentType string
+	Secret      string
+	Inactive    bool
+	DryRun      bool
+}
+
+var hooksCreateOpts hooksCreateOptions
+
+var hooksCmd = &cobra.Command{
+	Use:   "hooks",
+	Short: "List and manage repository webhooks",
+	Long: `List, create, update and delete repository webhooks.
+
+Update, enable, disable and delete act on the hooks selected by --hook (a single hook of
+--repo) or by --filter (all hooks of --repo or --org whose URL matches).
+Update additionally selects by the current content type with --only-content-type.
+
+Examples:
+  # List all hooks of an organization pointing to ci.example.com
+  gh hookmon hooks list --org=myorg --filter=ci.example.com
+
+  # Create a hook
+  gh hookmon hooks create --repo=owner/repo --url=https://ci.example.com/hook --events=push,pull_request --secret=s3cr3t
+
+  # Switch all hooks of an organization still sending form-encoded payloads to JSON
+  gh hookmon hooks update --org=myorg --only-content-type=form --content-type=json
+
+  # Preview moving all hooks of an organization to a new host
+  gh hookmon hooks update --org=myorg --filter=old-ci.example.com --url=https://ci.example.com/hook --dry-run
+
+  # Disable all hooks of an organization pointing to a decommissioned service
+  gh hookmon hooks disable --org=myorg --filter=old-ci.example.com
+
+  # Delete a single hook
+  gh hookmon hooks delete --repo=owner/repo --hook=12345678`,
+}
+
+var hooksListCmd = &cobra.Command{
+	Use:          "list",
+	Short:        "List webhooks",
+	SilenceUsage: true,
+	RunE:         runHooksList,
+}
+
+var hooksCreateCmd = &cobra.Command{
+	Use:          "create",
+	Short:        "Create a webhook",
+	SilenceUsage: true,
+	RunE:         runHooksCreate,
+}
+
+var hooksUpdateCmd = &cobra.Command{
+	Use:          "update",
+	Short:        "Change URL, events, content type or secret of webhooks",
+	SilenceUsage: true,
+	RunE:         runHooksUpdate,
+}
+
+var hooksEnableCmd = &cobra.Command{
+	Use:          "enable",
+	Short:        "Activate webhooks",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setHooksActive(cmd, true)
+	},
+}
+
+var hooksDisableCmd = &cobra.Command{
+	Use:          "disable",
+	Short:        "Deactivate webhooks",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setHooksActive(cmd, false)
+	},
+}
+
+var hooksDeleteCmd = &cobra.Command{
+	Use:          "delete",
+	Short:        "Delete webhooks",
+	SilenceUsage: true,
+	RunE:         runHooksDelete,
+}
+
+func init() {
+	hooksListCmd.Flags().StringVar(&cfg.Filter, "filter", "", "Filter webhook URLs by pattern")
+	hooksListCmd.Flags().BoolVar(&hooksOpts.JSON, "json", false, "Output in JSON format")
+
+	hooksCreateCmd.Flags().StringVar(&hooksCreateOpts.URL, "url", "", "Payload URL of the webhook (required)")
+	hooksCreateCmd.Flags().StringSliceVar(&hooksCreateOpts.Events, "events", []string{"push"}, "Events the webhook is subscribed to")
+	hooksCreateCmd.Flags().StringVar(&hooksCreateOpts.ContentType, "content-type", "json", "Payload content type: json or form")
+	hooksCreateCmd.Flags().StringVar(&hooksCreateOpts.Secret, "secret", "", "Shared secret used to sign payloads")
+	hooksCreateCmd.Flags().BoolVar(&hooksCreateOpts.Inactive, "inactive", false, "Create the webhook without activating it")
+	hooksCreateCmd.Flags().BoolVar(&hooksCreateOpts.DryRun, "dry-run", false, "Show what would be created without changing anything")
+
+	hooksUpdateCmd.Flags().IntVar(&hooksOpts.Hook, "hook", 0, "Select a single hook ID of --repo")
+	hooksUpdateCmd.Flags().StringVar(&cfg.Filter, "filter", "", "Select hooks whose URL matches the pattern")
+	hooksUpdateCmd.Flags().StringVar(&hooksOpts.OnlyType, "only-content-type", "", "Select hooks currently using this content type: json or form")
+	hooksUpdateCmd.Flags().StringVar(&hooksOpts.URL, "url", "", "New payload URL")
+	hooksUpdateCmd.Flags().StringSliceVar(&hooksOpts.Events, "events", nil, "Replace the subscribed events")
+	hooksUpdateCmd.Flags().StringSliceVar(&hooksOpts.AddEvents, "add-events", nil, "Subscribe to additional events")
+	hooksUpdateCmd.Flags().StringSliceVar(&hooksOpts.RemoveEvents, "remove-events", nil, "Unsubscribe from events")
+	hooksUpdateCmd.Flags().StringVar(&hooksOpts.ContentType, "content-type", "", "New payload content type: json or form")
+	hooksUpdateCmd.Flags().StringVar(&hooksOpts.Secret, "secret", "", "New shared secret")
+	hooksUpdateCmd.Flags().BoolVar(&hooksOpts.DryRun, "dry-run", false, "Show what would be changed without changing anything")
+	hooksUpdateCmd.Flags().BoolVarP(&hooksOpts.Yes, "yes", "y", false, "Do not ask for confirmation")
+
+	for _, c := range []*cobra.Command{hooksEnableCmd, hooksDisableCmd} {
+		c.Flags().IntVar(&hooksOpts.Hook, "hook", 0, "Select a single hook ID of --repo")
+		c.Flags().StringVar(&cfg.Filter, "filter", "", "Select hooks whose URL matches the pattern")
+		c.Flags().BoolVar(&hooksOpts.DryRun, "dry-run", false, "Show what would be changed without changing anything")
+		c.Flags().BoolVarP(&hooksOpts.Yes, "yes", "y", false, "Do not ask for confirmation")
+	}
+
+	hooksDeleteCmd.Flags().IntVar(&hooksOpts.Hook, "hook", 0, "Select a single hook ID of --repo")
+	hooksDeleteCmd.Flags().StringVar(&cfg.Filter, "filter", "", "Select hooks whose URL matches the pattern")
+	hooksDeleteCmd.Flags().BoolVar(&hooksOpts.DryRun, "dry-run", false, "Show what would be deleted without changing anything")
+	hooksDeleteCmd.Flags().BoolVarP(&hooksOpts.Yes, "yes", "y", false, "Do not ask for confirmation")
+
+	hooksCmd.AddCommand(hooksListCmd, hooksCreateCmd, hooksUpdateCmd, hooksEnableCmd, hooksDisableCmd, hooksDeleteCmd)
+	rootCmd.AddCommand(hooksCmd)
+}
+
+func runHooksList(cmd *cobra.Command, args []string) error {
+	if err := validateTarget(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	hooks, err := fetchHooks(client)
+	if err != nil {
+		return err
+	}
+
+	if hooksOpts.JSON {
+		return output.FormatHooksJSON(hooks, os.Stdout)
+	}
+	output.FormatHooksTable(hooks, os.Stdout)
+	return nil
+}
+
+func runHooksCreate(cmd *cobra.Command, args []string) error {
+	if err := requireOnline("hooks create"); err != nil {
+		return err
+	}
+	if cfg.Repo == "" {
+		return fmt.Errorf("validation error: hooks create requires --repo")
+	}
+	if cfg.Org != "" {
+		return fmt.Errorf("validation error: hooks create does not support --org")
+	}
+	if hooksCreateOpts.URL == "" {
+		return fmt.Errorf("validation error: --url is required")
+	}
+	if len(hooksCreateOpts.Events) == 0 {
+		return fmt.Errorf("validation error: --events must not be empty")
+	}
+	if err := validateContentType(hooksCreateOpts.ContentType); err != nil {
+		return err
+	}
+
+	if hooksCreateOpts.DryRun {
+		fmt.Printf("Would create hook in %s: %s (events: %s)\n", cfg.Repo, hooksCreateOpts.URL, strings.Join(hooksCreateOpts.Events, ", "))
+		return nil
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	config := github.HookConfigUpdate{
+		URL:         hooksCreateOpts.URL,
+		ContentType: hooksCreateOpts.ContentType,
+		Secret:      hooksCreateOpts.Secret,
+	}
+	hook, err := client.CreateRepoWebhook(cfg.Repo, config, hooksCreateOpts.Events, !hooksCreateOpts.Inactive)
+	if err != nil {
+		return err
+	}
+	forgetHooks(cfg.Repo)
+
+	fmt.Printf("Created hook %d in %s: %s\n", hook.ID, cfg.Repo, hook.GetTargetURL())
+	return nil
+}
+
+func runHooksUpdate(cmd *cobra.Command, args []string) error {
+	if err := requireOnline("hooks update"); err != nil {
+		return err
+	}
+	if err := validateHookSelector(cmd); err != nil {
+		return err
+	}
+	if len(hooksOpts.Events) > 0 && (len(hooksOpts.AddEvents) > 0 || len(hooksOpts.RemoveEvents) > 0) {
+		return fmt.Errorf("validation error: --events cannot be combined with --add-events or --remove-events")
+	}
+	if hooksOpts.ContentType != "" {
+		if err := validateContentType(hooksOpts.ContentType); err != nil {
+			return err
+		}
+	}
+	if hooksOpts.OnlyType != "" && hooksOpts.OnlyType != "json" && hooksOpts.OnlyType != "form" {
+		return fmt.Errorf("validation error: --only-content-type must be 'json' or 'form', got %q", hooksOpts.OnlyType)
+	}
+
+	config := github.HookConfigUpdate{
+		URL:         hooksOpts.URL,
+		ContentType: hooksOpts.ContentType,
+		Secret:      hooksOpts.Secret,
+	}
+	update := github.HookUpdate{
+		Events:       hooksOpts.Events,
+		AddEvents:    hooksOpts.AddEvents,
+		RemoveEvents: hooksOpts.RemoveEvents,
+	}
+	changes := describeHookChanges(config, update)
+	if len(changes) == 0 {
+		return fmt.Errorf("validation error: nothing to update, use --url, --events, --add-events, --remove-events, --content-type or --secret")
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	hooks, err := selectHooks(client)
+	if err != nil {
+		return err
+	}
+
+	if hooksOpts.DryRun {
+		for _, h := range hooks {
+			fmt.Printf("Would update %s hook %d %s: %s\n", h.Repository, h.ID, displayURL(h.GetTargetURL()), changes)
+		}
+		return nil
+	}
+
+	if !hooksOpts.Yes {
+		for _, h := range hooks {
+			fmt.Printf("%s hook %d %s\n", h.Repository, h.ID, displayURL(h.GetTargetURL()))
+		}
+		ok, err := confirm(fmt.Sprintf("Update %d webhooks: %s?", len(hooks), changes))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("aborted, no webhooks were updated")
+		}
+	}
+
+	failures := 0
+	for _, h := range hooks {
+		if err := updateHook(client, h, config, update); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s hook %d: %v\n", h.Repository, h.ID, err)
+			failures++
+			continue
+		}
+		fmt.Printf("Updated %s hook %d %s: %s\n", h.Repository, h.ID, displayURL(h.GetTargetURL()), changes)
+	}
+
+	if failures > 0 {
+		return fmt.Errorf("%d of %d hooks could not be updated", failures, len(hooks))
+	}
+	return nil
+}
+
+func runHooksDelete(cmd *cobra.Command, args []string) error {
+	if err := requireOnline("hooks delete"); err != nil {
+		return err
+	}
+	if err := validateHookSelector(cmd); err != nil {
+		return err
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	hooks, err := selectHooks(client)
+	if err != nil {
+		return err
+	}
+
+	if hooksOpts.DryRun {
+		for _, h := range hooks {
+			fmt.Printf("Would delete %s hook %d %s\n", h.Repository, h.ID, displayURL(h.GetTargetURL()))
+		}
+		return nil
+	}
+
+	if !hooksOpts.Yes {
+		for _, h := range hooks {
+			fmt.Printf("%s hook %d %s\n", h.Repository, h.ID, displayURL(h.GetTargetURL()))
+		}
+		ok, err := confirm(fmt.Sprintf("Delete %d webhooks?", len(hooks)))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("aborted, no webhooks were deleted")
+		}
+	}
+
+	failures := 0
+	for _, h := range hooks {
+		if err := client.DeleteRepoWebhook(h.Repository, h.ID); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", h.Repository, err)
+			failures++
+			continue
+		}
+		fmt.Printf("Deleted %s hook %d %s\n", h.Repository, h.ID, displayURL(h.GetTargetURL()))
+	}
+
+	if failures > 0 {
+		return fmt.Errorf("%d of %d hooks could not be deleted", failures, len(hooks))
+	}
+	return nil
+}
+
+// setHooksActive enables or disables the selected hooks after confirmation
+// Hooks already in the requested state are left untouched
+func setHooksActive(cmd *cobra.Command, active bool) error {
+	verb, past := "enable", "Enabled"
+	if !active {
+		verb, past = "disable", "Disabled"
+	}
+
+	if err := requireOnline("hooks " + verb); err != nil {
+		return err
+	}
+	if err := validateHookSelector(cmd); err != nil {
+		return err
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	hooks, err := selectHooks(client)
+	if err != nil {
+		return err
+	}
+
+	var pending []github.RepoHook
+	for _, h := range hooks {
+		if h.Active != active {
+			pending = append(pending, h)
+		}
+	}
+	unchanged := len(hooks) - len(pending)
+
+	if len(pending) == 0 {
+		fmt.Printf("All %d matching hooks are already %sd\n", len(hooks), verb)
+		return nil
+	}
+
+	if hooksOpts.DryRun {
+		for _, h := range pending {
+			fmt.Printf("Would %s %s hook %d %s\n", verb, h.Repository, h.ID, displayURL(h.GetTargetURL()))
+		}
+		return nil
+	}
+
+	if !hooksOpts.Yes {
+		for _, h := range pending {
+			fmt.Printf("%s hook %d %s\n", h.Repository, h.ID, displayURL(h.GetTargetURL()))
+		}
+		ok, err := confirm(fmt.Sprintf("%s %d webhooks?", strings.ToUpper(verb[:1])+verb[1:], len(pending)))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("aborted, no webhooks were changed")
+		}
+	}
+
+	failures := 0
+	for _, h := range pending {
+		if _, err := client.UpdateRepoWebhook(h.Repository, h.ID, github.HookUpdate{Active: &active}); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", h.Repository, err)
+			failures++
+			continue
+		}
+		fmt.Printf("%s %s hook %d %s\n", past, h.Repository, h.ID, displayURL(h.GetTargetURL()))
+	}
+
+	fmt.Printf("%s %d hooks, %d already %sd, %d failed\n", past, len(pending)-failures, unchanged, verb, failures)
+
+	if failures > 0 {
+		return fmt.Errorf("%d of %d hooks could not be %sd", failures, len(pending), verb)
+	}
+	return nil
+}
+
+// validateHookSelector ensures hooks are never changed or deleted by accident
+func validateHookSelector(cmd *cobra.Command) error {
+	if hooksOpts.Hook == 0 && cfg.Filter == "" && hooksOpts.OnlyType == "" {
+		if cmd.Flags().Lookup("only-content-type") != nil {
+			return fmt.Errorf("validation error: select hooks with --hook, --filter or --only-content-type")
+		}
+		return fmt.Errorf("validation error: select hooks with --hook or --filter")
+	}
+	if hooksOpts.Hook != 0 && cfg.Repo == "" {
+		return fmt.Errorf("validation error: --hook requires --repo")
+	}
+	if err := validateTarget(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+	return nil
+}
+
+// validateContentType checks a payload content type as accepted by the GitHub API
+func validateContentType(contentType string) error {
+	if contentType != "json" && contentType != "form" {
+		return fmt.Errorf("validation error: --content-type must be 'json' or 'form', got %q", contentType)
+	}
+	return nil
+}
+
+// selectHooks returns the hooks selected by --hook and --filter
+func selectHooks(client *github.Client) ([]github.RepoHook, error) {
+	hooks, err := fetchHooks(client)
+	if err != nil {
+		return nil, err
+	}
+
+	if hooksOpts.Hook != 0 {
+		var selected []github.RepoHook
+		for _, h := range hooks {
+			if h.ID == hooksOpts.Hook {
+				selected = append(selected, h)
+			}
+		}
+		if len(selected) == 0 {
+			if cfg.Filter != "" {
+				return nil, fmt.Errorf("hook %d not found in %s or its URL does not match --filter", hooksOpts.Hook, cfg.Repo)
+			}
+			return nil, fmt.Errorf("hook %d not found in %s", hooksOpts.Hook, cfg.Repo)
+		}
+		hooks = selected
+	}
+
+	if hooksOpts.OnlyType != "" {
+		var selected []github.RepoHook
+		for _, h := range hooks {
+			if h.GetContentType() == hooksOpts.OnlyType {
+				selected = append(selected, h)
+			}
+		}
+		hooks = selected
+	}
+
+	if len(hooks) == 0 {
+		return nil, fmt.Errorf("no matching webhooks found")
+	}
+	return hooks, nil
+}
+
+// updateHook applies configuration and event changes to a hook
+// The API changes them in two requests, so a failed second request leaves the hook partly updated,
+// the error then tells which changes were applied and which were not
+func updateHook(client *github.Client, h github.RepoHook, config github.HookConfigUpdate, update github.HookUpdate) error {
+	configChanged := config != (github.HookConfigUpdate{})
+	hookChanged := update.Active != nil || len(update.Events) > 0 || len(update.AddEvents) > 0 || len(update.RemoveEvents) > 0
+
+	if configChanged {
+		if err := client.UpdateRepoWebhookConfig(h.Repository, h.ID, config); err != nil {
+			if hookChanged {
+				return fmt.Errorf("nothing was changed, updating %s failed: %w", describeHookChanges(config, github.HookUpdate{}), err)
+			}
+			return err
+		}
+	}
+	if hookChanged {
+		if _, err := client.UpdateRepoWebhook(h.Repository, h.ID, update); err != nil {
+			if configChanged {
+				return fmt.Errorf("only %s was applied, updating %s failed: %w",
+					describeHookChanges(config, github.HookUpdate{}), describeHookChanges(github.HookConfigUpdate{}, update), err)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// describeHookChanges summarizes the requested changes, never revealing the secret
+func describeHookChanges(config github.HookConfigUpdate, update github.HookUpdate) string {
+	var changes []string
+	if config.URL != "" {
+		changes = append(changes, "url="+config.URL)
+	}
+	if config.ContentType != "" {
+		changes = append(changes, "content_type="+config.ContentType)
+	}
+	if config.Secret != "" {
+		changes = append(changes, "secret=***")
+	}
+	if update.Active != nil {
+		changes = append(changes, fmt.Sprintf("active=%t", *update.Active))
+	}
+	if len(update.Events) > 0 {
+		changes = append(changes, "events="+strings.Join(update.Events, ","))
+	}
+	for _, event := range update.AddEvents {
+		changes = append(changes, "+"+event)
+	}
+	for _, event := range update.RemoveEvents {
+		changes = append(changes, "-"+event)
+	}
+	return strings.Join(changes, " ")
+}
+
+// confirm asks a yes/no question on the terminal
+// Without a terminal there is nobody to ask, so --yes must be given explicitly
+func confirm(question string) (bool, error) {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return false, fmt.Errorf("confirmation required, use --yes when not running in a terminal")
+	}
+
+	fmt.Fprintf(os.Stderr, "%s [y/N] ", question)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false, nil
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes", nil
+}
+
+// fetchHooks lists the webhooks of the configured repository or organization matching the URL filter
+func fetchHooks(client *github.Client) ([]github.RepoHook, error) {
+	return scanTargets(client, func(client *github.Client, repo string) ([]github.RepoHook, error) {
+		hooks, err := listRepoWebhooks(client, repo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list webhooks: %w", err)
+		}
+
+		result := make([]github.RepoHook, 0, len(hooks))
+		for _, hook := range hooks {
+			if cfg.Filter != "" && !hook.MatchesFilter(cfg.Filter) {
+				continue
+			}
+			result = append(result, github.RepoHook{Repository: repo, Hook: hook})
+		}
+		return result, nil
+	})
+}
@@ -0,0 +1,276 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ohader/gh-hookmon/internal/github"
+	"github.com/ohader/gh-hookmon/internal/output"
+)
+
+// Each repository of a scan is an isolated task: it is retried on transient errors (--repo-retries)
+// and its API requests are cancelled once it took longer than --repo-timeout, so a single
+// pathological repository can neither stall the worker pool nor drop out of the results unnoticed
+
+// Defaults of --repo-timeout and --repo-retries
+const (
+	defaultRepoTimeout = 10 * time.Minute
+	defaultRepoRetries = 2
+)
+
+// errRepoTimeout is returned for API requests of a repository whose scan exceeded --repo-timeout
+var errRepoTimeout = errors.New("--repo-timeout exceeded")
+
+// repoScanScopes binds the API requests of every repository being scanned to the context of its scan
+// Requests are matched to repositories by their /repos/OWNER/REPO path
+type repoScanScopes struct {
+	mu   sync.Mutex
+	ctxs map[string]context.Context
+}
+
+var repoScans = &repoScanScopes{ctxs: make(map[string]context.Context)}
+
+// begin starts the scan of a repository, bounded by the deadline and --repo-timeout
+// The returned function ends the scan and must always be called
+func (s *repoScanScopes) begin(repo string) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(deadline.ctx)
+	if cfg.RepoTimeout > 0 {
+		ctx, cancel = context.WithTimeoutCause(deadline.ctx, cfg.RepoTimeout, errRepoTimeout)
+	}
+
+	key := strings.ToLower(repo)
+	s.mu.Lock()
+	s.ctxs[key] = ctx
+	s.mu.Unlock()
+
+	return ctx, func() {
+		s.mu.Lock()
+		delete(s.ctxs, key)
+		s.mu.Unlock()
+		cancel()
+	}
+}
+
+// lookup returns the scan context of the repository a request path belongs to, nil if it is not being scanned
+func (s *repoScanScopes) lookup(path string) context.Context {
+	_, rest, ok := strings.Cut(path, "/repos/")
+	if !ok {
+		return nil
+	}
+	parts := strings.SplitN(rest, "/", 3)
+	if len(parts) < 2 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ctxs[strings.ToLower(parts[0]+"/"+parts[1])]
+}
+
+// transport runs the requests of every repository being scanned in the context of its scan
+func (s *repoScanScopes) transport(base http.RoundTripper) http.RoundTripper {
+	return &repoScanTransport{base: base, scopes: s}
+}
+
+type repoScanTransport struct {
+	base   http.RoundTripper
+	scopes *repoScanScopes
+}
+
+func (t *repoScanTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := t.scopes.lookup(req.URL.Path)
+	if ctx == nil {
+		return t.base.RoundTrip(req)
+	}
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil && errors.Is(context.Cause(ctx), errRepoTimeout) {
+		return nil, errRepoTimeout
+	}
+	return resp, err
+}
+
+// repoScan is the outcome of scanning a single repository
+type repoScan[T any] struct {
+	items    []T
+	err      error
+	attempts int
+	timedOut bool // Cancelled by --repo-timeout
+}
+
+// scanRepository scans a repository, again up to --repo-retries times while it fails with a transient error
+// The scan including retries is bounded by --repo-timeout
+func scanRepository[T any](client *github.Client, repo string, scan func(client *github.Client, repo string) ([]T, error)) repoScan[T] {
+	ctx, end := repoScans.begin(repo)
+	defer end()
+
+	var result repoScan[T]
+	for {
+		result.attempts++
+		result.items, result.err = traceScan(client, repo, scan)
+		// Scans that tolerate failing hooks may return without an error, their results are incomplete nonetheless
+		if errors.Is(context.Cause(ctx), errRepoTimeout) {
+			result.timedOut = true
+			result.err = fmt.Errorf("scan timed out after %s: %w", cfg.RepoTimeout, errRepoTimeout)
+			return result
+		}
+		if result.err == nil {
+			return result
+		}
+		if result.attempts > cfg.RepoRetries || !transientError(result.err) {
+			return result
+		}
+
+		// Back off a little longer with every attempt, unless the deadline or --repo-timeout passes meanwhile
+		wait := time.Duration(result.attempts) * 2 * time.Second
+		if cfg.Verbose {
+			fmt.Fprintf(os.Stderr, "Retrying repository %s in %s after: %v\n", repo, wait, result.err)
+		}
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			result.timedOut = errors.Is(context.Cause(ctx), errRepoTimeout)
+			return result
+		}
+	}
+}
+
+// transientError reports whether a failed scan is worth retrying: server errors and timed out requests
+// Cancellations by the deadline or --repo-timeout are final
+func transientError(err error) bool {
+	if deadline.interrupted(err) || errors.Is(err, errRepoTimeout) {
+		return false
+	}
+	if github.IsServerError(err) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// scanSummary counts the outcomes of the repositories of a scan
+// Repositories the token may not read are expected in large organizations, they are grouped instead of listed
+type scanSummary struct {
+	total, retried, failed, timedOut, skipped int
+	problems                                  []string            // One line per failed or timed out repository
+	denied                                    map[string][]string // Repositories per reason of a permission error
+}
+
+// Reasons of repositories skipped for missing permissions
+// GitHub answers 404 instead of 403 for repositories and hooks the token may not see at all
+const (
+	deniedForbidden = "insufficient permissions (403)"
+	deniedNotFound  = "not found or no admin access (404)"
+)
+
+// permissionDenied returns the reason if err is a permission error of the API, i.e. 403 or 404
+func permissionDenied(err error) (string, bool) {
+	switch github.HTTPStatus(err) {
+	case http.StatusForbidden:
+		return deniedForbidden, true
+	case http.StatusNotFound:
+		return deniedNotFound, true
+	}
+	return "", false
+}
+
+// deniedRepos counts the repositories of all scans of the run denied with 403 or 404
+var deniedRepos atomic.Int64
+
+// recordDenied records a repository denied with 403 or 404 according to --on-forbidden
+// With skip and fail it is a failed operation as any other, with report it is listed as skipped in the JSON envelope
+func recordDenied(repo, reason string, err error) {
+	deniedRepos.Add(1)
+	if cfg.OnForbidden == "report" {
+		diag.skip(output.Issue{Kind: output.IssueForbidden, Repository: repo, Message: reason})
+		return
+	}
+	diag.fail(output.Issue{Kind: output.IssueForbidden, Repository: repo, Message: err.Error()})
+}
+
+// forbiddenExitError fails a run that skipped denied repositories with --on-forbidden=fail
+// The results are printed nonetheless, auditors learn that they are incomplete from the exit code
+func forbiddenExitError(err error) error {
+	denied := deniedRepos.Load()
+	if err != nil || cfg.OnForbidden != "fail" || denied == 0 {
+		return err
+	}
+	err = fmt.Errorf("%d repositories were skipped for missing permissions (--on-forbidden=fail)", denied)
+	fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	return &ExitError{Code: exitCodeFetchErrors, Err: err}
+}
+
+// add counts the outcome of a repository
+func (s *scanSummary) add(repo string, attempts int, err error, timedOut bool) {
+	s.total++
+	reason, denied := permissionDenied(err)
+	switch {
+	case timedOut:
+		s.timedOut++
+		s.problems = append(s.problems, fmt.Sprintf("%s: timed out after %s", repo, cfg.RepoTimeout))
+	case denied:
+		if s.denied == nil {
+			s.denied = make(map[string][]string)
+		}
+		s.denied[reason] = append(s.denied[reason], repo)
+	case err != nil && attempts > 1:
+		s.failed++
+		s.problems = append(s.problems, fmt.Sprintf("%s: failed after %d attempts: %v", repo, attempts, err))
+	case err != nil:
+		s.failed++
+		s.problems = append(s.problems, fmt.Sprintf("%s: %v", repo, err))
+	case attempts > 1:
+		s.retried++
+	}
+}
+
+// deniedCount returns the number of repositories skipped for missing permissions
+func (s *scanSummary) deniedCount() int {
+	n := 0
+	for _, repos := range s.denied {
+		n += len(repos)
+	}
+	return n
+}
+
+// print writes the summary to stderr if any repository needed a retry, failed, timed out or was denied, always with --verbose
+// Denied repositories are listed with --show-skipped
+func (s *scanSummary) print() {
+	denied := s.deniedCount()
+	if s.retried+s.failed+s.timedOut+denied == 0 && !cfg.Verbose {
+		return
+	}
+	ok := s.total - s.retried - s.failed - s.timedOut - denied
+	fmt.Fprintf(os.Stderr, "Scanned %d repositories: %d ok, %d ok after retries, %d failed, %d timed out",
+		s.total, ok, s.retried, s.failed, s.timedOut)
+	if s.skipped > 0 {
+		fmt.Fprintf(os.Stderr, ", %d skipped (--deadline)", s.skipped)
+	}
+	fmt.Fprintln(os.Stderr)
+	for _, problem := range s.problems {
+		fmt.Fprintf(os.Stderr, "  %s\n", problem)
+	}
+
+	for _, reason := range []string{deniedForbidden, deniedNotFound} {
+		repos := s.denied[reason]
+		if len(repos) == 0 {
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "%d repositories skipped: %s\n", len(repos), reason)
+		if cfg.ShowSkipped || cfg.OnForbidden == "report" {
+			for _, repo := range repos {
+				fmt.Fprintf(os.Stderr, "  %s\n", repo)
+			}
+		}
+	}
+	if denied > 0 && !cfg.ShowSkipped && cfg.OnForbidden != "report" {
+		fmt.Fprintln(os.Stderr, "Use --show-skipped to list them, reading webhooks requires admin access to a repository")
+	}
+}
@@ -0,0 +1,370 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ohader/gh-hookmon/internal/cache"
+	"github.com/ohader/gh-hookmon/internal/github"
+	"github.com/ohader/gh-hookmon/internal/output"
+)
+
+// Online runs store the webhooks and deliveries they fetch in the on-disk cache,
+// --offline answers from these entries instead of the API regardless of their age
+
+// cachedData tracks the age of the cache entries an --offline run was answered from
+// Workers read concurrently, so access is guarded by a mutex
+type cachedData struct {
+	mu     sync.Mutex
+	oldest time.Time
+	newest time.Time
+	stale  int // Entries older than --cache-ttl
+}
+
+var offline = &cachedData{}
+
+// load reads a cache entry for --offline, what describes the entry in error messages
+func (c *cachedData) load(key, what, repo string, v interface{}) error {
+	store, err := cache.New()
+	if err != nil {
+		return err
+	}
+	storedAt, found, err := store.Load(key, v)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("no cached %s (--offline), run once without --offline to fetch it", what)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.oldest.IsZero() || storedAt.Before(c.oldest) {
+		c.oldest = storedAt
+	}
+	if storedAt.After(c.newest) {
+		c.newest = storedAt
+	}
+	if age := time.Since(storedAt); age > cfg.CacheTTL {
+		c.stale++
+		diag.warn(output.Issue{
+			Kind:       output.IssueStaleCache,
+			Repository: repo,
+			Message:    fmt.Sprintf("cached %s is %s old", what, formatAge(age)),
+		})
+	}
+	return nil
+}
+
+// report prints the age of the data an --offline run was answered from
+// Stale data is always reported, as it may no longer reflect the current state
+func (c *cachedData) report() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.oldest.IsZero() {
+		return
+	}
+	if c.stale > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: offline results are based on cached data up to %s old (%d entries older than --cache-ttl of %s)\n",
+			formatAge(time.Since(c.oldest)), c.stale, cfg.CacheTTL)
+		return
+	}
+	if cfg.Verbose {
+		fmt.Fprintf(os.Stderr, "Offline results are based on cached data fetched between %s and %s\n",
+			c.oldest.Local().Format(time.DateTime), c.newest.Local().Format(time.DateTime))
+	}
+}
+
+// storeForOffline writes fetched data to the cache for later --offline runs, unless --no-cache is set
+func storeForOffline(key string, v interface{}) {
+	if cfg.NoCache {
+		return
+	}
+	store, err := cache.New()
+	if err == nil {
+		err = store.Set(key, v)
+	}
+	if err != nil && cfg.Verbose {
+		fmt.Fprintf(os.Stderr, "Warning: failed to cache %s: %v\n", key, err)
+	}
+}
+
+// requireOnline fails commands that cannot work from cached data
+func requireOnline(what string) error {
+	if cfg.Offline {
+		return fmt.Errorf("validation error: %s requires API access and cannot be combined with --offline", what)
+	}
+	return nil
+}
+
+// listRepoWebhooks lists the webhooks of a repository, from the cache with --offline
+func listRepoWebhooks(client *github.Client, repo string) ([]github.Hook, error) {
+	key := hooksKey(repo)
+	if cfg.Offline {
+		var hooks []github.Hook
+		if err := offline.load(key, "webhooks of "+repo, repo, &hooks); err != nil {
+			return nil, err
+		}
+		return hooks, nil
+	}
+
+	hooks, err := client.ListRepoWebhooks(repo)
+	if err != nil {
+		return nil, err
+	}
+	storeForOffline(key, hooks)
+	return hooks, nil
+}
+
+// listScannedRepoWebhooks lists the webhooks of a repository whose deliveries are scanned
+// Unlike listRepoWebhooks, repositories found without webhooks within --hookless-ttl are skipped
+// Commands changing or auditing hooks must not miss a hook added meanwhile, they use listRepoWebhooks
+func listScannedRepoWebhooks(client *github.Client, repo string) ([]github.Hook, error) {
+	if !cfg.Offline && knownHookless(hooksKey(repo)) {
+		if cfg.Verbose {
+			fmt.Fprintf(os.Stderr, "Skipping %s, it had no webhooks within --hookless-ttl\n", repo)
+		}
+		return []github.Hook{}, nil
+	}
+	return listRepoWebhooks(client, repo)
+}
+
+// knownHookless reports whether the webhooks cached at key were fetched within --hookless-ttl and there were none
+// Most repositories of an organization have no webhooks, skipping them saves a request per repository and run
+func knownHookless(key string) bool {
+	if cfg.NoCache || cfg.Refresh || cfg.HooklessTTL <= 0 {
+		return false
+	}
+	store, err := cache.New()
+	if err != nil {
+		return false
+	}
+	var hooks []github.Hook
+	found, err := store.Get(key, cfg.HooklessTTL, &hooks)
+	return err == nil && found && len(hooks) == 0
+}
+
+// forgetHooks drops the cached webhooks of a repository after changing them, e.g. so it is no longer skipped as hookless
+func forgetHooks(repo string) {
+	if cfg.NoCache {
+		return
+	}
+	store, err := cache.New()
+	if err == nil {
+		err = store.Delete(hooksKey(repo))
+	}
+	if err != nil && cfg.Verbose {
+		fmt.Fprintf(os.Stderr, "Warning: failed to forget cached webhooks of %s: %v\n", repo, err)
+	}
+}
+
+// listRepoHookDeliveries lists the deliveries of a repository webhook, back to the window of the report, from the cache with --offline
+func listRepoHookDeliveries(client *github.Client, repo string, hookID int) ([]github.Delivery, error) {
+	key := deliveriesKey(repo, hookID)
+	if cfg.Offline {
+		var deliveries []github.Delivery
+		if err := offline.load(key, fmt.Sprintf("deliveries of hook %d", hookID), repo, &deliveries); err != nil {
+			return nil, err
+		}
+		// Repository and hook are not part of the stored JSON
+		for i := range deliveries {
+			deliveries[i].Repository = repo
+			deliveries[i].HookID = hookID
+		}
+		return deliveries, nil
+	}
+
+	deliveries, err := client.ListRepoHookDeliveriesSince(repo, hookID, cfg.Limit, windowStart)
+	if err != nil {
+		return nil, err
+	}
+	storeForOffline(key, deliveries)
+	return deliveries, nil
+}
+
+// listOrgWebhooks lists the webhooks of an organization, from the cache with --offline
+func listOrgWebhooks(client *github.Client, org string) ([]github.Hook, error) {
+	key := orgHooksKey(org)
+	if cfg.Offline {
+		var hooks []github.Hook
+		if err := offline.load(key, "webhooks of organization "+org, "", &hooks); err != nil {
+			return nil, err
+		}
+		return hooks, nil
+	}
+
+	hooks, err := client.ListOrgWebhooks(org)
+	if err != nil {
+		return nil, err
+	}
+	storeForOffline(key, hooks)
+	return hooks, nil
+}
+
+// listOrgHookDeliveries lists the deliveries of an organization webhook, back to the window of the report, from the cache with --offline
+func listOrgHookDeliveries(client *github.Client, org string, hookID int) ([]github.Delivery, error) {
+	key := orgDeliveriesKey(org, hookID)
+	if cfg.Offline {
+		var deliveries []github.Delivery
+		if err := offline.load(key, fmt.Sprintf("deliveries of organization hook %d", hookID), "", &deliveries); err != nil {
+			return nil, err
+		}
+		// Organization and hook are not part of the stored JSON
+		for i := range deliveries {
+			deliveries[i].Repository = org
+			deliveries[i].HookID = hookID
+		}
+		return deliveries, nil
+	}
+
+	deliveries, err := client.ListOrgHookDeliveriesSince(org, hookID, cfg.Limit, windowStart)
+	if err != nil {
+		return nil, err
+	}
+	storeForOffline(key, deliveries)
+	return deliveries, nil
+}
+
+// getRepositoryID returns the ID of a repository, from the cache with --offline
+func getRepositoryID(client *github.Client, repo string) (int, error) {
+	key := repositoryIDKey(repo)
+	if cfg.Offline {
+		var id int
+		if err := offline.load(key, "ID of "+repo, repo, &id); err != nil {
+			return 0, err
+		}
+		return id, nil
+	}
+
+	id, err := client.GetRepositoryID(repo)
+	if err != nil {
+		return 0, err
+	}
+	storeForOffline(key, id)
+	return id, nil
+}
+
+// detailCacheTTL is how long delivery details are reused
+// Details never change once fetched, the limit only keeps the cache from growing with deliveries GitHub no longer lists
+const detailCacheTTL = 30 * 24 * time.Hour
+
+// errDetailNotCached is returned by getDeliveryDetail for details missing from the cache with --offline
+var errDetailNotCached = errors.New("delivery detail is not cached")
+
+// getDeliveryDetail returns the detail of a delivery, from the cache if it was fetched before
+// The detail is cached redacted, so signatures and payload secrets never reach the disk
+// --no-redact therefore fetches the original detail unless the run is --offline
+func getDeliveryDetail(client *github.Client, d github.Delivery) (*github.DeliveryDetail, error) {
+	// Details of organization hook deliveries are looked up through the organization
+	owner := d.Repository
+	if d.Org != "" {
+		owner = d.Org
+	}
+	key := detailKey(owner, d.HookID, d.ID)
+	var store *cache.Cache
+	if !cfg.NoCache {
+		var err error
+		store, err = cache.New()
+		if err != nil && cfg.Verbose {
+			fmt.Fprintf(os.Stderr, "Warning: cache unavailable: %v\n", err)
+		}
+	}
+
+	if store != nil && (!cfg.NoRedact || cfg.Offline) {
+		var detail github.DeliveryDetail
+		var found bool
+		var err error
+		if cfg.Offline {
+			_, found, err = store.Load(key, &detail)
+		} else {
+			found, err = store.Get(key, detailCacheTTL, &detail)
+		}
+		if err != nil && cfg.Verbose {
+			fmt.Fprintf(os.Stderr, "Warning: failed to read cached delivery detail: %v\n", err)
+		}
+		if found {
+			// Repository and hook are not part of the stored JSON
+			detail.Repository = d.Repository
+			detail.HookID = d.HookID
+			return &detail, nil
+		}
+	}
+	if cfg.Offline {
+		return nil, errDetailNotCached
+	}
+
+	var detail *github.DeliveryDetail
+	var err error
+	if d.Org != "" {
+		detail, err = client.GetOrgHookDeliveryDetail(d.Org, d.HookID, d.ID)
+	} else {
+		detail, err = client.GetRepoHookDeliveryDetail(d.Repository, d.HookID, d.ID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	detail.Repository = d.Repository
+	if store != nil {
+		if err := store.Set(key, redactDetail(detail)); err != nil && cfg.Verbose {
+			fmt.Fprintf(os.Stderr, "Warning: failed to cache delivery detail: %v\n", err)
+		}
+	}
+	return detail, nil
+}
+
+// repositoriesKey is the cache key of the repository list of an organization
+func repositoriesKey(org string) string {
+	return "repositories:" + org
+}
+
+// teamRepositoriesKey is the cache key of the repository list of a team
+func teamRepositoriesKey(org, team string) string {
+	return fmt.Sprintf("team-repositories:%s/%s", org, team)
+}
+
+// hooksKey is the cache key of the webhooks of a repository
+func hooksKey(repo string) string {
+	return "hooks:" + repo
+}
+
+// deliveriesKey is the cache key of the deliveries of a repository webhook
+func deliveriesKey(repo string, hookID int) string {
+	return fmt.Sprintf("deliveries:%s:%d", repo, hookID)
+}
+
+// orgHooksKey is the cache key of the webhooks of an organization
+func orgHooksKey(org string) string {
+	return "org-hooks:" + org
+}
+
+// orgDeliveriesKey is the cache key of the deliveries of an organization webhook
+func orgDeliveriesKey(org string, hookID int) string {
+	return fmt.Sprintf("org-deliveries:%s:%d", org, hookID)
+}
+
+// repositoryIDKey is the cache key of the ID of a repository
+func repositoryIDKey(repo string) string {
+	return "repository-id:" + repo
+}
+
+// detailKey is the cache key of the detail of a delivery
+func detailKey(repo string, hookID, deliveryID int) string {
+	return fmt.Sprintf("detail:%s:%d:%d", repo, hookID, deliveryID)
+}
+
+// formatAge formats a duration in the largest sensible unit, e.g. 45s, 26h or 3d
+func formatAge(d time.Duration) string {
+	switch {
+	case d >= 48*time.Hour:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	case d >= time.Minute:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	default:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	}
+}
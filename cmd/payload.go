@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ohader/gh-hookmon/internal/cache"
+	"github.com/ohader/gh-hookmon/internal/github"
+	"github.com/ohader/gh-hookmon/internal/schema"
+)
+
+// schemaCacheTTL is how long downloaded event schemas are reused, they only change with GitHub's event formats
+const schemaCacheTTL = 7 * 24 * time.Hour
+
+// payloadSchemas are the event schemas request payloads are validated against, nil without --validate-payload
+var payloadSchemas *schema.Set
+
+// loadPayloadSchemas reads the event schemas from the --payload-schema file or URL
+// Downloaded schemas are cached, offline the cached schemas are used regardless of their age
+func loadPayloadSchemas() (*schema.Set, error) {
+	source := cfg.PayloadSchema
+	if source == "" {
+		source = schema.DefaultURL
+	}
+	if !strings.HasPrefix(source, "https://") && !strings.HasPrefix(source, "http://") {
+		data, err := os.ReadFile(source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read payload schema: %w", err)
+		}
+		return schema.Parse(data)
+	}
+
+	key := schemaKey(source)
+	store, err := cache.New()
+	if err != nil && cfg.Verbose {
+		fmt.Fprintf(os.Stderr, "Warning: cache unavailable: %v\n", err)
+	}
+	if store != nil && !cfg.NoCache {
+		var cached json.RawMessage
+		found, err := store.Get(key, schemaCacheTTL, &cached)
+		if err != nil && cfg.Verbose {
+			fmt.Fprintf(os.Stderr, "Warning: failed to read cached payload schema: %v\n", err)
+		}
+		if found {
+			return schema.Parse(cached)
+		}
+	}
+
+	data, err := downloadSchema(source)
+	if err != nil {
+		return nil, err
+	}
+	set, err := schema.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	if store != nil && !cfg.NoCache {
+		if err := store.Set(key, json.RawMessage(data)); err != nil && cfg.Verbose {
+			fmt.Fprintf(os.Stderr, "Warning: failed to cache payload schema: %v\n", err)
+		}
+	}
+	return set, nil
+}
+
+// downloadSchema fetches the event schemas, honoring --ca-bundle, the proxy settings and --request-timeout
+func downloadSchema(url string) ([]byte, error) {
+	transport, err := github.NewTransport(cfg.CABundle)
+	if err != nil {
+		return nil, err
+	}
+	client := &http.Client{Transport: traceTransport(deadline.transport(transport)), Timeout: cfg.RequestTimeout}
+
+	if cfg.Verbose {
+		fmt.Fprintf(os.Stderr, "Downloading payload schema from %s\n", url)
+	}
+	response, err := client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download payload schema: %w", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download payload schema from %s: %s", url, response.Status)
+	}
+
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download payload schema: %w", err)
+	}
+	return data, nil
+}
+
+// schemaKey is the cache key of event schemas downloaded from a URL
+func schemaKey(url string) string {
+	return "schema:" + url
+}
+
+// validatePayload sets the validation result of a delivery's request payload
+func validatePayload(d *github.Delivery, payload interface{}) {
+	problems := payloadSchemas.Validate(d.Event, d.Action, payload)
+	valid := len(problems) == 0
+	d.PayloadValid = &valid
+	d.PayloadProblems = nil
+	for _, p := range problems {
+		d.PayloadProblems = append(d.PayloadProblems, p.String())
+	}
+}
+
+// withPayloadValidation validates the request payloads of deliveries that have not been validated yet
+// Deliveries whose details cannot be fetched are kept without a validation result
+func withPayloadValidation(deliveries []github.Delivery, fetchDetails func([]github.Delivery) ([]github.Delivery, error)) ([]github.Delivery, error) {
+	var missing []github.Delivery
+	for _, d := range deliveries {
+		if d.PayloadValid == nil {
+			missing = append(missing, d)
+		}
+	}
+	if len(missing) == 0 {
+		return deliveries, nil
+	}
+
+	detailed, err := fetchDetails(missing)
+	if err != nil {
+		return nil, err
+	}
+
+	validated := make(map[int]github.Delivery, len(detailed))
+	for _, d := range detailed {
+		validated[d.ID] = d
+	}
+	result := make([]github.Delivery, len(deliveries))
+	for i, d := range deliveries {
+		result[i] = d
+		if v, ok := validated[d.ID]; ok && v.PayloadValid != nil {
+			result[i].PayloadValid = v.PayloadValid
+			result[i].PayloadProblems = v.PayloadProblems
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ohader/gh-hookmon/internal/filter"
+	"github.com/ohader/gh-hookmon/internal/github"
+	"github.com/ohader/gh-hookmon/internal/output"
+	"github.com/ohader/gh-hookmon/internal/probe"
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+)
+
+// probeOptions holds the flags of the probe subcommand
+type probeOptions struct {
+	Method  string
+	Timeout time.Duration
+	JSON    bool
+}
+
+var probeOpts probeOptions
+
+var probeCmd = &cobra.Command{
+	Use:   "probe",
+	Short: "Check reachability of webhook target URLs",
+	Long: `Issue a lightweight request to each distinct webhook target URL and report
+DNS resolution, TCP connectivity, TLS handshake and HTTP status, next to the
+number of failed deliveries of the hooks pointing to it.
+
+Any HTTP response counts as reachable. Exits with exit code 2 if any endpoint
+is unreachable.
+
+Examples:
+  # Probe all webhook endpoints of an organization
+  gh hookmon probe --org=myorg
+
+  # Use GET instead of HEAD for receivers rejecting HEAD requests
+  gh hookmon probe --repo=owner/repo --method=GET --timeout=5s`,
+	SilenceUsage: true,
+	RunE:         runProbe,
+}
+
+func init() {
+	probeCmd.Flags().StringVar(&probeOpts.Method, "method", "HEAD", "HTTP method of the probe request")
+	probeCmd.Flags().DurationVar(&probeOpts.Timeout, "timeout", 10*time.Second, "Timeout of each probe stage")
+	probeCmd.Flags().BoolVar(&probeOpts.JSON, "json", false, "Output in JSON format")
+	probeCmd.Flags().StringVar(&cfg.Filter, "filter", "", "Filter webhook URLs by pattern")
+	rootCmd.AddCommand(probeCmd)
+}
+
+func runProbe(cmd *cobra.Command, args []string) error {
+	if err := requireOnline("probe"); err != nil {
+		return err
+	}
+	if err := validateTarget(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	// Without delivery history, endpoints are still probed but failures are not counted
+	scan := fetchRepoHookDeliveries
+	if err := requireFeature(client, github.FeatureHookDeliveries); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v, delivery statistics are not available\n", err)
+		scan = func(client *github.Client, repo string) ([]hookDeliveries, error) {
+			hooks, err := client.ListRepoWebhooks(repo)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list webhooks: %w", err)
+			}
+			result := make([]hookDeliveries, 0, len(hooks))
+			for _, hook := range hooks {
+				if hook.MatchesFilter(cfg.Filter) {
+					result = append(result, hookDeliveries{Repository: repo, Hook: hook})
+				}
+			}
+			return result, nil
+		}
+	}
+
+	hooks, err := scanTargets(client, scan)
+	if err != nil {
+		return err
+	}
+
+	// Group hooks and deliveries by target URL
+	rowsByURL := make(map[string]*output.ProbeRow)
+	for _, h := range hooks {
+		targetURL := h.Hook.GetTargetURL()
+		if targetURL == "" {
+			continue
+		}
+		row := rowsByURL[targetURL]
+		if row == nil {
+			row = &output.ProbeRow{Result: probe.Result{URL: targetURL}}
+			rowsByURL[targetURL] = row
+		}
+		row.Hooks++
+		for _, d := range h.Deliveries {
+			row.Deliveries++
+			if filter.IsFailed(d.StatusCode) {
+				row.Failures++
+			}
+		}
+	}
+
+	urls := make([]string, 0, len(rowsByURL))
+	for u := range rowsByURL {
+		urls = append(urls, u)
+	}
+	sort.Strings(urls)
+
+	// Probe endpoints concurrently
+	const maxConcurrent = 10
+	g := new(errgroup.Group)
+	g.SetLimit(maxConcurrent)
+	opts := probe.Options{Method: strings.ToUpper(probeOpts.Method), Timeout: probeOpts.Timeout}
+	for _, u := range urls {
+		row := rowsByURL[u]
+		g.Go(func() error {
+			if cfg.Verbose {
+				fmt.Fprintf(os.Stderr, "Probing %s\n", u)
+			}
+			row.Result = probe.Probe(context.Background(), u, opts)
+			return nil
+		})
+	}
+	g.Wait()
+
+	rows := make([]output.ProbeRow, 0, len(urls))
+	unreachable := 0
+	for _, u := range urls {
+		rows = append(rows, *rowsByURL[u])
+		if !rowsByURL[u].Reachable() {
+			unreachable++
+		}
+	}
+
+	if probeOpts.JSON {
+		if err := output.FormatProbeJSON(rows, os.Stdout); err != nil {
+			return err
+		}
+	} else {
+		output.FormatProbeTable(rows, os.Stdout)
+	}
+
+	if unreachable > 0 {
+		return &ExitError{
+			Code: exitCodeViolation,
+			Err:  fmt.Errorf("%d of %d endpoints are unreachable", unreachable, len(rows)),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	ghconfig "github.com/cli/go-gh/v2/pkg/config"
+	"golang.org/x/term"
+)
+
+// recentRepoChoices is the number of recently pushed repositories offered by the target prompt
+const recentRepoChoices = 10
+
+// validateTarget validates the configuration of commands that scan --org or --repo
+// On a terminal, a missing target is asked for like the gh CLI does instead of failing
+func validateTarget() error {
+	if err := promptTarget(); err != nil {
+		return err
+	}
+	return cfg.Validate()
+}
+
+// promptTarget lets the user select an organization or repository if none is given
+// Nothing is asked without a terminal, offline, for GitHub Apps or with prompts disabled in gh
+func promptTarget() error {
+	if cfg.Org != "" || cfg.Repo != "" || cfg.ReposList != "" || cfg.ReposFile != "" {
+		return nil
+	}
+	if cfg.Offline || cfg.AppID != 0 || !canPrompt() {
+		return nil
+	}
+
+	// The choices only help, a token that may not list them still lets the user type a name
+	var orgs, repos []string
+	if client, err := newClient(); err == nil {
+		orgs, _ = client.ListUserOrgs()
+		repos, _ = client.ListRecentRepos(recentRepoChoices)
+	}
+
+	fmt.Fprintln(os.Stderr, "Neither --org nor --repo given, what do you want to monitor?")
+	choices := make([]string, 0, len(orgs)+len(repos))
+	printChoices := func(title string, names []string) {
+		if len(names) == 0 {
+			return
+		}
+		fmt.Fprintf(os.Stderr, "%s:\n", title)
+		for _, name := range names {
+			choices = append(choices, name)
+			fmt.Fprintf(os.Stderr, "  %2d) %s\n", len(choices), name)
+		}
+	}
+	printChoices("Organizations", orgs)
+	printChoices("Recently pushed repositories", repos)
+	if len(choices) > 0 {
+		fmt.Fprintf(os.Stderr, "Enter a number, an organization or OWNER/REPO: ")
+	} else {
+		fmt.Fprintf(os.Stderr, "Enter an organization or OWNER/REPO: ")
+	}
+
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.TrimSpace(answer)
+	if answer == "" {
+		if err != nil {
+			fmt.Fprintln(os.Stderr)
+		}
+		return fmt.Errorf("no organization or repository selected")
+	}
+	if n, err := strconv.Atoi(answer); err == nil {
+		if n < 1 || n > len(choices) {
+			return fmt.Errorf("no choice %d, enter a number between 1 and %d", n, len(choices))
+		}
+		answer = choices[n-1]
+	}
+
+	if strings.Contains(answer, "/") {
+		cfg.Repo = answer
+		fmt.Fprintf(os.Stderr, "Using --repo=%s\n", answer)
+	} else {
+		cfg.Org = answer
+		fmt.Fprintf(os.Stderr, "Using --org=%s\n", answer)
+	}
+	return nil
+}
+
+// canPrompt reports whether the user can be asked on the terminal
+// GH_PROMPT_DISABLED and "gh config set prompt disabled" turn prompts off as they do for gh
+func canPrompt() bool {
+	if !term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stderr.Fd())) {
+		return false
+	}
+	if os.Getenv("GH_PROMPT_DISABLED") != "" {
+		return false
+	}
+	if ghCfg, err := ghconfig.Read(nil); err == nil {
+		if prompt, _ := ghCfg.Get([]string{"prompt"}); prompt == "disabled" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ohader/gh-hookmon/internal/github"
+	"github.com/ohader/gh-hookmon/internal/output"
+	"golang.org/x/term"
+)
+
+// rateLimits records the API budget reported by the responses of the run
+// Requests hitting a secondary rate limit are sent again after the wait GitHub asks for
+var rateLimits = github.NewRateLimits()
+
+func init() {
+	rateLimits.OnSecondaryLimit = apiWorkers.throttle
+}
+
+// maxAPIWorkers is the number of workers sending API requests concurrently, until a secondary rate limit lowers it
+const maxAPIWorkers = 10
+
+// apiWorkers bounds the workers of the repository scan and delivery detail pools together
+var apiWorkers = newWorkerGate(maxAPIWorkers)
+
+// workerGate bounds the number of concurrent workers, its limit can be lowered while workers run
+type workerGate struct {
+	mu          sync.Mutex
+	cond        *sync.Cond
+	limit       int
+	active      int
+	calmedUntil time.Time // Secondary rate limits hit before are answered by the last reduction already
+}
+
+// newWorkerGate creates a gate admitting limit workers at a time
+func newWorkerGate(limit int) *workerGate {
+	g := &workerGate{limit: limit}
+	g.cond = sync.NewCond(&g.mu)
+	return g
+}
+
+// acquire waits until the worker may run, every acquire must be followed by a release
+func (g *workerGate) acquire() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for g.active >= g.limit {
+		g.cond.Wait()
+	}
+	g.active++
+}
+
+// release lets the next worker run
+func (g *workerGate) release() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.active--
+	g.cond.Broadcast()
+}
+
+// throttle halves the number of concurrent workers for the rest of the run after a secondary rate limit
+// Concurrent requests usually hit the limit together, so it is halved once per wait
+func (g *workerGate) throttle(wait time.Duration) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	now := time.Now()
+	if now.Before(g.calmedUntil) {
+		return
+	}
+	g.calmedUntil = now.Add(wait)
+	g.limit = max(g.limit/2, 1)
+	warnAlways(output.Issue{
+		Kind:    output.IssueRateLimited,
+		Message: fmt.Sprintf("GitHub secondary rate limit hit, pausing requests for %s and reducing concurrency to %d", wait, g.limit),
+	})
+}
+
+// rateLimitResource is the rate limit resource of REST requests, which make up most of a scan
+const rateLimitResource = "core"
+
+// budgetProjection warns once if a scan is projected to use up the remaining API budget before it resets
+type budgetProjection struct {
+	once      sync.Once
+	requests  int // Responses recorded before the scan started
+	minSample int // Repositories scanned before their average cost is projected
+}
+
+// newBudgetProjection starts projecting the API budget of a scan
+func newBudgetProjection() *budgetProjection {
+	return &budgetProjection{requests: rateLimits.Requests(rateLimitResource), minSample: 5}
+}
+
+// check projects the remaining scan from the average number of requests of the repositories done so far
+// Before any repository is done, every remaining repository is assumed to cost at least one request
+func (p *budgetProjection) check(done, total int) {
+	budget, ok := rateLimits.Get(rateLimitResource)
+	if !ok || done >= total || !time.Now().Before(budget.Reset) {
+		return
+	}
+
+	perRepo := 1.0
+	switch {
+	case done == 0:
+		// Every remaining repository costs at least the request listing its hooks
+	case done >= p.minSample:
+		perRepo = float64(rateLimits.Requests(rateLimitResource)-p.requests) / float64(done)
+	default:
+		// Too few repositories for a meaningful average
+		return
+	}
+
+	projected := int(perRepo * float64(total-done))
+	if projected <= budget.Remaining {
+		return
+	}
+	p.once.Do(func() {
+		warnAlways(output.Issue{
+			Kind: output.IssueRateLimitBudget,
+			Message: fmt.Sprintf("the remaining %d repositories are projected to need about %s API requests, but only %s remain until %s, later repositories may fail",
+				total-done, formatThousands(projected), formatThousands(budget.Remaining), budget.Reset.Local().Format("15:04")),
+		})
+	})
+}
+
+// printRateLimit prints the remaining API budget after a run
+// It is shown with --verbose or if stderr is a terminal, scheduled runs are kept quiet
+func printRateLimit() {
+	if !cfg.Verbose && !term.IsTerminal(int(os.Stderr.Fd())) {
+		return
+	}
+	if budget, ok := rateLimits.Get(rateLimitResource); ok {
+		fmt.Fprintf(os.Stderr, "API budget: %s\n", formatRateLimit(budget))
+	}
+}
+
+// formatRateLimit describes a budget like "3,812 remaining, resets 14:03"
+func formatRateLimit(budget github.RateLimit) string {
+	return fmt.Sprintf("%s remaining, resets %s", formatThousands(budget.Remaining), budget.Reset.Local().Format("15:04"))
+}
+
+// formatThousands formats n with thousands separators, e.g. 12,345
+func formatThousands(n int) string {
+	s := strconv.Itoa(n)
+	if n < 0 {
+		return "-" + formatThousands(-n)
+	}
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"github.com/ohader/gh-hookmon/internal/github"
+	"github.com/ohader/gh-hookmon/internal/redact"
+)
+
+// redactDetail returns a copy of a delivery detail with sensitive headers and the values matching
+// the redaction rules of the config file replaced, the detail itself is left unchanged
+func redactDetail(detail *github.DeliveryDetail) *github.DeliveryDetail {
+	redacted := *detail
+	redacted.Request.Headers = redact.Headers(detail.Request.Headers)
+	redacted.Response.Headers = redact.Headers(detail.Response.Headers)
+	redacted.Request.Payload = redactor.Payload(detail.Request.Payload)
+	redacted.Response.Payload = redactor.Text(detail.Response.Payload)
+	return &redacted
+}
+
+// outputDetail returns the delivery detail to print, redacted unless --no-redact is set
+func outputDetail(detail *github.DeliveryDetail) *github.DeliveryDetail {
+	if cfg.NoRedact {
+		return detail
+	}
+	return redactDetail(detail)
+}
+
+// outputPayload returns a request payload to print or extract fields from, redacted unless --no-redact is set
+func outputPayload(payload interface{}) interface{} {
+	if cfg.NoRedact {
+		return payload
+	}
+	return redactor.Payload(payload)
+}
+
+// outputText returns a response body to print, redacted unless --no-redact is set
+func outputText(text string) string {
+	if cfg.NoRedact {
+		return text
+	}
+	return redactor.Text(text)
+}
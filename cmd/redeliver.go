@@ -0,0 +1,350 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ohader/gh-hookmon/internal/filter"
+	"github.com/ohader/gh-hookmon/internal/github"
+	"github.com/spf13/cobra"
+)
+
+// redeliverOptions holds the flags of the redeliver subcommand
+type redeliverOptions struct {
+	Hook         int
+	Stdin        bool
+	UntilSuccess bool
+	Retries      int
+	Backoff      time.Duration
+	PollTimeout  time.Duration
+	Throttle     string
+	BatchSize    int
+	BatchPause   time.Duration
+}
+
+var redeliverOpts redeliverOptions
+
+// pollInterval is how often the delivery list is checked for a new attempt
+const pollInterval = 2 * time.Second
+
+var redeliverCmd = &cobra.Command{
+	Use:   "redeliver [DELIVERY-ID|GUID...]",
+	Short: "Redeliver webhook deliveries",
+	Long: `Trigger new delivery attempts for webhook deliveries of a repository.
+
+Deliveries are identified by delivery ID or GUID. With --until-success the
+command waits for each new attempt to complete and retries failed attempts
+with exponential backoff.
+
+Examples:
+  # Redeliver a single delivery
+  gh hookmon redeliver --repo=owner/repo 12345678
+
+  # Redeliver all failed deliveries from today
+  gh hookmon --repo=owner/repo --failed --since=2026-01-20 --output=guids | gh hookmon redeliver --repo=owner/repo --stdin
+
+  # Retry until the receiver accepts the delivery, at most 3 more times
+  gh hookmon redeliver --repo=owner/repo --until-success --retries=3 12345678
+
+  # Pace bulk redeliveries: at most 5 per second, pausing 1m after every 100
+  gh hookmon redeliver --repo=owner/repo --stdin --throttle=5/s --batch-size=100 --batch-pause=1m < guids.txt`,
+	SilenceUsage: true,
+	RunE:         runRedeliver,
+}
+
+func init() {
+	redeliverCmd.Flags().IntVar(&redeliverOpts.Hook, "hook", 0, "Only look up deliveries of this hook ID")
+	redeliverCmd.Flags().BoolVar(&redeliverOpts.Stdin, "stdin", false, "Read delivery IDs or GUIDs from stdin, one per line")
+	redeliverCmd.Flags().BoolVar(&redeliverOpts.UntilSuccess, "until-success", false, "Wait for each attempt and retry until it succeeds")
+	redeliverCmd.Flags().IntVar(&redeliverOpts.Retries, "retries", 3, "Maximum number of retries with --until-success")
+	redeliverCmd.Flags().DurationVar(&redeliverOpts.Backoff, "backoff", 10*time.Second, "Initial wait between retries, doubled after each attempt")
+	redeliverCmd.Flags().DurationVar(&redeliverOpts.PollTimeout, "poll-timeout", time.Minute, "Maximum time to wait for an attempt to show up")
+	redeliverCmd.Flags().StringVar(&redeliverOpts.Throttle, "throttle", "", "Maximum redelivery rate, e.g. 5/s, 30/m or 500/h (default: unlimited)")
+	redeliverCmd.Flags().IntVar(&redeliverOpts.BatchSize, "batch-size", 0, "Pause after every N redeliveries (default: no batches)")
+	redeliverCmd.Flags().DurationVar(&redeliverOpts.BatchPause, "batch-pause", 30*time.Second, "Pause between batches with --batch-size")
+	rootCmd.AddCommand(redeliverCmd)
+}
+
+func runRedeliver(cmd *cobra.Command, args []string) error {
+	if err := requireOnline("redeliver"); err != nil {
+		return err
+	}
+	if cfg.Repo == "" {
+		return fmt.Errorf("validation error: redeliver requires --repo")
+	}
+	if cfg.Org != "" {
+		return fmt.Errorf("validation error: redeliver does not support --org")
+	}
+	if err := validateTarget(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+	if redeliverOpts.Retries < 0 {
+		return fmt.Errorf("validation error: --retries must be a non-negative integer")
+	}
+	if redeliverOpts.BatchSize < 0 {
+		return fmt.Errorf("validation error: --batch-size must be a non-negative integer")
+	}
+	interval, err := parseRate(redeliverOpts.Throttle)
+	if err != nil {
+		return fmt.Errorf("validation error: --throttle: %w", err)
+	}
+	pace := &pacer{interval: interval}
+
+	identifiers := args
+	if redeliverOpts.Stdin {
+		stdinIdentifiers, err := readIdentifiers(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("failed to read stdin: %w", err)
+		}
+		identifiers = append(identifiers, stdinIdentifiers...)
+	}
+	if len(identifiers) == 0 {
+		return fmt.Errorf("validation error: no delivery IDs or GUIDs given")
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	if err := requireFeature(client, github.FeatureRedelivery); err != nil {
+		return err
+	}
+
+	targets, unresolved, err := resolveDeliveries(client, cfg.Repo, redeliverOpts.Hook, identifiers)
+	if err != nil {
+		return err
+	}
+	for _, id := range unresolved {
+		fmt.Fprintf(os.Stderr, "Warning: delivery %s not found in the latest %d deliveries per hook of %s, raise --limit to search further\n", id, lookupLimit(), cfg.Repo)
+	}
+
+	failures := len(unresolved)
+	for i, target := range targets {
+		if redeliverOpts.BatchSize > 0 && i > 0 && i%redeliverOpts.BatchSize == 0 {
+			if cfg.Verbose {
+				fmt.Fprintf(os.Stderr, "Completed batch of %d redeliveries, pausing for %s\n", redeliverOpts.BatchSize, redeliverOpts.BatchPause)
+			}
+			time.Sleep(redeliverOpts.BatchPause)
+		}
+		if !redeliver(client, target, pace) {
+			failures++
+		}
+	}
+
+	if failures > 0 {
+		return fmt.Errorf("%d of %d redeliveries did not succeed", failures, len(identifiers))
+	}
+	return nil
+}
+
+// readIdentifiers reads one delivery ID or GUID per line, skipping empty lines and comments
+func readIdentifiers(r io.Reader) ([]string, error) {
+	var identifiers []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		identifiers = append(identifiers, line)
+	}
+	return identifiers, scanner.Err()
+}
+
+// resolveDeliveries maps delivery IDs and GUIDs to deliveries of the repository's hooks
+// GUIDs shared by several attempts resolve to the most recent attempt
+// The latest lookupLimit deliveries of every hook are searched
+func resolveDeliveries(client *github.Client, repo string, hookID int, identifiers []string) ([]github.Delivery, []string, error) {
+	hookIDs := []int{hookID}
+	if hookID == 0 {
+		hooks, err := client.ListRepoWebhooks(repo)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to list webhooks: %w", err)
+		}
+		hookIDs = hookIDs[:0]
+		for _, hook := range hooks {
+			hookIDs = append(hookIDs, hook.ID)
+		}
+	}
+
+	byKey := make(map[string]github.Delivery)
+	for _, hookID := range hookIDs {
+		deliveries, err := client.ListRepoHookDeliveries(repo, hookID, lookupLimit())
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to list deliveries for hook %d: %w", hookID, err)
+		}
+		for _, d := range deliveries {
+			byKey[strconv.Itoa(d.ID)] = d
+			if existing, ok := byKey[d.GUID]; !ok || d.DeliveredAt.After(existing.DeliveredAt) {
+				byKey[d.GUID] = d
+			}
+		}
+	}
+
+	var targets []github.Delivery
+	var unresolved []string
+	for _, id := range identifiers {
+		d, ok := byKey[id]
+		if !ok {
+			unresolved = append(unresolved, id)
+			continue
+		}
+		targets = append(targets, d)
+	}
+
+	return targets, unresolved, nil
+}
+
+// redeliver triggers a new attempt for the delivery and reports the outcome
+// With --until-success failed attempts are retried with exponential backoff
+func redeliver(client *github.Client, d github.Delivery, pace *pacer) bool {
+	backoff := redeliverOpts.Backoff
+
+	// Attempts that already exist for the GUID must not be mistaken for the new one
+	lastSeenID := d.ID
+	if redeliverOpts.UntilSuccess {
+		latest, err := latestAttemptID(client, d)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: delivery %d (%s): %v\n", d.ID, d.GUID, err)
+			return false
+		}
+		lastSeenID = latest
+	}
+
+	for attempt := 0; ; attempt++ {
+		pace.wait()
+		if err := client.RedeliverRepoHookDelivery(d.Repository, d.HookID, d.ID); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return false
+		}
+
+		if !redeliverOpts.UntilSuccess {
+			fmt.Printf("Redelivery of %d (%s) to hook %d requested\n", d.ID, d.GUID, d.HookID)
+			return true
+		}
+
+		result, err := awaitAttempt(client, d, lastSeenID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: delivery %d (%s): %v\n", d.ID, d.GUID, err)
+			return false
+		}
+		lastSeenID = result.ID
+
+		if !filter.IsFailed(result.StatusCode) {
+			fmt.Printf("Redelivery of %d (%s) to hook %d succeeded: %d %s (attempt %d)\n", d.ID, d.GUID, d.HookID, result.StatusCode, result.Status, attempt+1)
+			return true
+		}
+
+		fmt.Fprintf(os.Stderr, "Redelivery of %d (%s) to hook %d failed: %d %s (attempt %d)\n", d.ID, d.GUID, d.HookID, result.StatusCode, result.Status, attempt+1)
+		if attempt >= redeliverOpts.Retries {
+			return false
+		}
+
+		if cfg.Verbose {
+			fmt.Fprintf(os.Stderr, "Retrying in %s\n", backoff)
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// latestAttemptID returns the highest delivery ID of all attempts sharing the delivery's GUID
+func latestAttemptID(client *github.Client, d github.Delivery) (int, error) {
+	deliveries, err := client.ListRepoHookDeliveries(d.Repository, d.HookID, lookupLimit())
+	if err != nil {
+		return 0, err
+	}
+
+	latest := d.ID
+	for _, other := range deliveries {
+		if other.GUID == d.GUID && other.ID > latest {
+			latest = other.ID
+		}
+	}
+	return latest, nil
+}
+
+// lookupLimit is the number of recent deliveries per hook searched for a delivery ID, GUID or attempt
+// It follows --limit, so deliveries copied from a longer listing are found as well
+func lookupLimit() int {
+	return max(cfg.Limit, deliveriesPerHook)
+}
+
+// awaitAttempt polls the hook's deliveries until a redelivery with the same GUID
+// and an ID newer than lastSeenID shows up
+func awaitAttempt(client *github.Client, d github.Delivery, lastSeenID int) (*github.Delivery, error) {
+	deadline := time.Now().Add(redeliverOpts.PollTimeout)
+
+	for {
+		deliveries, err := client.ListRepoHookDeliveries(d.Repository, d.HookID, lookupLimit())
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range deliveries {
+			if deliveries[i].GUID == d.GUID && deliveries[i].Redelivery && deliveries[i].ID > lastSeenID {
+				return &deliveries[i], nil
+			}
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("no new attempt showed up within %s", redeliverOpts.PollTimeout)
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+// pacer spaces out operations so that at most one happens per interval
+type pacer struct {
+	interval time.Duration
+	last     time.Time
+}
+
+// wait blocks until the next operation is allowed
+func (p *pacer) wait() {
+	if p.interval <= 0 {
+		return
+	}
+	if next := p.last.Add(p.interval); time.Now().Before(next) {
+		time.Sleep(time.Until(next))
+	}
+	p.last = time.Now()
+}
+
+// parseRate parses a rate like "5/s", "30/m" or "500/h" into the interval between operations
+// An empty rate means no limit and returns a zero interval
+func parseRate(rate string) (time.Duration, error) {
+	if rate == "" {
+		return 0, nil
+	}
+
+	count, unit, ok := strings.Cut(rate, "/")
+	if !ok {
+		return 0, fmt.Errorf("rate must be in format N/s, N/m or N/h")
+	}
+
+	n, err := strconv.ParseFloat(count, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("rate must be a positive number, got %q", count)
+	}
+
+	var per time.Duration
+	switch unit {
+	case "s":
+		per = time.Second
+	case "m":
+		per = time.Minute
+	case "h":
+		per = time.Hour
+	default:
+		return 0, fmt.Errorf("rate unit must be s, m or h, got %q", unit)
+	}
+
+	return time.Duration(float64(per) / n), nil
+}
@@ -1,18 +1,38 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"strings"
+	"sync/atomic"
+	"time"
 
+	"github.com/ohader/gh-hookmon/internal/cache"
 	"github.com/ohader/gh-hookmon/internal/config"
 	"github.com/ohader/gh-hookmon/internal/filter"
 	"github.com/ohader/gh-hookmon/internal/github"
 	"github.com/ohader/gh-hookmon/internal/output"
+	"github.com/ohader/gh-hookmon/internal/redact"
+	"github.com/ohader/gh-hookmon/internal/stats"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/term"
 )
 
 var cfg config.Config
 
+// configPath is the --config flag, fileCfg the loaded configuration file
+// and redactor the payload redaction rules it declares
+var (
+	configPath string
+	fileCfg    = &config.File{}
+	redactor   *redact.Rules
+)
+
 var rootCmd = &cobra.Command{
 	Use:   "gh-hookmon",
 	Short: "Monitor GitHub webhook deliveries",
@@ -31,6 +51,10 @@ Examples:
   # Filter by date range
   gh hookmon --org=myorg --since=2026-01-01 --until=2026-01-31
 
+  # Filter by delivery ID range or explicit IDs
+  gh hookmon --repo=owner/repo --delivery-id=">=12345678"
+  gh hookmon --repo=owner/repo --delivery-id=111,222
+
   # Show only failed deliveries
   gh hookmon --org=myorg --failed
 
@@ -55,109 +79,449 @@ Examples:
   # Combine with filters and sorting
   gh hookmon --org=myorg --failed --sort=repository:asc --head=5
 
+  # Only show deliveries of classic webhooks, excluding GitHub App traffic
+  gh hookmon --org=myorg --installation=none
+
+  # Show the most recent delivery of every hook
+  gh hookmon --org=myorg --latest-per-hook
+
+  # Fail with a non-zero exit code if any repository could not be scanned
+  gh hookmon --org=myorg --strict
+
   # Output as JSON
-  gh hookmon --repo=owner/repo --json`,
-	RunE: run,
+  gh hookmon --repo=owner/repo --output=json
+
+  # Output as JSON including warnings and errors about incomplete results
+  gh hookmon --org=myorg --output=json --include-warnings
+
+  # Failed deliveries as CSV for a spreadsheet
+  gh hookmon --org=myorg --failed --output=csv > failed.csv
+
+  # Print only the GUIDs of failed deliveries, one per line
+  gh hookmon --repo=owner/repo --failed --output=guids
+
+  # Run the failed-week alias of the config file
+  gh hookmon @failed-week --org=myorg`,
+	PersistentPreRunE: preRun,
+	RunE:              run,
 }
 
 func init() {
-	rootCmd.Flags().StringVar(&cfg.Org, "org", "", "Process all repos in organization (required if --repo not set)")
-	rootCmd.Flags().StringVar(&cfg.Repo, "repo", "", "Process specific repository OWNER/REPO (required if --org not set)")
-	rootCmd.Flags().StringVar(&cfg.Filter, "filter", "", "Filter webhook URLs by pattern")
-	rootCmd.Flags().String("since", "", "Start date YYYY-MM-DD (00:00:00)")
-	rootCmd.Flags().String("until", "", "End date YYYY-MM-DD (23:59:59)")
-	rootCmd.Flags().BoolVar(&cfg.JSONOutput, "json", false, "Output in JSON format")
-	rootCmd.Flags().BoolVar(&cfg.Failed, "failed", false, "Filter for failed webhook deliveries (4xx, 5xx, or no response)")
-	rootCmd.Flags().BoolVar(&cfg.LastFailed, "last-failed", false, "Filter repos where the most recent delivery failed")
-	rootCmd.Flags().IntVar(&cfg.Head, "head", 0, "Show only N most recent deliveries per repository (default: all)")
-	rootCmd.Flags().StringVar(&cfg.SortBy, "sort", "", "Sort by field (repository, timestamp, code, event) with optional order (:asc or :desc)")
-	rootCmd.Flags().BoolVarP(&cfg.Verbose, "verbose", "v", false, "Enable verbose output")
+	rootCmd.PersistentFlags().StringVar(&cfg.Org, "org", "", "Process all repos in organization (required if --repo not set), with --repo include the organization's hooks")
+	rootCmd.PersistentFlags().StringVar(&cfg.Team, "team", "", "Process the repositories of team ORG/TEAM instead of the whole organization")
+	rootCmd.PersistentFlags().StringVar(&cfg.Repo, "repo", "", "Process specific repository OWNER/REPO (required if --org not set)")
+	rootCmd.PersistentFlags().BoolVar(&cfg.IncludeForks, "include-forks", false, "Also scan the forks of an organization, which are skipped by default")
+	rootCmd.PersistentFlags().StringVar(&cfg.Language, "language", "", "Only scan organization repositories with these primary languages, e.g. Go,TypeScript")
+	rootCmd.PersistentFlags().StringVar(&cfg.ActiveSince, "active-since", "", "Only scan organization repositories pushed to within this duration, e.g. 90d")
+	rootCmd.PersistentFlags().StringVar(&cfg.ReposList, "repos", "", "Process these comma-separated repositories OWNER/REPO, - reads one per line from stdin")
+	rootCmd.PersistentFlags().StringVar(&cfg.ReposFile, "repos-file", "", "Process the repositories listed in this file, one OWNER/REPO per line, # starts a comment")
+	addDeliveryListFlags(rootCmd.Flags())
+	rootCmd.Flags().StringVar(&cfg.Ref, "ref", "", "Filter by the git ref of the request payload, e.g. refs/heads/main (fetches delivery details)")
+	rootCmd.Flags().StringVar(&cfg.Branch, "branch", "", "Filter by the branch of the request payload, e.g. main (fetches delivery details)")
+	rootCmd.Flags().StringVar(&cfg.Sender, "sender", "", "Filter by the sender login of the request payload, e.g. dependabot[bot] (fetches delivery details)")
+	rootCmd.Flags().StringVar(&cfg.ExcludeSender, "exclude-sender", "", "Drop deliveries sent by these logins, e.g. dependabot[bot],renovate[bot] (fetches delivery details)")
+	rootCmd.Flags().StringVar(&cfg.PullRequest, "pr", "", "Filter by pull request numbers of the request payload, e.g. 1234 (fetches delivery details)")
+	rootCmd.Flags().StringVar(&cfg.Issue, "issue", "", "Filter by issue numbers of the request payload, e.g. 567 (fetches delivery details)")
+	rootCmd.Flags().BoolVar(&cfg.Details, "details", false, "Fetch the details of every listed delivery for its target URL, payload size and response summary")
+	rootCmd.Flags().StringVar(&cfg.Extract, "extract", "", "Show payload fields as extra columns, e.g. pull_request.head.sha,sender.login (fetches delivery details)")
+	rootCmd.Flags().IntVar(&cfg.ResponseExcerpt, "response-excerpt", 0, "Fetch and record the first N bytes of the response body of failed deliveries")
+	rootCmd.Flags().BoolVar(&cfg.ValidatePayload, "validate-payload", false, "Fetch request payloads and validate them against the webhook event schemas")
+	rootCmd.Flags().StringVar(&cfg.PayloadSchema, "payload-schema", "", "File or URL of the webhook event schemas (default: the octokit/webhooks schemas)")
+	rootCmd.Flags().BoolVar(&cfg.RefreshRepos, "refresh-repos", false, "Ignore the cached organization repository list and fetch it again")
+	rootCmd.Flags().BoolVar(&cfg.Strict, "strict", false, "Exit with an error if any repository, hook or delivery detail fails instead of warning")
+	rootCmd.Flags().BoolVar(&cfg.FailFast, "fail-fast", false, "Abort on the first failure and cancel remaining workers (implies --strict)")
+	rootCmd.PersistentFlags().StringVar(&configPath, "config", "", "Path to the config file (default: ~/.config/gh-hookmon/config.yml)")
+	rootCmd.PersistentFlags().StringVar(&cfg.Profile, "profile", "", "Use the host, credentials and default organization of a config file profile")
+	rootCmd.PersistentFlags().IntVar(&cfg.Limit, "limit", deliveriesPerHook, "Number of recent deliveries fetched per hook, more than 100 fetches several pages")
+	rootCmd.PersistentFlags().StringVar(&cfg.CABundle, "ca-bundle", "", "Trust the CA certificates in this PEM file, e.g. of a corporate proxy")
+	rootCmd.PersistentFlags().StringVar(&cfg.Token, "token", "", "GitHub API token (default: GH_TOKEN or GITHUB_TOKEN, then the gh CLI login)")
+	rootCmd.PersistentFlags().Int64Var(&cfg.AppID, "app-id", 0, "Authenticate as a GitHub App with this ID instead of the gh CLI login")
+	rootCmd.PersistentFlags().StringVar(&cfg.PrivateKey, "private-key", "", "Path to the GitHub App private key (PEM)")
+	rootCmd.PersistentFlags().Int64Var(&cfg.AppInstallation, "app-installation-id", 0, "GitHub App installation ID (default: looked up for --org or --repo)")
+	rootCmd.PersistentFlags().DurationVar(&cfg.CacheTTL, "cache-ttl", defaultCacheTTL, "How long cached organization repository lists are reused")
+	rootCmd.PersistentFlags().DurationVar(&cfg.HooklessTTL, "hookless-ttl", 0, "Skip repositories found without webhooks for this long when scanning deliveries (0 = never skip them)")
+	rootCmd.PersistentFlags().BoolVar(&cfg.Refresh, "refresh", false, "Ignore cached repository lists and scan repositories remembered without webhooks again")
+	rootCmd.PersistentFlags().BoolVar(&cfg.NoCache, "no-cache", false, "Neither read nor write the on-disk cache")
+	rootCmd.PersistentFlags().BoolVar(&cfg.Offline, "offline", false, "Answer from data cached by earlier runs without any API request")
+	rootCmd.PersistentFlags().StringVar(&cfg.HistoryDB, "history-db", "", "Path of the history database (default: gh-hookmon/history.db in the user cache directory, e.g. ~/.cache)")
+	rootCmd.PersistentFlags().BoolVar(&cfg.RecordHistory, "record", false, "Record fetched deliveries in the history database (default: only collect records)")
+	rootCmd.PersistentFlags().StringVar(&cfg.Retention, "retention", "", "Delete recorded deliveries older than this from the history database, e.g. 90d (default: keep all)")
+	rootCmd.PersistentFlags().DurationVar(&cfg.RequestTimeout, "request-timeout", 30*time.Second, "Time limit of each API request (0 = no limit)")
+	rootCmd.PersistentFlags().DurationVar(&cfg.RepoTimeout, "repo-timeout", defaultRepoTimeout, "Time limit of scanning a single repository including retries (0 = no limit)")
+	rootCmd.PersistentFlags().IntVar(&cfg.RepoRetries, "repo-retries", defaultRepoRetries, "Scan a repository again this many times if it fails with a server error or request timeout")
+	rootCmd.PersistentFlags().BoolVar(&cfg.ShowSkipped, "show-skipped", false, "List the repositories skipped for missing permissions (403 or 404) after a scan")
+	rootCmd.PersistentFlags().StringVar(&cfg.OnForbidden, "on-forbidden", "skip", "Handle repositories denied with 403 or 404: skip, report (list them, also in the JSON envelope) or fail (exit code 4)")
+	rootCmd.PersistentFlags().DurationVar(&cfg.Deadline, "deadline", 0, "Time limit of the whole run, e.g. 10m, results are partial when it is exceeded (exit code 3)")
+	rootCmd.PersistentFlags().BoolVar(&cfg.NoRedact, "no-redact", false, "Show signatures, credentials and redacted payload values in output (trusted contexts only)")
+	rootCmd.PersistentFlags().StringVar(&cfg.OtelEndpoint, "otel-endpoint", "", "Export OpenTelemetry traces of the run to this OTLP/HTTP endpoint, e.g. http://localhost:4318")
+	rootCmd.PersistentFlags().BoolVarP(&cfg.Verbose, "verbose", "v", false, "Enable verbose output")
+}
+
+// addDeliveryListFlags registers the filter, sort and output flags of delivery listings
+func addDeliveryListFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&cfg.Filter, "filter", "", "Filter webhook URLs by pattern")
+	flags.StringVar(&cfg.DeliveryID, "delivery-id", "", "Filter by delivery IDs: list (111,222), comparison (>=123) or range (100-200)")
+	flags.StringVar(&cfg.Installation, "installation", "", "Filter by GitHub App installation: none (classic webhooks), any, or installation IDs")
+	flags.String("since", "", "Start date YYYY-MM-DD (00:00:00)")
+	flags.String("until", "", "End date YYYY-MM-DD (23:59:59)")
+	flags.BoolVar(&cfg.JSONOutput, "json", false, "Output in JSON format")
+	flags.MarkDeprecated("json", "use --output=json instead")
+	flags.StringVarP(&cfg.Output, "output", "o", "", "Output format ("+strings.Join(output.FormatNames(), ", ")+")")
+	flags.BoolVar(&cfg.Compact, "compact", false, "Show a status glyph and code instead of the status text in tables")
+	flags.BoolVar(&cfg.Wide, "wide", false, "Show all table columns even if the table is wider than the terminal")
+	flags.StringVar(&cfg.TimeFormat, "time-format", "rfc3339", "Timestamp format of tables: rfc3339 or relative (e.g. 3m ago)")
+	flags.StringVar(&cfg.SummaryBy, "summary-by", "", "Show one summary row per repository or hook instead of the deliveries")
+	flags.BoolVar(&cfg.DurationDelta, "duration-delta", false, "Compare every duration with the rolling median of its hook, e.g. +340%")
+	flags.BoolVar(&cfg.IncludeWarnings, "include-warnings", false, "Wrap JSON output in an envelope with warnings and errors")
+	flags.BoolVar(&cfg.Flatten, "flatten", false, "Emit flat JSON, NDJSON or YAML objects including repository and hook_id, extracted fields as field_<path>")
+	flags.IntVar(&cfg.FormatVersion, "format-version", 0, "Version of the JSON output to keep pipelines stable across releases (default: latest)")
+	flags.BoolVar(&cfg.Failed, "failed", false, "Filter for failed webhook deliveries (4xx, 5xx, or no response)")
+	flags.StringVar(&cfg.ErrorClass, "error-class", "", "Filter failed deliveries by error class (timeout, dns, tls, client, server)")
+	flags.BoolVar(&cfg.LastFailed, "last-failed", false, "Filter repos where the most recent delivery failed")
+	flags.IntVar(&cfg.Head, "head", 0, "Show only N most recent deliveries per repository (default: all)")
+	flags.BoolVar(&cfg.LatestPerHook, "latest-per-hook", false, "Show only the most recent delivery of each hook")
+	flags.StringVar(&cfg.SortBy, "sort", "", "Sort by field (repository, timestamp, code, event) with optional order (:asc or :desc)")
 }
 
 func Execute() error {
-	return rootCmd.Execute()
+	args, err := expandAlias(os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return err
+	}
+	rootCmd.SetArgs(args)
+
+	err = rootCmd.Execute()
+	err = forbiddenExitError(err)
+	printRateLimit()
+	stopTracing(err)
+	deadline.stop()
+	offline.report()
+	closeHistory()
+	return deadline.exitError(err)
+}
+
+// preRun starts the --deadline countdown and loads the configuration file before any command runs
+func preRun(cmd *cobra.Command, args []string) error {
+	// --team scans part of its organization, everything else applies to the organization as a whole
+	if org, _ := cfg.GetTeam(); org != "" && cfg.Org == "" {
+		cfg.Org = org
+	}
+	if err := loadRepoList(); err != nil {
+		return err
+	}
+	deadline.start(cfg.Deadline)
+	startTracing(cmd)
+	return loadConfigFile(cmd, args)
+}
+
+// loadRepoList reads the repositories of --repos or --repos-file
+// --repos=- reads them from stdin, e.g. piped from gh repo list
+func loadRepoList() error {
+	var repos []string
+	var err error
+	switch {
+	case cfg.ReposList == "-":
+		if term.IsTerminal(int(os.Stdin.Fd())) {
+			return fmt.Errorf("--repos=- reads the repository list from stdin, e.g. gh repo list myorg --json nameWithOwner -q '.[].nameWithOwner' | gh hookmon --repos -")
+		}
+		repos, err = config.ReadRepoList(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("--repos: stdin: %w", err)
+		}
+	case cfg.ReposList != "":
+		repos, err = config.ParseRepoList(cfg.ReposList)
+		if err != nil {
+			return fmt.Errorf("--repos: %w", err)
+		}
+	case cfg.ReposFile != "":
+		repos, err = config.LoadRepoList(cfg.ReposFile)
+		if err != nil {
+			return fmt.Errorf("--repos-file: %w", err)
+		}
+	}
+	cfg.Repos = repos
+	return nil
+}
+
+// loadConfigFile loads the configuration file before any command runs
+// The default location is optional, an explicit --config path must exist
+func loadConfigFile(cmd *cobra.Command, args []string) error {
+	path := configPath
+	required := path != ""
+	if !required {
+		defaultPath, err := config.DefaultConfigPath()
+		if err != nil {
+			return nil
+		}
+		path = defaultPath
+	}
+
+	loaded, err := config.LoadFile(path, required)
+	if err != nil {
+		return err
+	}
+	fileCfg = loaded
+
+	if cfg.Retention == "" {
+		cfg.Retention = fileCfg.Retention
+	}
+	redactor, err = redact.NewRules(fileCfg.Redact.Fields, fileCfg.Redact.Patterns)
+	if err != nil {
+		return fmt.Errorf("redact: %w", err)
+	}
+	theme, err := output.NewTheme(fileCfg.Theme)
+	if err != nil {
+		return fmt.Errorf("theme: %w", err)
+	}
+	output.SetTheme(theme)
+
+	profile, err := fileCfg.GetProfile(cfg.Profile)
+	if err != nil {
+		return fmt.Errorf("--profile: %w", err)
+	}
+
+	// App credentials from the profile or the config file apply unless given as flags
+	app := fileCfg.App
+	if profile != nil {
+		cfg.Host = profile.Host
+		if cfg.CABundle == "" {
+			cfg.CABundle = profile.CABundle
+		}
+		if cfg.Org == "" && cfg.Repo == "" && cfg.Team == "" && len(cfg.Repos) == 0 {
+			cfg.Org = profile.Org
+		}
+		if profile.App.ID != 0 {
+			app = profile.App
+		}
+		if cfg.Token == "" && cfg.AppID == 0 {
+			token, err := profile.Token()
+			if err != nil {
+				return fmt.Errorf("profile: %w", err)
+			}
+			cfg.Token = token
+		}
+	}
+	if cfg.AppID == 0 && cfg.PrivateKey == "" && cfg.Token == "" {
+		cfg.AppID = app.ID
+		cfg.PrivateKey = app.PrivateKey
+		if cfg.AppInstallation == 0 {
+			cfg.AppInstallation = app.InstallationID
+		}
+	}
+	if fileCfg.RecordHistory && !cmd.Flags().Changed("record") {
+		cfg.RecordHistory = true
+	}
+	return nil
 }
 
 func run(cmd *cobra.Command, args []string) error {
+	if printSchema {
+		_, err := os.Stdout.Write(output.OutputSchema)
+		return err
+	}
+
+	filters, err := parseDeliveryFilters(cmd)
+	if err != nil {
+		return err
+	}
+
+	// --org combined with --repo lists the repository's deliveries of the organization's hooks as well
+	if cfg.Org != "" && cfg.Repo != "" {
+		cfg.OrgHooks, cfg.Org = cfg.Org, ""
+	}
+
+	// Validate configuration
+	if err := validateTarget(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+
+	if cfg.ValidatePayload {
+		payloadSchemas, err = loadPayloadSchemas()
+		if err != nil {
+			return err
+		}
+	}
+
+	// Create GitHub client
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	// Filter the deliveries of every hook as they arrive, so large scans only keep the matching ones
+	retainFilters = &filters
+	allDeliveries, err := fetchDeliveries(client)
+	if err != nil {
+		return err
+	}
+
+	return listDeliveries(allDeliveries, filters, func(deliveries []github.Delivery) ([]github.Delivery, error) {
+		return fetchDeliveryDetails(client, deliveries, cfg.Org != "")
+	})
+}
+
+// deliveryFilters are the parsed delivery ID, installation, error class and payload filters of a delivery listing
+type deliveryFilters struct {
+	ids           *filter.IDFilter
+	installations *filter.InstallationFilter
+	classes       *filter.ClassFilter
+	refs          *filter.RefFilter
+	senders       *filter.SenderFilter
+	numbers       *filter.NumberFilter
+}
+
+// retainFilters are applied to the deliveries of every hook as they are fetched, only deliveries passing them are kept
+// Only the delivery listing sets them, other commands work on all fetched deliveries
+var retainFilters *deliveryFilters
+
+// retains reports whether a delivery passes the filters that only need the delivery itself:
+// the date range, delivery ID, installation, error class and --failed
+func (f deliveryFilters) retains(d github.Delivery) bool {
+	if !filter.InRange(d.DeliveredAt, cfg.Since, cfg.Until) || !f.ids.Matches(d.ID) || !f.installations.Matches(d.InstallationID) ||
+		!f.classes.Matches(d.StatusCode, d.Status) {
+		return false
+	}
+	return !cfg.Failed || filter.IsFailed(d.StatusCode)
+}
+
+// payload reports whether a filter needs the request payloads of the delivery details
+func (f deliveryFilters) payload() bool {
+	return f.refs != nil || f.senders != nil || f.numbers != nil
+}
+
+// parseDeliveryFilters parses the date range, delivery ID, installation, error class and payload filter flags
+func parseDeliveryFilters(cmd *cobra.Command) (deliveryFilters, error) {
 	// Parse date range
 	sinceStr, _ := cmd.Flags().GetString("since")
 	untilStr, _ := cmd.Flags().GetString("until")
 
 	since, until, err := config.ParseDateRange(sinceStr, untilStr)
 	if err != nil {
-		return err
+		return deliveryFilters{}, err
 	}
 
 	cfg.Since = since
 	cfg.Until = until
 
-	// Validate configuration
-	if err := cfg.Validate(); err != nil {
-		return fmt.Errorf("validation error: %w", err)
+	// Validate the output format against the registered formats
+	if _, ok := output.FormatByName(cfg.GetOutputFormat()); !ok {
+		return deliveryFilters{}, fmt.Errorf("validation error: --output must be one of: %s", strings.Join(output.FormatNames(), ", "))
+	}
+	if cfg.FormatVersion != 0 && !output.SupportsFormatVersion(cfg.FormatVersion) {
+		return deliveryFilters{}, fmt.Errorf("validation error: --format-version %d is not supported, the latest version is %d", cfg.FormatVersion, output.FormatVersion)
 	}
 
-	// Create GitHub client
-	client, err := github.NewClient()
+	// Parse delivery ID filter
+	idFilter, err := filter.ParseIDFilter(cfg.DeliveryID)
 	if err != nil {
-		return fmt.Errorf("failed to create GitHub client: %w\nHint: Run 'gh auth login' to authenticate", err)
+		return deliveryFilters{}, fmt.Errorf("validation error: --delivery-id: %w", err)
 	}
 
-	var allDeliveries []github.Delivery
+	// Parse installation filter
+	installationFilter, err := filter.ParseInstallationFilter(cfg.Installation)
+	if err != nil {
+		return deliveryFilters{}, fmt.Errorf("validation error: --installation: %w", err)
+	}
 
-	// Process organization or repository
-	if cfg.Org != "" {
-		allDeliveries, err = processOrganization(client, cfg.Org)
-		if err != nil {
-			return err
-		}
-	} else {
-		allDeliveries, err = processRepository(client, cfg.Repo)
-		if err != nil {
-			return err
-		}
+	// Parse error class filter
+	classFilter, err := filter.ParseClassFilter(cfg.ErrorClass)
+	if err != nil {
+		return deliveryFilters{}, fmt.Errorf("validation error: --error-class: %w", err)
 	}
 
-	// Apply date range filter
+	// Parse git ref filter, --branch is a shorthand for the ref of a branch
+	ref, refFlag := cfg.Ref, "--ref"
+	if cfg.Branch != "" {
+		ref, refFlag = "refs/heads/"+cfg.Branch, "--branch"
+	}
+	refFilter, err := filter.ParseRefFilter(ref)
+	if err != nil {
+		return deliveryFilters{}, fmt.Errorf("validation error: %s: %w", refFlag, err)
+	}
+
+	// Parse pull request and issue filter
+	numberFilter, err := filter.ParseNumberFilter(cfg.PullRequest, cfg.Issue)
+	if err != nil {
+		return deliveryFilters{}, fmt.Errorf("validation error: %w", err)
+	}
+
+	return deliveryFilters{
+		ids:           idFilter,
+		installations: installationFilter,
+		classes:       classFilter,
+		refs:          refFilter,
+		senders:       filter.ParseSenderFilter(cfg.Sender, cfg.ExcludeSender),
+		numbers:       numberFilter,
+	}, nil
+}
+
+// Rolling median of --duration-delta: the number of earlier deliveries of a hook it is computed from,
+// and the minimum number of earlier deliveries for a meaningful median
+const (
+	durationDeltaWindow      = 20
+	durationDeltaMinBaseline = 5
+)
+
+// listDeliveries filters, sorts and outputs deliveries according to the listing flags
+// fetchDetails resolves the target URLs for --filter, it is nil if the deliveries carry them already
+func listDeliveries(allDeliveries []github.Delivery, filters deliveryFilters, fetchDetails func([]github.Delivery) ([]github.Delivery, error)) error {
+	// Deliveries fetched for the listing were annotated and filtered per hook already
+	if retainFilters == nil {
+		annotateDeliveries(allDeliveries)
+	}
+
+	// Apply date range, delivery ID, installation, error class and status filters
 	filteredDeliveries := make([]github.Delivery, 0)
 	for _, d := range allDeliveries {
-		if filter.InRange(d.DeliveredAt, cfg.Since, cfg.Until) {
+		if filters.retains(d) {
 			filteredDeliveries = append(filteredDeliveries, d)
 		}
 	}
 
 	// Apply --last-failed filter: only include repos where most recent delivery failed
+	// It excludes --failed, so the status filter above kept all deliveries
 	if cfg.LastFailed {
 		filteredDeliveries = filterByLastFailed(filteredDeliveries)
 	}
 
-	// Apply status filter if --failed is specified
-	if cfg.Failed {
-		statusFilteredDeliveries := make([]github.Delivery, 0)
-		for _, d := range filteredDeliveries {
-			if filter.IsFailed(d.StatusCode) {
-				statusFilteredDeliveries = append(statusFilteredDeliveries, d)
+	// If URL or payload filters are specified, fetch detailed delivery info and filter
+	// Deliveries carry the target URL of their hook, the URL filter only needs details for those without one
+	if cfg.Filter != "" || filters.payload() {
+		detailedDeliveries := filteredDeliveries
+		if fetchDetails != nil {
+			var err error
+			if filters.payload() {
+				detailedDeliveries, err = fetchDetails(filteredDeliveries)
+			} else {
+				detailedDeliveries, err = withTargetURLs(filteredDeliveries, fetchDetails)
+			}
+			if err != nil {
+				return err
 			}
-		}
-		filteredDeliveries = statusFilteredDeliveries
-	}
-
-	// If URL filter is specified, fetch detailed delivery info and filter
-	if cfg.Filter != "" {
-		detailedDeliveries, err := fetchDeliveryDetails(client, filteredDeliveries, cfg.Org != "")
-		if err != nil {
-			return err
 		}
 
-		// Filter by URL pattern
+		// Filter by URL pattern and payload fields
 		finalDeliveries := make([]github.Delivery, 0)
 		for _, d := range detailedDeliveries {
-			if filter.MatchesPattern(d.URL, cfg.Filter) {
+			if filter.MatchesPattern(d.URL, cfg.Filter) && filters.refs.Matches(d.Ref) && filters.senders.Matches(d.Sender) &&
+				filters.numbers.Matches(d.PullRequest, d.Issue) {
 				finalDeliveries = append(finalDeliveries, d)
 			}
 		}
 		filteredDeliveries = finalDeliveries
 	}
 
+	// Reduce to the most recent delivery of each hook
+	if cfg.LatestPerHook {
+		filteredDeliveries = latestPerHook(filteredDeliveries)
+	}
+
+	// Summaries replace the listing, sorting and limits do not apply to them
+	if cfg.SummaryBy != "" {
+		return outputSummary(filteredDeliveries)
+	}
+
 	// Apply sorting based on configuration
 	sortField, ascending := cfg.GetSortConfig()
 	github.ApplySort(filteredDeliveries, sortField, ascending)
@@ -168,128 +532,689 @@ func run(cmd *cobra.Command, args []string) error {
 		filteredDeliveries = applyHeadLimit(filteredDeliveries, cfg.Head, sortField, ascending)
 	}
 
+	// Fetch response excerpts last, so only deliveries that are shown cost an API request
+	if cfg.ResponseExcerpt > 0 && fetchDetails != nil {
+		var err error
+		filteredDeliveries, err = withResponseExcerpts(filteredDeliveries, fetchDetails)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Validate payloads last as well, for the same reason
+	if payloadSchemas != nil && fetchDetails != nil {
+		var err error
+		filteredDeliveries, err = withPayloadValidation(filteredDeliveries, fetchDetails)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Fetch details last as well, for the same reason
+	if cfg.Details && fetchDetails != nil {
+		var err error
+		filteredDeliveries, err = withDetails(filteredDeliveries, fetchDetails)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Extract payload fields last as well, for the same reason
+	if cfg.Extract != "" && fetchDetails != nil {
+		var err error
+		filteredDeliveries, err = withExtractedFields(filteredDeliveries, fetchDetails)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Output results
-	if cfg.JSONOutput {
-		return output.FormatJSON(filteredDeliveries, os.Stdout)
-	} else {
-		output.FormatTable(filteredDeliveries, os.Stdout)
+	opts := output.TableOptions{
+		Width:        tableWidth(),
+		Compact:      cfg.Compact,
+		ASCII:        !unicodeLocale(),
+		RelativeTime: cfg.TimeFormat == "relative",
+		Fields:       cfg.GetExtractPaths(),
+		Details:      cfg.Details,
+		Delta:        cfg.DurationDelta,
+		Flatten:      cfg.Flatten,
+	}
+	if cfg.IncludeWarnings {
+		return output.FormatJSONReport(diag.report(filteredDeliveries), os.Stdout, opts)
+	}
+	format, _ := output.FormatByName(cfg.GetOutputFormat())
+	return format.Write(filteredDeliveries, os.Stdout, opts)
+}
+
+// annotateDeliveries counts the attempts of every event and, with --duration-delta, compares durations
+// with the rolling median of their hook
+// Both need all deliveries of a hook, before filters drop any of them, e.g. successful redeliveries with --failed
+func annotateDeliveries(deliveries []github.Delivery) {
+	attempts := stats.Attempts(deliveries)
+	var deltas map[int]float64
+	if cfg.DurationDelta {
+		deltas = stats.DurationDeltas(deliveries, durationDeltaWindow, durationDeltaMinBaseline)
+	}
+	for i, d := range deliveries {
+		deliveries[i].Attempts = attempts[d.ID]
+		if delta, ok := deltas[d.ID]; ok {
+			deliveries[i].DurationDelta = &delta
+		}
+	}
+}
+
+// retainDeliveries annotates the deliveries of a hook and keeps only those passing retainFilters
+// The kept deliveries are copied, so the hook's full list can be freed while the scan goes on
+func retainDeliveries(deliveries []github.Delivery) []github.Delivery {
+	if retainFilters == nil {
+		return deliveries
+	}
+	annotateDeliveries(deliveries)
+	retained := make([]github.Delivery, 0)
+	for _, d := range deliveries {
+		if retainFilters.retains(d) {
+			retained = append(retained, d)
+		}
+	}
+	return retained
+}
+
+// tableWidth returns the width tables have to fit into, 0 with --wide or if stdout is not a terminal
+func tableWidth() int {
+	if cfg.Wide || !term.IsTerminal(int(os.Stdout.Fd())) {
+		return 0
+	}
+	width, _, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		return 0
+	}
+	return width
+}
+
+// unicodeLocale reports whether the locale uses UTF-8, so terminals can show Unicode glyphs
+// The first of LC_ALL, LC_CTYPE and LANG that is set decides, as in the C library
+func unicodeLocale() bool {
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if value := os.Getenv(name); value != "" {
+			value = strings.ToLower(value)
+			return strings.Contains(value, "utf-8") || strings.Contains(value, "utf8")
+		}
+	}
+	return false
+}
+
+// newClient creates the GitHub client with a hint on authentication failures
+// With GitHub App credentials the client authenticates as the app's installation,
+// an explicit token (--token, GH_TOKEN or GITHUB_TOKEN) bypasses the gh CLI login
+// Its requests belong to the root span of the run when tracing
+func newClient() (*github.Client, error) {
+	if cfg.Offline {
+		return github.NewOfflineClient(github.Options{Host: cfg.Host})
+	}
+
+	transport, err := github.NewTransport(cfg.CABundle)
+	if err != nil {
+		return nil, err
+	}
+	// Requests hitting a secondary rate limit wait within the deadline and --repo-timeout, but outside --request-timeout
+	requests := rateLimits.Transport(github.TimeoutTransport(transport, cfg.RequestTimeout))
+	opts := github.Options{Host: cfg.Host, Transport: traceTransport(deadline.transport(repoScans.transport(requests)))}
+
+	if cfg.AppID != 0 {
+		key, err := github.LoadPrivateKey(cfg.PrivateKey)
+		if err != nil {
+			return nil, err
+		}
+		target := cfg.Org
+		if target == "" {
+			target = cfg.Repo
+		}
+		// The installation of the first listed repository is used for the whole list
+		if target == "" && len(cfg.Repos) > 0 {
+			target = cfg.Repos[0]
+		}
+		client, err := github.NewAppClient(opts, github.AppCredentials{
+			AppID:          cfg.AppID,
+			PrivateKey:     key,
+			InstallationID: cfg.AppInstallation,
+		}, target)
+		if err != nil {
+			return nil, fmt.Errorf("failed to authenticate as GitHub App: %w", err)
+		}
+		return client.WithContext(runContext), nil
+	}
+
+	if token := apiToken(); token != "" {
+		client, err := github.NewTokenClient(opts, token)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create GitHub client: %w", err)
+		}
+		return client.WithContext(runContext), nil
+	}
+
+	client, err := github.NewClient(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GitHub client: %w\nHint: Run 'gh auth login' to authenticate or pass a token with --token or GH_TOKEN", err)
+	}
+	return client.WithContext(runContext), nil
+}
+
+// requireFeature fails with a clear message if the server lacks the feature
+// If the server version cannot be detected, the feature is assumed to be available
+func requireFeature(client *github.Client, feature github.Feature) error {
+	if cfg.Offline {
 		return nil
 	}
+	info, err := client.GetServerInfo()
+	if err != nil {
+		if cfg.Verbose {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		return nil
+	}
+	return info.Require(feature)
 }
 
-func processOrganization(client *github.Client, org string) ([]github.Delivery, error) {
+// requireScopes fails before a scan if the token lacks a scope it needs
+// Without admin:repo_hook every repository answers 404 as if it had no webhooks, one clear error beats hundreds of warnings
+// Tokens without reported scopes, i.e. fine-grained tokens and GitHub App installations, are not checked
+func requireScopes(client *github.Client, required ...github.ScopeRequirement) error {
+	if cfg.Offline {
+		return nil
+	}
+	scopes, reported, err := client.TokenScopes()
+	if err != nil {
+		if cfg.Verbose {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		return nil
+	}
+	if !reported {
+		return nil
+	}
+	var missing []string
+	for _, scope := range required {
+		if !scope.SatisfiedBy(scopes) {
+			missing = append(missing, scope.Name)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return fmt.Errorf("your token lacks %s, which is needed to read webhooks and their deliveries\nHint: %s",
+		strings.Join(missing, " and "), scopeFix(client, missing))
+}
+
+// apiToken returns the explicitly configured API token, if any
+func apiToken() string {
+	if cfg.Token != "" {
+		return cfg.Token
+	}
+	for _, name := range []string{"GH_TOKEN", "GITHUB_TOKEN"} {
+		if token := os.Getenv(name); token != "" {
+			return token
+		}
+	}
+	return ""
+}
+
+// fetchDeliveries retrieves the deliveries of the configured organization or repository
+func fetchDeliveries(client *github.Client) ([]github.Delivery, error) {
+	if err := requireFeature(client, github.FeatureHookDeliveries); err != nil {
+		return nil, err
+	}
+	if cfg.OrgHooks != "" {
+		if err := requireScopes(client, github.ScopeOrgHooks); err != nil {
+			return nil, err
+		}
+	}
+	deliveries, err := scanTargets(client, processRepository)
+	if err != nil || cfg.OrgHooks == "" {
+		return deliveries, err
+	}
+
+	orgDeliveries, err := fetchOrgHookDeliveries(client, cfg.OrgHooks, cfg.Repo)
+	if err != nil {
+		return nil, err
+	}
+	return append(deliveries, orgDeliveries...), nil
+}
+
+// scanTargets runs scan for the configured repository, every repository of the configured organization
+// or every repository of --repos and --repos-file
+// Scans of many repositories fail upfront if the token lacks the scope to read their webhooks
+func scanTargets[T any](client *github.Client, scan func(client *github.Client, repo string) ([]T, error)) ([]T, error) {
+	if cfg.Org != "" || len(cfg.Repos) > 0 {
+		if err := requireScopes(client, github.ScopeRepoHooks); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.Org != "" {
+		return scanOrganization(client, cfg.Org, scan)
+	}
+	if len(cfg.Repos) > 0 {
+		return scanRepositories(client, cfg.Repos, scan)
+	}
+	result := scanRepository(client, cfg.Repo, scan)
+	return result.items, result.err
+}
+
+// scanOrganization runs scan for every repository of the organization
+func scanOrganization[T any](client *github.Client, org string, scan func(client *github.Client, repo string) ([]T, error)) ([]T, error) {
 	if cfg.Verbose {
 		fmt.Fprintf(os.Stderr, "Fetching repositories for organization: %s\n", org)
 	}
 
-	// Get all repositories in the organization
-	repos, err := client.ListOrgRepos(org)
+	// Get all repositories in the organization, or the repositories of --team
+	orgRepos, err := listScanRepos(client, org)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list organization repositories: %w", err)
 	}
 
 	if cfg.Verbose {
-		fmt.Fprintf(os.Stderr, "Found %d repositories\n", len(repos))
+		fmt.Fprintf(os.Stderr, "Found %d repositories\n", len(orgRepos))
+	}
+
+	return scanRepositories(client, selectScanRepos(orgRepos), scan)
+}
+
+// selectScanRepos returns the names of the organization repositories to scan
+// Forks are skipped unless --include-forks is set, they rarely have webhooks of their own
+// With --language only repositories of these primary languages are scanned, with --active-since only recently pushed ones
+func selectScanRepos(orgRepos []github.Repository) []string {
+	languages := cfg.GetLanguages()
+	var activeSince time.Time
+	if d, _ := cfg.GetActiveSince(); d > 0 {
+		activeSince = time.Now().Add(-d)
 	}
 
+	repos := make([]string, 0, len(orgRepos))
+	forks, otherLanguages, dormant := 0, 0, 0
+	for _, r := range orgRepos {
+		if r.IsFork && !cfg.IncludeForks {
+			forks++
+			continue
+		}
+		if languages != nil && !languages[strings.ToLower(r.Language)] {
+			otherLanguages++
+			continue
+		}
+		// Repositories without a known push time are scanned, e.g. from a repository list cached by an older release
+		if !activeSince.IsZero() && r.PushedAt != nil && r.PushedAt.Before(activeSince) {
+			dormant++
+			continue
+		}
+		repos = append(repos, r.FullName)
+	}
+
+	if cfg.Verbose {
+		if forks > 0 {
+			fmt.Fprintf(os.Stderr, "Skipping %d forks, use --include-forks to scan them\n", forks)
+		}
+		if otherLanguages > 0 {
+			fmt.Fprintf(os.Stderr, "Skipping %d repositories not written in %s\n", otherLanguages, cfg.Language)
+		}
+		if dormant > 0 {
+			fmt.Fprintf(os.Stderr, "Skipping %d repositories without a push in the last %s\n", dormant, cfg.ActiveSince)
+		}
+	}
+	return repos
+}
+
+// scanRepositories runs scan for every repository using a bounded worker pool
+// Each repository is retried and timed out on its own, see scanRepository
+// Failing repositories are reported as warnings and in a summary on stderr, or abort the scan with --strict and --fail-fast
+func scanRepositories[T any](client *github.Client, repos []string, scan func(client *github.Client, repo string) ([]T, error)) ([]T, error) {
 	if len(repos) == 0 {
-		return []github.Delivery{}, nil
+		return []T{}, nil
 	}
 
-	// Use concurrent workers to speed up repository processing
+	// Process repositories concurrently with a bounded number of workers
+	// Each worker writes to its own slot, so results keep the repository order
 	const maxConcurrent = 10
-	numWorkers := maxConcurrent
-	if len(repos) < numWorkers {
-		numWorkers = len(repos)
-	}
 
-	// Channels for work distribution and results
 	type repoResult struct {
-		repo       string
-		deliveries []github.Delivery
-		err        error
+		repoScan[T]
+		skipped bool // Not scanned because --deadline passed
 	}
+	results := make([]repoResult, len(repos))
 
-	jobs := make(chan string, len(repos))
-	results := make(chan repoResult, len(repos))
+	if budget, ok := rateLimits.Get(rateLimitResource); ok && cfg.Verbose {
+		fmt.Fprintf(os.Stderr, "API budget: %s\n", formatRateLimit(budget))
+	}
+	projection := newBudgetProjection()
+	projection.check(0, len(repos))
+	var done atomic.Int64
 
-	// Start workers
-	for w := 0; w < numWorkers; w++ {
-		go func() {
-			for repo := range jobs {
-				if cfg.Verbose {
-					fmt.Fprintf(os.Stderr, "Processing repository: %s\n", repo)
-				}
-				repoDeliveries, err := processRepository(client, repo)
-				results <- repoResult{
-					repo:       repo,
-					deliveries: repoDeliveries,
-					err:        err,
-				}
+	g, ctx := errgroup.WithContext(context.Background())
+	g.SetLimit(maxConcurrent)
+
+	for i, repo := range repos {
+		// Stop scheduling once --fail-fast has cancelled the scan
+		if ctx.Err() != nil {
+			break
+		}
+		g.Go(func() error {
+			if ctx.Err() != nil {
+				return nil
+			}
+			if deadline.exceeded() {
+				results[i] = repoResult{skipped: true}
+				return nil
+			}
+			if cfg.Verbose {
+				fmt.Fprintf(os.Stderr, "Processing repository: %s\n", repo)
+			}
+			apiWorkers.acquire()
+			result := scanRepository(client, repo, scan)
+			apiWorkers.release()
+			projection.check(int(done.Add(1)), len(repos))
+			if deadline.interrupted(result.err) {
+				results[i] = repoResult{skipped: true}
+				return nil
 			}
-		}()
+			if result.err != nil && cfg.FailFast {
+				return fmt.Errorf("failed to process repository %s: %w", repo, result.err)
+			}
+			results[i] = repoResult{repoScan: result}
+			return nil
+		})
 	}
 
-	// Send jobs
-	for _, repo := range repos {
-		jobs <- repo
+	if err := g.Wait(); err != nil {
+		return nil, fmt.Errorf("aborting scan (--fail-fast): %w", err)
 	}
-	close(jobs)
 
 	// Collect results
-	var allDeliveries []github.Delivery
-	for i := 0; i < len(repos); i++ {
-		result := <-results
+	var allItems []T
+	var failures []error
+	var summary scanSummary
+	skipped := 0
+	for i, result := range results {
+		if result.skipped {
+			skipped++
+			continue
+		}
+		summary.add(repos[i], result.attempts, result.err, result.timedOut)
 		if result.err != nil {
-			if cfg.Verbose {
-				fmt.Fprintf(os.Stderr, "Warning: failed to process repository %s: %v\n", result.repo, result.err)
+			repoErr := fmt.Errorf("failed to process repository %s: %w", repos[i], result.err)
+			if reason, denied := permissionDenied(result.err); denied {
+				recordDenied(repos[i], reason, repoErr)
+			} else {
+				kind := output.IssueRepositoryFailed
+				if result.timedOut {
+					kind = output.IssueRepositoryTimeout
+				}
+				diag.fail(output.Issue{
+					Kind:       kind,
+					Repository: repos[i],
+					Message:    repoErr.Error(),
+				})
 			}
+			failures = append(failures, repoErr)
 			continue
 		}
-		allDeliveries = append(allDeliveries, result.deliveries...)
+		allItems = append(allItems, result.items...)
 	}
 
-	return allDeliveries, nil
+	summary.skipped = skipped
+	summary.print()
+	if skipped > 0 {
+		deadline.warnPartial(skipped, len(repos), "repositories")
+	}
+
+	if cfg.Strict && len(failures) > 0 {
+		return nil, fmt.Errorf("%d of %d repositories could not be processed (--strict):\n%w", len(failures), len(repos), errors.Join(failures...))
+	}
+
+	return allItems, nil
+}
+
+// defaultCacheTTL is how long a cached organization repository list is reused unless --cache-ttl is set
+const defaultCacheTTL = time.Hour
+
+// listScanRepos returns the repositories of an organization to scan, only those of --team if it is set
+func listScanRepos(client *github.Client, org string) ([]github.Repository, error) {
+	if _, team := cfg.GetTeam(); team != "" {
+		return listTeamRepos(client, org, team)
+	}
+	return listOrgRepos(client, org)
+}
+
+// listOrgRepos returns the repositories of an organization
+// The list is cached on disk for --cache-ttl, --refresh-repos bypasses the cached list and --no-cache the whole cache
+func listOrgRepos(client *github.Client, org string) ([]github.Repository, error) {
+	return listCachedRepos(repositoriesKey(org), "repository list of "+org, func() ([]github.Repository, error) {
+		return client.ListOrgRepos(org)
+	})
+}
+
+// listTeamRepos returns the repositories a team has access to, cached like the repository list of an organization
+func listTeamRepos(client *github.Client, org, team string) ([]github.Repository, error) {
+	return listCachedRepos(teamRepositoriesKey(org, team), fmt.Sprintf("repository list of team %s/%s", org, team), func() ([]github.Repository, error) {
+		return client.ListTeamRepos(org, team)
+	})
 }
 
+// listCachedRepos returns a repository list from the cache if it is younger than --cache-ttl, otherwise from fetch
+// what describes the list in messages, e.g. "repository list of myorg"
+func listCachedRepos(key, what string, fetch func() ([]github.Repository, error)) ([]github.Repository, error) {
+	if cfg.Offline {
+		var repos []github.Repository
+		if err := offline.load(key, what, "", &repos); err != nil {
+			return nil, err
+		}
+		return repos, nil
+	}
+	if cfg.NoCache {
+		return fetch()
+	}
+
+	store, err := cache.New()
+	if err != nil {
+		if cfg.Verbose {
+			fmt.Fprintf(os.Stderr, "Warning: cache unavailable: %v\n", err)
+		}
+		return fetch()
+	}
+
+	if !cfg.RefreshRepos && !cfg.Refresh {
+		var repos []github.Repository
+		found, err := store.Get(key, cfg.CacheTTL, &repos)
+		if err != nil && cfg.Verbose {
+			fmt.Fprintf(os.Stderr, "Warning: failed to read cached %s: %v\n", what, err)
+		}
+		if found {
+			if cfg.Verbose {
+				fmt.Fprintf(os.Stderr, "Using cached %s\n", what)
+			}
+			return repos, nil
+		}
+	}
+
+	repos, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := store.Set(key, repos); err != nil && cfg.Verbose {
+		fmt.Fprintf(os.Stderr, "Warning: failed to cache %s: %v\n", what, err)
+	}
+
+	return repos, nil
+}
+
+// deliveriesPerHook is the number of deliveries fetched for each webhook unless --limit is set, a single page
+const deliveriesPerHook = 100
+
 func processRepository(client *github.Client, repo string) ([]github.Delivery, error) {
-	// Get webhooks for the repository
-	hooks, err := client.ListRepoWebhooks(repo)
+	hooks, err := fetchRepoHookDeliveries(client, repo)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+		return nil, err
 	}
 
-	if len(hooks) == 0 {
-		return []github.Delivery{}, nil
+	allDeliveries := make([]github.Delivery, 0)
+	for _, h := range hooks {
+		allDeliveries = append(allDeliveries, h.Deliveries...)
 	}
 
-	var allDeliveries []github.Delivery
+	return allDeliveries, nil
+}
+
+// hookDeliveries is a webhook together with its recent deliveries
+type hookDeliveries struct {
+	Repository string
+	Hook       github.Hook
+	Deliveries []github.Delivery
+}
+
+// fetchRepoHookDeliveries lists the repository's hooks matching the URL filter together with their deliveries
+func fetchRepoHookDeliveries(client *github.Client, repo string) ([]hookDeliveries, error) {
+	// Get webhooks for the repository
+	hooks, err := listScannedRepoWebhooks(client, repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
 
 	// For each webhook, get deliveries
+	result := make([]hookDeliveries, 0, len(hooks))
 	for _, hook := range hooks {
 		// If we have a URL filter, check if this hook matches before fetching deliveries
-		if cfg.Filter != "" && !hook.MatchesFilter(cfg.Filter) {
+		// Hooks without a configured URL are fetched, their deliveries are matched by the URL of their details
+		if cfg.Filter != "" && hook.GetTargetURL() != "" && !hook.MatchesFilter(cfg.Filter) {
 			continue
 		}
 
-		deliveries, err := client.ListRepoHookDeliveries(repo, hook.ID, 100)
+		hookClient, span := startSpan(client, fmt.Sprintf("hook %d", hook.ID),
+			attribute.String("github.repository", repo),
+			attribute.Int("github.hook_id", hook.ID))
+		deliveries, err := fetchHookDeliveries(hookClient, repo, hook)
+		span.SetAttributes(attribute.Int("github.deliveries", len(deliveries)))
+		endSpan(span, err)
 		if err != nil {
-			if cfg.Verbose {
-				fmt.Fprintf(os.Stderr, "Warning: failed to list deliveries for hook %d: %v\n", hook.ID, err)
+			// In strict mode a failing hook fails the whole repository, as does the deadline or --repo-timeout passing
+			if cfg.Strict || cfg.FailFast || deadline.interrupted(err) || errors.Is(err, errRepoTimeout) {
+				return nil, err
+			}
+			// Without its deliveries the hook would look healthy, it is left out and reported as failed instead
+			diag.fail(output.Issue{
+				Kind:       output.IssueHookFailed,
+				Repository: repo,
+				HookID:     hook.ID,
+				Message:    err.Error(),
+			})
+			continue
+		}
+
+		result = append(result, hookDeliveries{
+			Repository: repo,
+			Hook:       hook,
+			Deliveries: deliveries,
+		})
+	}
+
+	return result, nil
+}
+
+// fetchHookDeliveries retrieves the deliveries of a repository hook
+// Each delivery is tagged with the hook's target URL
+func fetchHookDeliveries(client *github.Client, repo string, hook github.Hook) ([]github.Delivery, error) {
+	deliveries, err := listRepoHookDeliveries(client, repo, hook.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deliveries for hook %d: %w", hook.ID, err)
+	}
+
+	// Deliveries are fetched up to --limit, older deliveries may exist
+	if len(deliveries) >= cfg.Limit {
+		diag.warn(output.Issue{
+			Kind:       output.IssueTruncated,
+			Repository: repo,
+			HookID:     hook.ID,
+			Message:    fmt.Sprintf("hook %d in %s returned %d deliveries, older deliveries are not included (see --limit)", hook.ID, repo, len(deliveries)),
+		})
+	}
+
+	// Add the webhook target URL to each delivery
+	targetURL := hook.GetTargetURL()
+	for i := range deliveries {
+		deliveries[i].URL = targetURL
+	}
+
+	if !cfg.Offline {
+		recordHistory(deliveries)
+	}
+
+	return retainDeliveries(deliveries), nil
+}
+
+// fetchOrgHookDeliveries retrieves the deliveries of the organization's hooks that belong to one of its repositories
+// Organization hooks receive the events of every repository, deliveries are matched by their repository ID
+// Each delivery is tagged with the repository, the organization and the hook's target URL
+func fetchOrgHookDeliveries(client *github.Client, org, repo string) ([]github.Delivery, error) {
+	repoID, err := getRepositoryID(client, repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up repository: %w", err)
+	}
+	hooks, err := listOrgWebhooks(client, org)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organization webhooks: %w", err)
+	}
+
+	var result []github.Delivery
+	for _, hook := range hooks {
+		if cfg.Filter != "" && hook.GetTargetURL() != "" && !hook.MatchesFilter(cfg.Filter) {
+			continue
+		}
+
+		hookClient, span := startSpan(client, fmt.Sprintf("organization hook %d", hook.ID),
+			attribute.String("github.organization", org),
+			attribute.Int("github.hook_id", hook.ID))
+		deliveries, err := listOrgHookDeliveries(hookClient, org, hook.ID)
+		span.SetAttributes(attribute.Int("github.deliveries", len(deliveries)))
+		endSpan(span, err)
+		if err != nil {
+			err = fmt.Errorf("failed to list deliveries for organization hook %d: %w", hook.ID, err)
+			if cfg.Strict || cfg.FailFast || deadline.interrupted(err) {
+				return nil, err
 			}
+			diag.fail(output.Issue{
+				Kind:       output.IssueHookFailed,
+				Repository: org,
+				HookID:     hook.ID,
+				Message:    err.Error(),
+			})
 			continue
 		}
 
-		// Add the webhook target URL to each delivery
+		// Deliveries are fetched up to --limit, older deliveries of the repository may exist
+		if len(deliveries) >= cfg.Limit {
+			diag.warn(output.Issue{
+				Kind:       output.IssueTruncated,
+				Repository: org,
+				HookID:     hook.ID,
+				Message:    fmt.Sprintf("organization hook %d of %s returned %d deliveries, older deliveries are not included (see --limit)", hook.ID, org, len(deliveries)),
+			})
+		}
+
 		targetURL := hook.GetTargetURL()
-		for i := range deliveries {
-			deliveries[i].URL = targetURL
+		var matched []github.Delivery
+		for _, d := range deliveries {
+			if d.RepositoryID == nil || *d.RepositoryID != repoID {
+				continue
+			}
+			d.Repository = repo
+			d.Org = org
+			d.URL = targetURL
+			matched = append(matched, d)
 		}
 
-		allDeliveries = append(allDeliveries, deliveries...)
+		if !cfg.Offline {
+			recordHistory(matched)
+		}
+		result = append(result, retainDeliveries(matched)...)
 	}
 
-	return allDeliveries, nil
+	return result, nil
 }
 
 func fetchDeliveryDetails(client *github.Client, deliveries []github.Delivery, isOrg bool) ([]github.Delivery, error) {
@@ -297,56 +1222,110 @@ func fetchDeliveryDetails(client *github.Client, deliveries []github.Delivery, i
 		return deliveries, nil
 	}
 
-	// Use concurrent workers to speed up fetching
+	// Fetch details concurrently with a bounded number of workers
+	// Each worker writes to its own slot, so results keep the input order
 	const maxConcurrent = 5
-	numWorkers := maxConcurrent
-	if len(deliveries) < numWorkers {
-		numWorkers = len(deliveries)
-	}
-
-	// Channels for work distribution and results
-	jobs := make(chan github.Delivery, len(deliveries))
-	results := make(chan github.Delivery, len(deliveries))
-	errors := make(chan error, len(deliveries))
-
-	// Start workers
-	for w := 0; w < numWorkers; w++ {
-		go func() {
-			for d := range jobs {
-				// Always use repository webhook endpoint since all webhooks are repository webhooks
-				// Even when processing an org, we iterate through repos and fetch their webhooks
-				detail, err := client.GetRepoHookDeliveryDetail(d.Repository, d.HookID, d.ID)
-
-				if err != nil {
-					errors <- fmt.Errorf("failed to get delivery detail for %d: %v", d.ID, err)
-					continue
+
+	type detailResult struct {
+		delivery github.Delivery
+		issue    *output.Issue
+		skipped  bool // Not fetched because --deadline passed
+	}
+	results := make([]detailResult, len(deliveries))
+
+	g, ctx := errgroup.WithContext(context.Background())
+	g.SetLimit(maxConcurrent)
+
+	for i, d := range deliveries {
+		// Stop scheduling once --fail-fast has cancelled the run
+		if ctx.Err() != nil {
+			break
+		}
+		g.Go(func() error {
+			if ctx.Err() != nil {
+				return nil
+			}
+			if deadline.exceeded() {
+				results[i] = detailResult{skipped: true}
+				return nil
+			}
+			// Deliveries of organization hooks are looked up through the organization, all others through their repository
+			apiWorkers.acquire()
+			detail, err := getDeliveryDetail(client, d)
+			apiWorkers.release()
+			if errors.Is(err, errDetailNotCached) {
+				// Offline, deliveries without a cached detail keep the target URL of their hook
+				results[i] = detailResult{delivery: d}
+				return nil
+			}
+			if deadline.interrupted(err) {
+				results[i] = detailResult{skipped: true}
+				return nil
+			}
+			if err != nil {
+				issue := output.Issue{
+					Kind:       output.IssueDetailFailed,
+					Repository: d.Repository,
+					HookID:     d.HookID,
+					DeliveryID: d.ID,
+					Message:    fmt.Sprintf("failed to get delivery detail for %d: %v", d.ID, err),
+				}
+				if cfg.FailFast {
+					return errors.New(issue.Message)
 				}
+				results[i] = detailResult{issue: &issue}
+				return nil
+			}
 
-				// Copy basic delivery info and add URL
-				detailed := d
-				detailed.URL = detail.URL
-				results <- detailed
+			// Copy basic delivery info and add URL
+			detailed := d
+			detailed.URL = detail.URL
+			detailed.Ref = github.PayloadRef(detail.Request.Payload)
+			detailed.Sender = github.PayloadSender(detail.Request.Payload)
+			detailed.PullRequest, detailed.Issue = github.PayloadNumbers(detail.Request.Payload)
+			detailed.PayloadSize = payloadSize(detail.Request.Payload)
+			detailed.ResponseSummary = responseSummary(detail.Response.Payload)
+			if cfg.ResponseExcerpt > 0 && filter.IsFailed(d.StatusCode) {
+				detailed.ResponseExcerpt = responseExcerpt(outputText(detail.Response.Payload), cfg.ResponseExcerpt)
+			}
+			if payloadSchemas != nil {
+				validatePayload(&detailed, detail.Request.Payload)
+			}
+			if cfg.Extract != "" {
+				detailed.Fields = extractFields(detail.Request.Payload)
 			}
-		}()
+			results[i] = detailResult{delivery: detailed}
+			return nil
+		})
 	}
 
-	// Send jobs
-	for _, d := range deliveries {
-		jobs <- d
+	if err := g.Wait(); err != nil {
+		return nil, fmt.Errorf("aborting (--fail-fast): %w", err)
 	}
-	close(jobs)
 
 	// Collect results
 	detailedDeliveries := make([]github.Delivery, 0, len(deliveries))
-	for i := 0; i < len(deliveries); i++ {
-		select {
-		case detailed := <-results:
-			detailedDeliveries = append(detailedDeliveries, detailed)
-		case err := <-errors:
-			if cfg.Verbose {
-				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
-			}
+	failures := 0
+	skipped := 0
+	for _, result := range results {
+		if result.skipped {
+			skipped++
+			continue
+		}
+		if result.issue != nil {
+			diag.fail(*result.issue)
+			failures++
+			continue
 		}
+		detailedDeliveries = append(detailedDeliveries, result.delivery)
+	}
+
+	if skipped > 0 {
+		deadline.warnPartial(skipped, len(deliveries), "delivery details")
+	}
+
+	if cfg.Strict && failures > 0 {
+		return nil, fmt.Errorf("%d of %d delivery details could not be fetched (--strict)", failures, len(deliveries))
 	}
 
 	return detailedDeliveries, nil
@@ -381,6 +1360,29 @@ func applyHeadLimit(deliveries []github.Delivery, limit int, sortField string, a
 	return result
 }
 
+// latestPerHook returns the most recent delivery of each hook
+func latestPerHook(deliveries []github.Delivery) []github.Delivery {
+	type hookKey struct {
+		repository string
+		hookID     int
+	}
+
+	latest := make(map[hookKey]int)
+	result := make([]github.Delivery, 0)
+	for _, d := range deliveries {
+		key := hookKey{repository: d.Repository, hookID: d.HookID}
+		if i, ok := latest[key]; ok {
+			if d.DeliveredAt.After(result[i].DeliveredAt) {
+				result[i] = d
+			}
+			continue
+		}
+		latest[key] = len(result)
+		result = append(result, d)
+	}
+	return result
+}
+
 // filterByLastFailed returns only deliveries from repositories where
 // the most recent delivery was a failure.
 func filterByLastFailed(deliveries []github.Delivery) []github.Delivery {
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/ohader/gh-hookmon/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// printSchema is the --print-schema flag of the root command
+var printSchema bool
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print the JSON Schema of the JSON output",
+	Long: `Print the JSON Schema (draft 2020-12) describing the output of --output=json:
+the list of deliveries, the envelope of --include-warnings and the rows of
+--summary-by. Every line of --output=ndjson is a delivery of the schema.
+
+Fields are only added in a compatible way, integrators can validate the output
+and generate code against it. gh hookmon --print-schema prints the same schema.
+
+Examples:
+  # Save the schema
+  gh hookmon schema > hookmon-schema.json
+
+  # Validate the output of a run
+  gh hookmon --org=myorg --output=json > deliveries.json
+  check-jsonschema --schemafile hookmon-schema.json deliveries.json`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, err := os.Stdout.Write(output.OutputSchema)
+		return err
+	},
+}
+
+func init() {
+	rootCmd.Flags().BoolVar(&printSchema, "print-schema", false, "Print the JSON Schema of the JSON output and exit, see the schema subcommand")
+	rootCmd.AddCommand(schemaCmd)
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ohader/gh-hookmon/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// showOptions holds the flags of the show subcommand
+type showOptions struct {
+	Hook int
+	JSON bool
+}
+
+var showOpts showOptions
+
+var showCmd = &cobra.Command{
+	Use:   "show DELIVERY-ID|GUID",
+	Short: "Show a webhook delivery with its request and response",
+	Long: `Show a single webhook delivery of a repository with its request and response
+headers and the response body.
+
+The values of the X-Hub-Signature, X-Hub-Signature-256 and Authorization headers
+and the payload values matched by the redact section of the configuration file
+are redacted so the output can be shared safely. Use --no-redact to show them
+in trusted contexts, e.g. to verify a signature.
+
+Examples:
+  # Show a delivery
+  gh hookmon show --repo=owner/repo 12345678
+
+  # Show the most recent attempt of a delivery GUID in JSON format
+  gh hookmon show --repo=owner/repo --json 0b989ba4-242f-11e5-81e1-c7b6966d2516`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE:         runShow,
+}
+
+func init() {
+	showCmd.Flags().IntVar(&showOpts.Hook, "hook", 0, "Only look up deliveries of this hook ID")
+	showCmd.Flags().BoolVar(&showOpts.JSON, "json", false, "Output in JSON format")
+	rootCmd.AddCommand(showCmd)
+}
+
+func runShow(cmd *cobra.Command, args []string) error {
+	if err := requireOnline("show"); err != nil {
+		return err
+	}
+	if cfg.Repo == "" {
+		return fmt.Errorf("validation error: show requires --repo")
+	}
+	if cfg.Org != "" {
+		return fmt.Errorf("validation error: show does not support --org")
+	}
+	if err := validateTarget(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	targets, _, err := resolveDeliveries(client, cfg.Repo, showOpts.Hook, args)
+	if err != nil {
+		return err
+	}
+	if len(targets) == 0 {
+		return fmt.Errorf("delivery %s not found in %s", args[0], cfg.Repo)
+	}
+	d := targets[0]
+
+	detail, err := getDeliveryDetail(client, d)
+	if err != nil {
+		return err
+	}
+	detail = outputDetail(detail)
+
+	if showOpts.JSON {
+		return output.FormatDetailJSON(detail, os.Stdout)
+	}
+	output.FormatDetailText(detail, os.Stdout)
+	return nil
+}
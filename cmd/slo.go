@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ohader/gh-hookmon/internal/config"
+	"github.com/ohader/gh-hookmon/internal/output"
+	"github.com/ohader/gh-hookmon/internal/stats"
+	"github.com/spf13/cobra"
+)
+
+// sloOptions holds the flags of the slo subcommand
+type sloOptions struct {
+	Name string
+	JSON bool
+}
+
+var sloOpts sloOptions
+
+var sloCmd = &cobra.Command{
+	Use:   "slo",
+	Short: "Evaluate service level objectives defined in the config file",
+	Long: `Evaluate the availability and latency objectives declared under "slos" in
+the config file, including the remaining error budget and the burn rate.
+
+Exits with exit code 2 if any objective is breached.
+
+Example config file (~/.config/gh-hookmon/config.yml):
+  slos:
+    - name: packagist
+      url: packagist.org
+      window: 7d
+      availability: 99.5%
+      latency: 2s
+      latency_target: 95%
+
+Examples:
+  # Evaluate all SLOs against the hooks of an organization
+  gh hookmon slo --org=myorg
+
+  # Evaluate a single SLO as JSON
+  gh hookmon slo --org=myorg --name=packagist --json`,
+	SilenceUsage: true,
+	RunE:         runSLO,
+}
+
+func init() {
+	sloCmd.Flags().StringVar(&sloOpts.Name, "name", "", "Only evaluate the SLO with this name")
+	sloCmd.Flags().BoolVar(&sloOpts.JSON, "json", false, "Output in JSON format")
+	rootCmd.AddCommand(sloCmd)
+}
+
+func runSLO(cmd *cobra.Command, args []string) error {
+	if err := validateTarget(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+
+	slos := make([]config.SLO, 0, len(fileCfg.SLOs))
+	for _, slo := range fileCfg.SLOs {
+		if sloOpts.Name == "" || slo.Name == sloOpts.Name {
+			slos = append(slos, slo)
+		}
+	}
+	if sloOpts.Name != "" && len(slos) == 0 {
+		return fmt.Errorf("no SLO named %q in the config file", sloOpts.Name)
+	}
+	if len(slos) == 0 {
+		output.FormatSLOTable(nil, os.Stdout)
+		return nil
+	}
+
+	// The deliveries must reach back to the start of the longest window
+	now := time.Now()
+	since := now
+	for _, slo := range slos {
+		window, err := slo.GetWindow()
+		if err != nil {
+			return fmt.Errorf("slo %q: %w", slo.Name, err)
+		}
+		if start := now.Add(-window); start.Before(since) {
+			since = start
+		}
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	coverWindow(since)
+	deliveries, err := fetchDeliveries(client)
+	if err != nil {
+		return err
+	}
+	incompleteWindow(deliveries, since, "its SLOs are evaluated on part of the window")
+
+	results := make([]stats.SLOResult, 0, len(slos))
+	breached := 0
+	for _, slo := range slos {
+		result, err := stats.EvaluateSLO(slo, deliveries, now)
+		if err != nil {
+			return fmt.Errorf("slo %q: %w", slo.Name, err)
+		}
+		if !result.Met {
+			breached++
+		}
+		results = append(results, result)
+	}
+
+	if sloOpts.JSON {
+		if err := output.FormatSLOJSON(results, os.Stdout); err != nil {
+			return err
+		}
+	} else {
+		output.FormatSLOTable(results, os.Stdout)
+	}
+
+	if breached > 0 {
+		return &ExitError{
+			Code: exitCodeViolation,
+			Err:  fmt.Errorf("%d of %d SLOs breached", breached, len(results)),
+		}
+	}
+	return nil
+}
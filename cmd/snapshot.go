@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ohader/gh-hookmon/internal/archive"
+	"github.com/ohader/gh-hookmon/internal/cache"
+	"github.com/ohader/gh-hookmon/internal/github"
+	"github.com/ohader/gh-hookmon/internal/snapshot"
+	"github.com/spf13/cobra"
+)
+
+// snapshotOptions holds the flags of the snapshot subcommands
+type snapshotOptions struct {
+	Out      string
+	Compress bool
+	Upload   string
+}
+
+var snapshotOpts snapshotOptions
+
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Save and load complete datasets of webhooks and deliveries",
+	Long: `Save the webhooks and recent deliveries of an organization or repository to a file,
+and load such a file to analyze it with --offline without any API request.
+
+Snapshots ending with .gz or saved with --compress are gzip-compressed. Loading a snapshot replaces the cached
+data of its repositories, and the cached data keeps the age of the snapshot.
+
+Examples:
+  # Archive the current state of an organization
+  gh hookmon snapshot save --org=myorg --out=run.json.gz
+
+  # The same, the .gz extension is added by --compress
+  gh hookmon snapshot save --org=myorg --out=run.json --compress
+
+  # Archive it in an S3 bucket as well
+  gh hookmon snapshot save --org=myorg --out=run.json.gz --upload=s3://archive/hookmon/
+
+  # Load a snapshot shared by a colleague and analyze it
+  gh hookmon snapshot load run.json.gz
+  gh hookmon --org=myorg --offline --failed --sort=code`,
+}
+
+var snapshotSaveCmd = &cobra.Command{
+	Use:          "save",
+	Short:        "Fetch webhooks and deliveries and save them to a file",
+	SilenceUsage: true,
+	RunE:         runSnapshotSave,
+}
+
+var snapshotLoadCmd = &cobra.Command{
+	Use:          "load FILE",
+	Short:        "Load a snapshot for analysis with --offline",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE:         runSnapshotLoad,
+}
+
+func init() {
+	snapshotSaveCmd.Flags().StringVar(&snapshotOpts.Out, "out", "", "Snapshot file to write, gzip-compressed if it ends with .gz (required)")
+	snapshotSaveCmd.Flags().BoolVar(&snapshotOpts.Compress, "compress", false, "Gzip-compress the snapshot, adding .gz to the file name if missing")
+	snapshotSaveCmd.Flags().StringVar(&snapshotOpts.Upload, "upload", "", "Upload the snapshot to S3-compatible object storage, e.g. s3://bucket/path/")
+
+	snapshotCmd.AddCommand(snapshotSaveCmd, snapshotLoadCmd)
+	rootCmd.AddCommand(snapshotCmd)
+}
+
+func runSnapshotSave(cmd *cobra.Command, args []string) error {
+	if snapshotOpts.Out == "" {
+		return fmt.Errorf("validation error: --out is required")
+	}
+	if err := validateTarget(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+	upload, err := newUploader(snapshotOpts.Upload)
+	if err != nil {
+		return err
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+	if err := requireFeature(client, github.FeatureHookDeliveries); err != nil {
+		return err
+	}
+
+	snap, err := collectSnapshot(client)
+	if err != nil {
+		return err
+	}
+
+	out := archive.Path(snapshotOpts.Out, snapshotOpts.Compress)
+	if err := snapshot.Save(snap, out); err != nil {
+		return err
+	}
+
+	repoCount, hookCount, deliveryCount := snap.Counts()
+	fmt.Printf("Saved snapshot of %s to %s: %d repositories, %d hooks, %d deliveries\n",
+		snap.Target(), out, repoCount, hookCount, deliveryCount)
+	return upload.upload(out)
+}
+
+// collectSnapshot fetches the webhooks and recent deliveries of --org or --repo
+// Repositories that cannot be fetched are missing from the result
+func collectSnapshot(client *github.Client) (*snapshot.Snapshot, error) {
+	snap := &snapshot.Snapshot{
+		Version:   snapshot.Version,
+		CreatedAt: time.Now().UTC(),
+		Org:       cfg.Org,
+		Repo:      cfg.Repo,
+		Team:      cfg.Team,
+	}
+	if cfg.Org != "" {
+		repos, err := listScanRepos(client, cfg.Org)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list organization repositories: %w", err)
+		}
+		snap.OrgRepositories = repos
+	}
+
+	repos, err := scanTargets(client, func(client *github.Client, repo string) ([]snapshot.Repository, error) {
+		hooks, err := fetchRepoHookDeliveries(client, repo)
+		if err != nil {
+			return nil, err
+		}
+		r := snapshot.Repository{Name: repo, Hooks: make([]snapshot.Hook, 0, len(hooks))}
+		for _, h := range hooks {
+			r.Hooks = append(r.Hooks, snapshot.Hook{Hook: h.Hook, Deliveries: h.Deliveries})
+		}
+		return []snapshot.Repository{r}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	snap.Repositories = append([]snapshot.Repository{}, repos...)
+	return snap, nil
+}
+
+func runSnapshotLoad(cmd *cobra.Command, args []string) error {
+	if cfg.NoCache {
+		return fmt.Errorf("validation error: snapshot load imports into the cache and cannot be combined with --no-cache")
+	}
+
+	snap, err := snapshot.Load(args[0])
+	if err != nil {
+		return err
+	}
+
+	store, err := cache.New()
+	if err != nil {
+		return err
+	}
+
+	// Entries keep the age of the snapshot, so --offline reports stale data correctly
+	if snap.Org != "" {
+		key := repositoriesKey(snap.Org)
+		if org, team, ok := strings.Cut(snap.Team, "/"); ok {
+			key = teamRepositoriesKey(org, team)
+		}
+		if err := store.SetAt(key, snap.OrgRepositories, snap.CreatedAt); err != nil {
+			return err
+		}
+	}
+	for _, r := range snap.Repositories {
+		hooks := make([]github.Hook, len(r.Hooks))
+		for i, h := range r.Hooks {
+			hooks[i] = h.Hook
+			// Deliveries that could not be fetched stay missing instead of appearing empty
+			if h.Deliveries == nil {
+				continue
+			}
+			if err := store.SetAt(deliveriesKey(r.Name, h.Hook.ID), h.Deliveries, snap.CreatedAt); err != nil {
+				return err
+			}
+		}
+		if err := store.SetAt(hooksKey(r.Name), hooks, snap.CreatedAt); err != nil {
+			return err
+		}
+	}
+
+	repoCount, hookCount, deliveryCount := snap.Counts()
+	fmt.Printf("Loaded snapshot of %s taken %s: %d repositories, %d hooks, %d deliveries\n",
+		snap.Target(), snap.CreatedAt.Local().Format(time.DateTime), repoCount, hookCount, deliveryCount)
+
+	target := "--org=" + snap.Org
+	if snap.Org == "" {
+		target = "--repo=" + snap.Repo
+	}
+	fmt.Printf("Analyze it with --offline, e.g.: gh hookmon %s --offline\n", target)
+	return nil
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ohader/gh-hookmon/internal/config"
+	"github.com/ohader/gh-hookmon/internal/github"
+	"github.com/ohader/gh-hookmon/internal/output"
+	"github.com/ohader/gh-hookmon/internal/stats"
+	"github.com/spf13/cobra"
+)
+
+// statsOptions holds the flags of the stats subcommand
+type statsOptions struct {
+	By     string
+	Window string
+	JSON   bool
+}
+
+var statsOpts statsOptions
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Break down delivery counts, failure rates and latency by event, target domain or time interval",
+	Long: `Group the fetched deliveries and report the number of deliveries, the failure
+rate and the latency of every group, ordered by failure rate.
+
+--by=event groups by event and action, since receivers often break for a
+single event type (e.g. check_run) while everything else stays green.
+--by=domain groups by the hostname of the webhook target URL, showing which
+downstream service (Slack, Jenkins, Jira, internal services) is misbehaving.
+--by=hour, --by=day and --by=week bucket deliveries into time intervals in
+UTC, listed oldest first including empty buckets, so outages show up as a
+dip or a failure spike.
+
+Examples:
+  # Failure rate per event and action of an organization
+  gh hookmon stats --org=myorg --by=event
+
+  # Failure rate and latency per target domain
+  gh hookmon stats --org=myorg --by=domain
+
+  # Deliveries and failures per hour of the last two days
+  gh hookmon stats --org=myorg --by=hour --window=48h
+
+  # Only the last 24 hours of a single repository, as JSON
+  gh hookmon stats --repo=owner/repo --window=24h --json`,
+	SilenceUsage: true,
+	RunE:         runStats,
+}
+
+func init() {
+	statsCmd.Flags().StringVar(&statsOpts.By, "by", "event", "Group deliveries by ("+strings.Join(stats.DimensionNames(), ", ")+")")
+	statsCmd.Flags().StringVar(&statsOpts.Window, "window", "", "Only include deliveries of this time window, e.g. 24h or 7d (default: all fetched)")
+	statsCmd.Flags().BoolVar(&statsOpts.JSON, "json", false, "Output in JSON format")
+	statsCmd.Flags().StringVar(&cfg.Filter, "filter", "", "Filter webhook URLs by pattern")
+	rootCmd.AddCommand(statsCmd)
+}
+
+func runStats(cmd *cobra.Command, args []string) error {
+	dim, ok := stats.DimensionByName(statsOpts.By)
+	if !ok {
+		return fmt.Errorf("validation error: --by must be one of %s, got %q", strings.Join(stats.DimensionNames(), ", "), statsOpts.By)
+	}
+	var window time.Duration
+	if statsOpts.Window != "" {
+		var err error
+		if window, err = config.ParseDuration(statsOpts.Window); err != nil {
+			return fmt.Errorf("validation error: --window: %w", err)
+		}
+	}
+	if err := validateTarget(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	deliveries, err := fetchDeliveries(client)
+	if err != nil {
+		return err
+	}
+
+	if window > 0 {
+		since := time.Now().Add(-window)
+		inWindow := make([]github.Delivery, 0, len(deliveries))
+		for _, d := range deliveries {
+			if !d.DeliveredAt.Before(since) {
+				inWindow = append(inWindow, d)
+			}
+		}
+		deliveries = inWindow
+	}
+
+	groups := stats.Breakdown(deliveries, dim)
+	if statsOpts.JSON {
+		return output.FormatBreakdownJSON(groups, dim, os.Stdout)
+	}
+	if dim.Period != "" {
+		output.FormatTimelineTable(groups, dim, os.Stdout, !unicodeLocale())
+		return nil
+	}
+	output.FormatBreakdownTable(groups, dim, os.Stdout)
+	return nil
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/ohader/gh-hookmon/internal/filter"
+	"github.com/ohader/gh-hookmon/internal/github"
+	"github.com/ohader/gh-hookmon/internal/statsd"
+)
+
+// newStatsD connects to the StatsD server given by --statsd, nil if metrics are disabled
+func newStatsD() (*statsd.Client, error) {
+	if collectOpts.StatsD == "" {
+		return nil, nil
+	}
+	client, err := statsd.New(collectOpts.StatsD, collectOpts.StatsDPrefix, statsd.Flavor(collectOpts.StatsDFlavor))
+	if err != nil {
+		return nil, fmt.Errorf("validation error: --statsd: %w", err)
+	}
+	return client, nil
+}
+
+// collectMetrics is the outcome of a collect run reported to StatsD
+type collectMetrics struct {
+	Deliveries   []github.Delivery // New deliveries, nil if they are reported again by the next run
+	Hooks        int
+	Repositories int
+	FetchErrors  int
+}
+
+// emitCollectMetrics sends the metrics of a collect run
+// New deliveries and failures are counted per repository, hook and event, every latency is a sample of a distribution
+func emitCollectMetrics(client *statsd.Client, m collectMetrics) error {
+	type group struct {
+		repo       string
+		hookID     int
+		event      string
+		deliveries int
+		failures   int
+	}
+	groups := map[string]*group{}
+	for _, d := range m.Deliveries {
+		key := fmt.Sprintf("%s\x00%d\x00%s", d.Repository, d.HookID, d.Event)
+		g, ok := groups[key]
+		if !ok {
+			g = &group{repo: d.Repository, hookID: d.HookID, event: d.Event}
+			groups[key] = g
+		}
+		g.deliveries++
+		if filter.IsFailed(d.StatusCode) {
+			g.failures++
+		}
+		client.Timing("latency", d.Duration*1000, deliveryTags(d.Repository, d.HookID, d.Event)...)
+	}
+
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		g := groups[key]
+		tags := deliveryTags(g.repo, g.hookID, g.event)
+		client.Count("deliveries", g.deliveries, tags...)
+		client.Count("failures", g.failures, tags...)
+	}
+
+	client.Gauge("hooks", float64(m.Hooks))
+	client.Gauge("repositories", float64(m.Repositories))
+	client.Gauge("fetch_errors", float64(m.FetchErrors))
+	return client.Flush()
+}
+
+// deliveryTags returns the tags of delivery metrics
+func deliveryTags(repo string, hookID int, event string) []statsd.Tag {
+	return []statsd.Tag{
+		{Key: "repo", Value: repo},
+		{Key: "hook", Value: strconv.Itoa(hookID)},
+		{Key: "event", Value: event},
+	}
+}
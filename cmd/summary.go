@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/ohader/gh-hookmon/internal/github"
+	"github.com/ohader/gh-hookmon/internal/output"
+	"github.com/ohader/gh-hookmon/internal/stats"
+)
+
+// outputSummary prints the --summary-by summary of the filtered deliveries instead of the deliveries themselves
+func outputSummary(deliveries []github.Delivery) error {
+	jsonOutput := cfg.GetOutputFormat() == "json"
+	if cfg.SummaryBy == "hook" {
+		summaries := stats.SummarizeHooks(deliveries)
+		if jsonOutput {
+			return output.FormatHookSummaryJSON(summaries, os.Stdout)
+		}
+		output.FormatHookSummaryTable(summaries, os.Stdout)
+		return nil
+	}
+
+	summaries := stats.SummarizeRepositories(deliveries)
+	if jsonOutput {
+		return output.FormatRepositorySummaryJSON(summaries, os.Stdout)
+	}
+	output.FormatRepositorySummaryTable(summaries, os.Stdout)
+	return nil
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ohader/gh-hookmon/internal/config"
+	"github.com/ohader/gh-hookmon/internal/github"
+	"github.com/ohader/gh-hookmon/internal/output"
+	"github.com/ohader/gh-hookmon/internal/stats"
+	"github.com/spf13/cobra"
+)
+
+// topOptions holds the flags of the top subcommand
+type topOptions struct {
+	By            string
+	N             int
+	Window        string
+	MinDeliveries int
+	JSON          bool
+}
+
+var topOpts topOptions
+
+var topCmd = &cobra.Command{
+	Use:   "top",
+	Short: "Rank webhook target URLs by failures",
+	Long: `Rank the webhook target URLs by their number of failed deliveries or their
+failure rate within a time window, as the starting point of an incident
+investigation. URLs without failures are not listed.
+
+Examples:
+  # The 10 target URLs with the most failures in the last 24 hours
+  gh hookmon top --org=myorg --by=failures --n=10
+
+  # The highest failure rates of the last hour, ignoring URLs with fewer than 5 deliveries
+  gh hookmon top --org=myorg --by=rate --window=1h --min-deliveries=5`,
+	SilenceUsage: true,
+	RunE:         runTop,
+}
+
+func init() {
+	topCmd.Flags().StringVar(&topOpts.By, "by", string(stats.RankByFailures), "Rank by number of failures or failure rate (failures, rate)")
+	topCmd.Flags().IntVar(&topOpts.N, "n", 10, "Number of target URLs to show (0 = all)")
+	topCmd.Flags().StringVar(&topOpts.Window, "window", "24h", "Time window to evaluate, e.g. 1h, 24h or 7d")
+	topCmd.Flags().IntVar(&topOpts.MinDeliveries, "min-deliveries", 1, "Skip target URLs with fewer deliveries in the window")
+	topCmd.Flags().BoolVar(&topOpts.JSON, "json", false, "Output in JSON format")
+	topCmd.Flags().StringVar(&cfg.Filter, "filter", "", "Filter webhook URLs by pattern")
+	rootCmd.AddCommand(topCmd)
+}
+
+func runTop(cmd *cobra.Command, args []string) error {
+	by := stats.Ranking(topOpts.By)
+	if by != stats.RankByFailures && by != stats.RankByRate {
+		return fmt.Errorf("validation error: --by must be failures or rate, got %q", topOpts.By)
+	}
+	if topOpts.N < 0 {
+		return fmt.Errorf("validation error: --n must not be negative")
+	}
+	window, err := config.ParseDuration(topOpts.Window)
+	if err != nil {
+		return fmt.Errorf("validation error: --window: %w", err)
+	}
+	if err := validateTarget(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	deliveries, err := fetchDeliveries(client)
+	if err != nil {
+		return err
+	}
+
+	since := time.Now().Add(-window)
+	inWindow := make([]github.Delivery, 0, len(deliveries))
+	for _, d := range deliveries {
+		if !d.DeliveredAt.Before(since) {
+			inWindow = append(inWindow, d)
+		}
+	}
+
+	dim, _ := stats.DimensionByName("url")
+	top := stats.Top(stats.Breakdown(inWindow, dim), by, topOpts.N, topOpts.MinDeliveries)
+	if topOpts.JSON {
+		return output.FormatBreakdownJSON(top, dim, os.Stdout)
+	}
+	if len(top) == 0 {
+		fmt.Printf("No failed deliveries in the last %s\n", topOpts.Window)
+		return nil
+	}
+	output.FormatBreakdownTable(top, dim, os.Stdout)
+	return nil
+}
@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ohader/gh-hookmon/internal/github"
+	"github.com/spf13/cobra"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// tracing holds the OpenTelemetry setup of the run (--otel-endpoint), nil if tracing is disabled
+var tracing *tracingRun
+
+// tracer starts the spans of the run, it records nothing unless --otel-endpoint is set
+var tracer trace.Tracer = noop.NewTracerProvider().Tracer("")
+
+// runContext carries the root span of the run, the parent of all spans without a more specific one
+var runContext = context.Background()
+
+// tracingRun is the tracer provider exporting the spans of the run together with its root span
+type tracingRun struct {
+	provider *sdktrace.TracerProvider
+	root     trace.Span
+}
+
+// otlpTimeout limits sending the spans at the end of the run
+const otlpTimeout = 10 * time.Second
+
+// startTracing starts the root span of the run when --otel-endpoint is set
+// Spans are sent in batches to the OTLP/HTTP endpoint, OTEL_EXPORTER_OTLP_HEADERS adds headers, e.g. an API key
+func startTracing(cmd *cobra.Command) {
+	if cfg.OtelEndpoint == "" {
+		return
+	}
+
+	transport, err := github.NewTransport(cfg.CABundle)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to export traces: %v\n", err)
+		return
+	}
+	// The endpoint is the collector's base URL, e.g. http://localhost:4318, or its full /v1/traces URL
+	target := strings.TrimSuffix(cfg.OtelEndpoint, "/")
+	if !strings.HasSuffix(target, "/v1/traces") {
+		target += "/v1/traces"
+	}
+	exporter, err := otlptracehttp.New(context.Background(),
+		otlptracehttp.WithEndpointURL(target),
+		otlptracehttp.WithHTTPClient(&http.Client{Transport: transport, Timeout: otlpTimeout}),
+	)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to export traces: %v\n", err)
+		return
+	}
+
+	// Export failures are warnings, they do not change the outcome of the run
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		fmt.Fprintf(os.Stderr, "Warning: failed to export traces: %v\n", err)
+	}))
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", "gh-hookmon"))),
+	)
+	tracer = provider.Tracer("gh-hookmon")
+
+	var attributes []attribute.KeyValue
+	if cfg.Org != "" {
+		attributes = append(attributes, attribute.String("github.org", cfg.Org))
+	}
+	if cfg.Team != "" {
+		attributes = append(attributes, attribute.String("github.team", cfg.Team))
+	}
+	if cfg.Repo != "" {
+		attributes = append(attributes, attribute.String("github.repository", cfg.Repo))
+	}
+	var root trace.Span
+	runContext, root = tracer.Start(context.Background(), cmd.CommandPath(), trace.WithAttributes(attributes...))
+	tracing = &tracingRun{provider: provider, root: root}
+}
+
+// stopTracing ends the root span and exports the remaining spans
+func stopTracing(err error) {
+	if tracing == nil {
+		return
+	}
+	endSpan(tracing.root, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), otlpTimeout)
+	defer cancel()
+	if err := tracing.provider.Shutdown(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to export traces: %v\n", err)
+	}
+}
+
+// endSpan ends a span, marking it as failed if err is set
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// traceTransport records a client span for every request sent through base
+// Requests sent without a span in their context become children of the root span of the run
+func traceTransport(base http.RoundTripper) http.RoundTripper {
+	if tracing == nil {
+		return base
+	}
+	return runSpanTransport{base: otelhttp.NewTransport(base,
+		otelhttp.WithTracerProvider(tracing.provider),
+		otelhttp.WithSpanNameFormatter(func(_ string, req *http.Request) string {
+			// GitHub Enterprise Server serves the API below /api/v3
+			return req.Method + " " + strings.TrimPrefix(req.URL.Path, "/api/v3")
+		}),
+	)}
+}
+
+// runSpanTransport sends requests without a span in the context of the root span
+type runSpanTransport struct {
+	base http.RoundTripper
+}
+
+func (t runSpanTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !trace.SpanContextFromContext(req.Context()).IsValid() {
+		req = req.WithContext(trace.ContextWithSpan(req.Context(), tracing.root))
+	}
+	return t.base.RoundTrip(req)
+}
+
+// startSpan starts a span as a child of the client's context
+// The returned client sends its API requests within the span, which must be ended with endSpan
+func startSpan(client *github.Client, name string, attributes ...attribute.KeyValue) (*github.Client, trace.Span) {
+	ctx, span := tracer.Start(client.Context(), name, trace.WithAttributes(attributes...))
+	return client.WithContext(ctx), span
+}
+
+// traceScan scans a repository within a span, the client passed to scan sends the repository's API requests within it
+func traceScan[T any](client *github.Client, repo string, scan func(client *github.Client, repo string) ([]T, error)) ([]T, error) {
+	scanClient, span := startSpan(client, "scan "+repo, attribute.String("github.repository", repo))
+	items, err := scan(scanClient, repo)
+	endSpan(span, err)
+	return items, err
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ohader/gh-hookmon/internal/filter"
+	"github.com/ohader/gh-hookmon/internal/github"
+	"github.com/ohader/gh-hookmon/internal/history"
+	"github.com/ohader/gh-hookmon/internal/output"
+	"github.com/ohader/gh-hookmon/internal/stats"
+	"github.com/spf13/cobra"
+)
+
+// trendOptions holds the flags of the trend subcommand
+type trendOptions struct {
+	Period    string
+	Periods   int
+	Threshold string
+	JSON      bool
+}
+
+var trendOpts trendOptions
+
+var trendCmd = &cobra.Command{
+	Use:   "trend",
+	Short: "Show the median latency of every hook per day or week from the history",
+	Long: `Show the median delivery latency of every hook per day or week, based on the
+deliveries recorded in the history database by collect or runs with --record.
+
+Arrows compare each period with the previous one, the trend column compares
+the last period with the first. Gradually degrading receivers show up as
+rising latency well before their deliveries start timing out. --org and --repo
+are optional and restrict the report to an organization or repository.
+
+Examples:
+  # Median latency per day of the last week
+  gh hookmon trend --org=myorg
+
+  # Median latency per week of the last quarter, only changes of 25% or more count
+  gh hookmon trend --org=myorg --period=week --periods=13 --threshold=25%`,
+	SilenceUsage: true,
+	RunE:         runTrend,
+}
+
+func init() {
+	trendCmd.Flags().StringVar(&trendOpts.Period, "period", "day", "Length of a period (day, week)")
+	trendCmd.Flags().IntVar(&trendOpts.Periods, "periods", 7, "Number of periods to show, ending with the current one")
+	trendCmd.Flags().StringVar(&trendOpts.Threshold, "threshold", "10%", "Minimum change of the median latency shown as rising or falling")
+	trendCmd.Flags().BoolVar(&trendOpts.JSON, "json", false, "Output in JSON format")
+	trendCmd.Flags().StringVar(&cfg.Filter, "filter", "", "Filter webhook URLs by pattern")
+	rootCmd.AddCommand(trendCmd)
+}
+
+func runTrend(cmd *cobra.Command, args []string) error {
+	period := stats.Period(trendOpts.Period)
+	if period != stats.PeriodDay && period != stats.PeriodWeek {
+		return fmt.Errorf("validation error: --period must be day or week, got %q", trendOpts.Period)
+	}
+	if trendOpts.Periods < 1 {
+		return fmt.Errorf("validation error: --periods must be at least 1")
+	}
+	threshold, err := parseRelativeIncrease(trendOpts.Threshold)
+	if err != nil {
+		return fmt.Errorf("validation error: --threshold: %w", err)
+	}
+	if err := cfg.ValidateOptional(); err != nil {
+		return fmt.Errorf("validation error: %w", err)
+	}
+
+	store, err := openHistory()
+	if err != nil {
+		return err
+	}
+
+	opts := stats.TrendOptions{Period: period, Periods: trendOpts.Periods, Threshold: threshold}
+	now := time.Now()
+	starts := stats.PeriodStarts(opts, now)
+	query := history.Query{
+		Org:   cfg.Org,
+		Repo:  cfg.Repo,
+		Repos: cfg.Repos,
+		Since: &starts[0],
+	}
+	deliveries, err := store.Deliveries(query)
+	if err != nil {
+		return err
+	}
+	if err := incompleteHistory(store, query, starts[0], "the periods before are empty"); err != nil {
+		return err
+	}
+	if cfg.Filter != "" {
+		deliveries = filterByURL(deliveries, cfg.Filter)
+	}
+
+	trends := stats.ComputeTrends(deliveries, opts, now)
+	if trendOpts.JSON {
+		return output.FormatTrendJSON(trends, os.Stdout)
+	}
+	output.FormatTrendTable(trends, starts, period, os.Stdout)
+	return nil
+}
+
+// filterByURL keeps the deliveries whose target URL matches the pattern
+func filterByURL(deliveries []github.Delivery, pattern string) []github.Delivery {
+	filtered := make([]github.Delivery, 0, len(deliveries))
+	for _, d := range deliveries {
+		if filter.MatchesPattern(d.URL, pattern) {
+			filtered = append(filtered, d)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/ohader/gh-hookmon/internal/github"
+	"github.com/ohader/gh-hookmon/internal/s3"
+)
+
+// uploader uploads written files to the --upload location
+type uploader struct {
+	client   *s3.Client
+	location s3.Location
+}
+
+// newUploader checks the --upload location and loads the AWS credentials, so problems show up before anything is fetched
+// Returns nil without a destination
+func newUploader(destination string) (*uploader, error) {
+	if destination == "" {
+		return nil, nil
+	}
+	location, err := s3.ParseURL(destination)
+	if err != nil {
+		return nil, fmt.Errorf("validation error: --upload: %w", err)
+	}
+	credentials, err := s3.LoadCredentials()
+	if err != nil {
+		return nil, fmt.Errorf("--upload: %w", err)
+	}
+	transport, err := github.NewTransport(cfg.CABundle)
+	if err != nil {
+		return nil, err
+	}
+
+	return &uploader{
+		client: &s3.Client{
+			// Uploads of large exports may take longer than --request-timeout, only --deadline applies
+			HTTP:        &http.Client{Transport: traceTransport(deadline.transport(transport))},
+			Credentials: credentials,
+			Endpoint:    s3.EndpointFromEnv(),
+		},
+		location: location,
+	}, nil
+}
+
+// upload uploads a file and reports where it was stored, a nil uploader does nothing
+func (u *uploader) upload(file string) error {
+	if u == nil {
+		return nil
+	}
+	url, err := u.client.Upload(file, u.location)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Uploaded %s to %s\n", file, url)
+	return nil
+}
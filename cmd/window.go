@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ohader/gh-hookmon/internal/github"
+	"github.com/ohader/gh-hookmon/internal/history"
+	"github.com/ohader/gh-hookmon/internal/output"
+	"github.com/ohader/gh-hookmon/internal/stats"
+)
+
+// windowStart is the earliest start of the time windows evaluated by a report, zero for reports without a window
+// Delivery listings fetch pages beyond --limit until they reach back to it
+var windowStart time.Time
+
+// coverWindow makes the delivery listings of the run reach back to since, beyond --limit if necessary
+func coverWindow(since time.Time) {
+	if windowStart.IsZero() || since.Before(windowStart) {
+		windowStart = since
+	}
+}
+
+// incompleteWindow returns the hooks whose deliveries end at --limit after since and warns about each of them
+// consequence describes what the gap means for the report, e.g. "the heatmap misses the start of the window"
+// Listings fetched with coverWindow are complete: they only end after since if GitHub has no older deliveries.
+// Deliveries cached by earlier runs for --offline may still be cut short.
+func incompleteWindow(deliveries []github.Delivery, since time.Time, consequence string) map[stats.HookKey]bool {
+	if !cfg.Offline && !windowStart.IsZero() && !windowStart.After(since) {
+		return nil
+	}
+
+	counts := make(map[stats.HookKey]int)
+	oldest := make(map[stats.HookKey]time.Time)
+	orgs := make(map[stats.HookKey]string)
+	for _, d := range deliveries {
+		key := stats.ByHook(d)
+		counts[key]++
+		if first, ok := oldest[key]; !ok || d.DeliveredAt.Before(first) {
+			oldest[key] = d.DeliveredAt
+		}
+		orgs[key] = d.Org
+	}
+
+	incomplete := make(map[stats.HookKey]bool)
+	for _, key := range stats.SortedHookKeys(counts) {
+		if counts[key] < cfg.Limit || !oldest[key].After(since) {
+			continue
+		}
+		incomplete[key] = true
+
+		hook := fmt.Sprintf("hook %d in %s", key.HookID, key.Repository)
+		if orgs[key] != "" {
+			hook = fmt.Sprintf("organization hook %d of %s in %s", key.HookID, orgs[key], key.Repository)
+		}
+		hint := "raise --limit"
+		if cfg.Offline {
+			hint = "run once without --offline to cache the whole window"
+		}
+		warnAlways(output.Issue{
+			Kind:       output.IssueTruncated,
+			Repository: key.Repository,
+			HookID:     key.HookID,
+			Message:    fmt.Sprintf("%s only reaches back to %s with %d deliveries, %s (%s)", hook, oldest[key].Format(time.RFC3339), counts[key], consequence, hint),
+		})
+	}
+	return incomplete
+}
+
+// incompleteHistory warns if the recorded deliveries matching the query do not reach back to since
+// The history only holds what collect and runs with --record fetched, reports of earlier times would be empty
+func incompleteHistory(store *history.Store, q history.Query, since time.Time, consequence string) error {
+	q.Since = nil
+	oldest, err := store.Oldest(q)
+	if err != nil || oldest == nil || !oldest.After(since) {
+		return err
+	}
+	warnAlways(output.Issue{
+		Kind:    output.IssueTruncated,
+		Message: fmt.Sprintf("the history only reaches back to %s, %s (record deliveries with collect or --record)", oldest.Format(time.RFC3339), consequence),
+	})
+	return nil
+}
@@ -0,0 +1,162 @@
+package alert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Providers lists the supported incident management services
+var Providers = []string{"pagerduty", "opsgenie"}
+
+// Alert describes a failing hook, or a hook that recovered when resolving
+// Key identifies the hook across runs, so repeated triggers update one incident and a resolve closes it
+type Alert struct {
+	Key     string
+	Summary string
+	Source  string
+	Details map[string]interface{}
+}
+
+// Sink opens and resolves incidents
+type Sink interface {
+	Trigger(a Alert) error
+	Resolve(a Alert) error
+}
+
+// DefaultPagerDutyURL is the endpoint of the PagerDuty Events API v2
+const DefaultPagerDutyURL = "https://events.pagerduty.com/v2/enqueue"
+
+// DefaultOpsgenieURL is the Opsgenie API of the US region, the EU region uses https://api.eu.opsgenie.com
+const DefaultOpsgenieURL = "https://api.opsgenie.com"
+
+// PagerDuty sends events to a PagerDuty service integration (Events API v2)
+type PagerDuty struct {
+	HTTP       *http.Client
+	RoutingKey string // Integration key of the service
+	URL        string // Events API endpoint, DefaultPagerDutyURL if empty
+}
+
+// Trigger opens an incident, or updates the open incident with the same key
+func (p *PagerDuty) Trigger(a Alert) error {
+	return p.send(map[string]interface{}{
+		"routing_key":  p.RoutingKey,
+		"event_action": "trigger",
+		"dedup_key":    a.Key,
+		"payload": map[string]interface{}{
+			"summary":        a.Summary,
+			"source":         a.Source,
+			"severity":       "error",
+			"component":      "webhooks",
+			"custom_details": a.Details,
+		},
+	})
+}
+
+// Resolve resolves the incident with the key, PagerDuty ignores keys without an open incident
+// Resolve events carry no payload, the summary of the recovery is not shown
+func (p *PagerDuty) Resolve(a Alert) error {
+	return p.send(map[string]interface{}{
+		"routing_key":  p.RoutingKey,
+		"event_action": "resolve",
+		"dedup_key":    a.Key,
+	})
+}
+
+func (p *PagerDuty) send(event map[string]interface{}) error {
+	endpoint := p.URL
+	if endpoint == "" {
+		endpoint = DefaultPagerDutyURL
+	}
+	return post(p.HTTP, endpoint, nil, event, "PagerDuty")
+}
+
+// Opsgenie creates and closes Opsgenie alerts (Alert API v2)
+type Opsgenie struct {
+	HTTP   *http.Client
+	APIKey string // Key of an API integration
+	URL    string // Base URL of the API, DefaultOpsgenieURL if empty
+}
+
+// Trigger creates an alert, Opsgenie deduplicates open alerts with the same alias
+func (o *Opsgenie) Trigger(a Alert) error {
+	// Opsgenie only accepts string details
+	details := make(map[string]string, len(a.Details))
+	for key, value := range a.Details {
+		details[key] = fmt.Sprint(value)
+	}
+	return o.send("/v2/alerts", map[string]interface{}{
+		"message":     truncate(a.Summary, 130),
+		"alias":       truncate(a.Key, 512),
+		"description": a.Summary,
+		"source":      a.Source,
+		"details":     details,
+		"priority":    "P2",
+	})
+}
+
+// Resolve closes the alert with the key as alias, the summary of the recovery becomes the note of the close
+func (o *Opsgenie) Resolve(a Alert) error {
+	path := "/v2/alerts/" + url.PathEscape(truncate(a.Key, 512)) + "/close?identifierType=alias"
+	return o.send(path, map[string]interface{}{"source": "gh-hookmon", "note": truncate(a.Summary, 25000)})
+}
+
+func (o *Opsgenie) send(path string, body map[string]interface{}) error {
+	base := o.URL
+	if base == "" {
+		base = DefaultOpsgenieURL
+	}
+	headers := map[string]string{"Authorization": "GenieKey " + o.APIKey}
+	return post(o.HTTP, strings.TrimSuffix(base, "/")+path, headers, body, "Opsgenie")
+}
+
+// post sends a JSON document and fails on any status code outside 2xx
+func post(client *http.Client, endpoint string, headers map[string]string, body interface{}, service string) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event: %w", service, err)
+	}
+	request, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create %s request: %w", service, err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		request.Header.Set(key, value)
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to send %s event: %w", service, err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+		return &RejectedError{Service: service, StatusCode: response.StatusCode, Status: response.Status, Message: strings.TrimSpace(string(message))}
+	}
+	return nil
+}
+
+// RejectedError is returned when a service answers with a status code outside 2xx
+type RejectedError struct {
+	Service    string
+	StatusCode int
+	Status     string
+	Message    string // Beginning of the response body
+}
+
+func (e *RejectedError) Error() string {
+	return fmt.Sprintf("%s rejected the event: %s %s", e.Service, e.Status, e.Message)
+}
+
+// truncate shortens s to at most n bytes, as the services reject longer fields
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return strings.ToValidUTF8(s[:n], "")
+}
@@ -0,0 +1,80 @@
+package alert
+
+import (
+	"errors"
+	"net/http"
+	"time"
+)
+
+// Webhook posts alert documents to an arbitrary URL, for alerting systems without a dedicated integration
+type Webhook struct {
+	HTTP    *http.Client
+	URL     string
+	Headers map[string]string
+	Retries int           // Further attempts after a failed one
+	Backoff time.Duration // Wait before the first retry, doubled after each attempt
+}
+
+// Document is the JSON body posted by Webhook
+type Document struct {
+	Status    string                 `json:"status"` // firing or resolved
+	Key       string                 `json:"key"`
+	Summary   string                 `json:"summary,omitempty"`
+	Source    string                 `json:"source,omitempty"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+	Timestamp time.Time              `json:"timestamp"`
+	Generator string                 `json:"generator"`
+}
+
+// Document statuses
+const (
+	StatusFiring   = "firing"
+	StatusResolved = "resolved"
+)
+
+// Trigger posts a firing document, repeated for every run the hook keeps failing
+func (w *Webhook) Trigger(a Alert) error {
+	return w.send(Document{
+		Status:  StatusFiring,
+		Key:     a.Key,
+		Summary: a.Summary,
+		Source:  a.Source,
+		Details: a.Details,
+	})
+}
+
+// Resolve posts a resolved document, telling the receiver that the hook recovered
+func (w *Webhook) Resolve(a Alert) error {
+	return w.send(Document{
+		Status:  StatusResolved,
+		Key:     a.Key,
+		Summary: a.Summary,
+		Source:  a.Source,
+		Details: a.Details,
+	})
+}
+
+// send posts a document, retrying connection errors, 429 and 5xx responses with exponential backoff
+func (w *Webhook) send(doc Document) error {
+	doc.Timestamp = time.Now().UTC()
+	doc.Generator = "gh-hookmon"
+
+	backoff := w.Backoff
+	for attempt := 0; ; attempt++ {
+		err := post(w.HTTP, w.URL, w.Headers, doc, "alert webhook")
+		if err == nil || attempt >= w.Retries || !retryable(err) {
+			return err
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// retryable reports whether a failed request may succeed when repeated
+func retryable(err error) bool {
+	var rejected *RejectedError
+	if errors.As(err, &rejected) {
+		return rejected.StatusCode == http.StatusTooManyRequests || rejected.StatusCode >= 500
+	}
+	return true
+}
@@ -0,0 +1,83 @@
+package archive
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Extension marks gzip-compressed files
+const Extension = ".gz"
+
+// Path returns the file name an export is written to, with the .gz extension added when compressing
+func Path(path string, compress bool) string {
+	if compress && !strings.HasSuffix(path, Extension) {
+		return path + Extension
+	}
+	return path
+}
+
+// writer compresses everything written to the underlying file
+type writer struct {
+	*gzip.Writer
+	file *os.File
+}
+
+// Close flushes the compressed data and closes the file
+func (w *writer) Close() error {
+	if err := w.Writer.Close(); err != nil {
+		w.file.Close()
+		return err
+	}
+	return w.file.Close()
+}
+
+// Create creates an export file, gzip-compressed if path ends with .gz
+// The caller must close the returned writer, errors from Close mean the file is incomplete
+func Create(path string) (io.WriteCloser, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(path, Extension) {
+		return file, nil
+	}
+	return &writer{Writer: gzip.NewWriter(file), file: file}, nil
+}
+
+// reader decompresses the underlying file
+type reader struct {
+	*gzip.Reader
+	file *os.File
+}
+
+// Close closes the decompressor and the file
+func (r *reader) Close() error {
+	r.Reader.Close()
+	return r.file.Close()
+}
+
+// Open opens an export file, compressed files are detected by their content rather than their name
+func Open(path string) (io.ReadCloser, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	buffered := bufio.NewReader(file)
+	if magic, _ := buffered.Peek(2); len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(buffered)
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to decompress: %w", err)
+		}
+		return &reader{Reader: gz, file: file}, nil
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{buffered, file}, nil
+}
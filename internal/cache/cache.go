@@ -0,0 +1,238 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Cache stores JSON-encoded API results on disk
+// Each key is stored in its own file, named by the hash of the key
+type Cache struct {
+	dir string
+}
+
+// entry is the on-disk representation of a cached value
+type entry struct {
+	Key      string          `json:"key"`
+	StoredAt time.Time       `json:"stored_at"`
+	Data     json.RawMessage `json:"data"`
+}
+
+// New creates a cache in the user's cache directory (e.g. ~/.cache/gh-hookmon)
+func New() (*Cache, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine cache directory: %w", err)
+	}
+	return NewAt(filepath.Join(base, "gh-hookmon"))
+}
+
+// NewAt creates a cache in the given directory
+func NewAt(dir string) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	return &Cache{dir: dir}, nil
+}
+
+// Dir returns the directory the cache is stored in
+func (c *Cache) Dir() string {
+	return c.dir
+}
+
+// Get loads the value stored for key into v
+// Returns false if there is no entry or the entry is older than ttl
+func (c *Cache) Get(key string, ttl time.Duration, v interface{}) (bool, error) {
+	e, found, err := c.read(key)
+	if err != nil || !found {
+		return false, err
+	}
+
+	if ttl > 0 && time.Since(e.StoredAt) > ttl {
+		return false, nil
+	}
+
+	if err := json.Unmarshal(e.Data, v); err != nil {
+		return false, fmt.Errorf("failed to parse cached data: %w", err)
+	}
+
+	return true, nil
+}
+
+// Load loads the value stored for key into v regardless of its age
+// Returns when the value was stored, or false if there is no entry
+func (c *Cache) Load(key string, v interface{}) (time.Time, bool, error) {
+	e, found, err := c.read(key)
+	if err != nil || !found {
+		return time.Time{}, false, err
+	}
+
+	if err := json.Unmarshal(e.Data, v); err != nil {
+		return time.Time{}, false, fmt.Errorf("failed to parse cached data: %w", err)
+	}
+
+	return e.StoredAt, true, nil
+}
+
+// read loads the entry stored for key
+func (c *Cache) read(key string) (entry, bool, error) {
+	var e entry
+	data, err := os.ReadFile(c.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return e, false, nil
+	}
+	if err != nil {
+		return e, false, fmt.Errorf("failed to read cache entry: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &e); err != nil {
+		return e, false, fmt.Errorf("failed to parse cache entry: %w", err)
+	}
+
+	return e, true, nil
+}
+
+// Set stores v for key
+func (c *Cache) Set(key string, v interface{}) error {
+	return c.SetAt(key, v, time.Now())
+}
+
+// SetAt stores v for key as if it was stored at storedAt, e.g. for imported data
+func (c *Cache) SetAt(key string, v interface{}, storedAt time.Time) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache data: %w", err)
+	}
+
+	encoded, err := json.Marshal(entry{
+		Key:      key,
+		StoredAt: storedAt.UTC(),
+		Data:     data,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode cache entry: %w", err)
+	}
+
+	// Write to a temporary file first so concurrent readers never see partial entries
+	tmp, err := os.CreateTemp(c.dir, "entry-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	if _, err := tmp.Write(encoded); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), c.path(key)); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes the entry stored for key, if any
+func (c *Cache) Delete(key string) error {
+	err := os.Remove(c.path(key))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete cache entry: %w", err)
+	}
+	return nil
+}
+
+// Stats summarizes the entries of a cache
+type Stats struct {
+	Dir     string     `json:"dir"`
+	Entries int        `json:"entries"`
+	Expired int        `json:"expired"` // Entries older than the TTL, replaced on next use
+	Size    int64      `json:"size_bytes"`
+	Oldest  *time.Time `json:"oldest,omitempty"`
+	Newest  *time.Time `json:"newest,omitempty"`
+}
+
+// Stats summarizes the cache entries, entries older than ttl count as expired
+// Unreadable entries are counted but do not fail the summary
+func (c *Cache) Stats(ttl time.Duration) (Stats, error) {
+	stats := Stats{Dir: c.dir}
+	files, err := c.entryFiles()
+	if err != nil {
+		return stats, err
+	}
+
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			continue
+		}
+		stats.Entries++
+		stats.Size += info.Size()
+
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		var e entry
+		if err := json.Unmarshal(data, &e); err != nil {
+			continue
+		}
+		storedAt := e.StoredAt
+		if stats.Oldest == nil || storedAt.Before(*stats.Oldest) {
+			stats.Oldest = &storedAt
+		}
+		if stats.Newest == nil || storedAt.After(*stats.Newest) {
+			stats.Newest = &storedAt
+		}
+		if ttl > 0 && time.Since(storedAt) > ttl {
+			stats.Expired++
+		}
+	}
+
+	return stats, nil
+}
+
+// Clear removes all entries and returns the number of removed entries
+func (c *Cache) Clear() (int, error) {
+	files, err := c.entryFiles()
+	if err != nil {
+		return 0, err
+	}
+
+	// Leftovers of interrupted writes are removed as well, but not counted
+	temporary, _ := filepath.Glob(filepath.Join(c.dir, "entry-*.tmp"))
+	for _, file := range temporary {
+		os.Remove(file)
+	}
+
+	removed := 0
+	for _, file := range files {
+		if err := os.Remove(file); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return removed, fmt.Errorf("failed to delete cache entry: %w", err)
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// entryFiles returns the paths of all entry files
+func (c *Cache) entryFiles() ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(c.dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cache entries: %w", err)
+	}
+	return files, nil
+}
+
+func (c *Cache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
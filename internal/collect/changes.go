@@ -0,0 +1,101 @@
+package collect
+
+import (
+	"sort"
+
+	"github.com/ohader/gh-hookmon/internal/filter"
+	"github.com/ohader/gh-hookmon/internal/github"
+)
+
+// Observation is a hook as seen by the current run
+type Observation struct {
+	Repository string
+	Hook       github.Hook
+	Deliveries []github.Delivery // nil if the deliveries could not be fetched
+}
+
+// Changes is what happened since the previous run
+type Changes struct {
+	NewDeliveries []github.Delivery // Deliveries made since the previous run
+	NewFailures   []github.Delivery // Failed deliveries made since the previous run, oldest first
+	Recovered     []Hook            // Hooks whose newest delivery failed before and succeeds now
+	Added         []Hook
+	Removed       []Hook
+}
+
+// Update compares the observed hooks with the state and advances the state to them
+// Hooks of repositories missing from scanned are kept, as their absence may be a fetch error
+// Hooks whose URL does not match urlFilter are kept as well, the filter left them out of the observations
+// Added and removed hooks are not reported on the first run, when every hook would count as added
+func (s *State) Update(observations []Observation, scanned map[string]bool, urlFilter string) Changes {
+	var changes Changes
+	first := s.IsNew()
+	seen := make(map[string]bool, len(observations))
+
+	for _, o := range observations {
+		key := hookKey(o.Repository, o.Hook.ID)
+		seen[key] = true
+		previous, known := s.Hooks[key]
+		current := previous
+		current.Repository = o.Repository
+		current.ID = o.Hook.ID
+		current.URL = o.Hook.GetTargetURL()
+		if !known && !first {
+			changes.Added = append(changes.Added, current)
+		}
+
+		var newest *github.Delivery
+		for i, d := range o.Deliveries {
+			if newest == nil || d.DeliveredAt.After(newest.DeliveredAt) {
+				newest = &o.Deliveries[i]
+			}
+			if d.ID > current.LastDeliveryID {
+				current.LastDeliveryID = d.ID
+			}
+			if d.ID <= previous.LastDeliveryID {
+				continue
+			}
+			changes.NewDeliveries = append(changes.NewDeliveries, d)
+			if filter.IsFailed(d.StatusCode) {
+				changes.NewFailures = append(changes.NewFailures, d)
+			}
+		}
+		if current.LastDeliveryID > previous.LastDeliveryID {
+			current.Failing = filter.IsFailed(newest.StatusCode)
+			if previous.Failing && !current.Failing {
+				changes.Recovered = append(changes.Recovered, current)
+			}
+		}
+		s.Hooks[key] = current
+	}
+
+	for key, h := range s.Hooks {
+		if seen[key] || !scanned[h.Repository] {
+			continue
+		}
+		// Hooks without a known URL are observed regardless of the filter
+		if h.URL != "" && !filter.MatchesPattern(h.URL, urlFilter) {
+			continue
+		}
+		changes.Removed = append(changes.Removed, h)
+		delete(s.Hooks, key)
+	}
+
+	sort.Slice(changes.NewFailures, func(i, j int) bool {
+		return changes.NewFailures[i].DeliveredAt.Before(changes.NewFailures[j].DeliveredAt)
+	})
+	sortHooks(changes.Recovered)
+	sortHooks(changes.Added)
+	sortHooks(changes.Removed)
+	return changes
+}
+
+// sortHooks orders hooks by repository and ID
+func sortHooks(hooks []Hook) {
+	sort.Slice(hooks, func(i, j int) bool {
+		if hooks[i].Repository != hooks[j].Repository {
+			return hooks[i].Repository < hooks[j].Repository
+		}
+		return hooks[i].ID < hooks[j].ID
+	})
+}
@@ -0,0 +1,219 @@
+package collect
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/ohader/gh-hookmon/internal/github"
+)
+
+var base = time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+func hook(id int, url string) github.Hook {
+	h := github.Hook{ID: id}
+	h.Config.URL = url
+	return h
+}
+
+func delivery(id, statusCode int) github.Delivery {
+	return github.Delivery{ID: id, StatusCode: statusCode, DeliveredAt: base.Add(time.Duration(id) * time.Minute)}
+}
+
+// savedState returns a state as loaded after a previous run
+func savedState(hooks ...Hook) *State {
+	s := &State{Version: Version, UpdatedAt: base, Hooks: map[string]Hook{}}
+	for _, h := range hooks {
+		s.Hooks[hookKey(h.Repository, h.ID)] = h
+	}
+	return s
+}
+
+func hookIDs(hooks []Hook) []int {
+	ids := make([]int, len(hooks))
+	for i, h := range hooks {
+		ids[i] = h.ID
+	}
+	return ids
+}
+
+func deliveryIDs(deliveries []github.Delivery) []int {
+	ids := make([]int, len(deliveries))
+	for i, d := range deliveries {
+		ids[i] = d.ID
+	}
+	return ids
+}
+
+func equalIDs(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestStateUpdate(t *testing.T) {
+	ci := Hook{Repository: "acme/web", ID: 1, URL: "https://ci.example.com/hook", LastDeliveryID: 10}
+	chat := Hook{Repository: "acme/web", ID: 2, URL: "https://chat.example.com/hook", LastDeliveryID: 20, Failing: true}
+	api := Hook{Repository: "acme/api", ID: 3, URL: "https://ci.example.com/hook", LastDeliveryID: 30}
+
+	tests := []struct {
+		name          string
+		state         *State
+		observations  []Observation
+		scanned       map[string]bool
+		urlFilter     string
+		newDeliveries []int
+		newFailures   []int
+		recovered     []int
+		added         []int
+		removed       []int
+		kept          []int
+	}{
+		{
+			name:  "first run reports deliveries but no added hooks",
+			state: &State{Version: Version, Hooks: map[string]Hook{}},
+			observations: []Observation{
+				{Repository: "acme/web", Hook: hook(1, ci.URL), Deliveries: []github.Delivery{delivery(11, 200), delivery(12, 500)}},
+			},
+			scanned:       map[string]bool{"acme/web": true},
+			newDeliveries: []int{11, 12},
+			newFailures:   []int{12},
+			kept:          []int{1},
+		},
+		{
+			name:  "only deliveries newer than the state are new",
+			state: savedState(ci),
+			observations: []Observation{
+				{Repository: "acme/web", Hook: hook(1, ci.URL), Deliveries: []github.Delivery{delivery(13, 0), delivery(9, 500), delivery(10, 500), delivery(11, 200)}},
+			},
+			scanned:       map[string]bool{"acme/web": true},
+			newDeliveries: []int{13, 11},
+			newFailures:   []int{13},
+			kept:          []int{1},
+		},
+		{
+			name:  "failures are ordered oldest first",
+			state: savedState(ci),
+			observations: []Observation{
+				{Repository: "acme/web", Hook: hook(1, ci.URL), Deliveries: []github.Delivery{delivery(14, 502), delivery(12, 404)}},
+			},
+			scanned:       map[string]bool{"acme/web": true},
+			newDeliveries: []int{14, 12},
+			newFailures:   []int{12, 14},
+			kept:          []int{1},
+		},
+		{
+			name:  "hook recovers when its newest delivery succeeds",
+			state: savedState(chat),
+			observations: []Observation{
+				{Repository: "acme/web", Hook: hook(2, chat.URL), Deliveries: []github.Delivery{delivery(22, 200), delivery(21, 500)}},
+			},
+			scanned:       map[string]bool{"acme/web": true},
+			newDeliveries: []int{22, 21},
+			newFailures:   []int{21},
+			recovered:     []int{2},
+			kept:          []int{2},
+		},
+		{
+			name:  "hook without new deliveries does not recover",
+			state: savedState(chat),
+			observations: []Observation{
+				{Repository: "acme/web", Hook: hook(2, chat.URL), Deliveries: []github.Delivery{delivery(20, 200)}},
+			},
+			scanned: map[string]bool{"acme/web": true},
+			kept:    []int{2},
+		},
+		{
+			name:  "new and missing hooks of scanned repositories are added and removed",
+			state: savedState(ci, chat),
+			observations: []Observation{
+				{Repository: "acme/web", Hook: hook(1, ci.URL)},
+				{Repository: "acme/web", Hook: hook(4, "https://new.example.com/hook")},
+			},
+			scanned: map[string]bool{"acme/web": true},
+			added:   []int{4},
+			removed: []int{2},
+			kept:    []int{1, 4},
+		},
+		{
+			name:         "hooks of repositories that were not scanned are kept",
+			state:        savedState(ci, api),
+			observations: []Observation{{Repository: "acme/web", Hook: hook(1, ci.URL)}},
+			scanned:      map[string]bool{"acme/web": true, "acme/api": false},
+			kept:         []int{1, 3},
+		},
+		{
+			name:         "hooks excluded by the URL filter are kept",
+			state:        savedState(ci, chat),
+			observations: []Observation{{Repository: "acme/web", Hook: hook(1, ci.URL)}},
+			scanned:      map[string]bool{"acme/web": true},
+			urlFilter:    "CI.example",
+			kept:         []int{1, 2},
+		},
+		{
+			name:         "hooks matching the URL filter are removed",
+			state:        savedState(ci, chat),
+			observations: []Observation{{Repository: "acme/web", Hook: hook(2, chat.URL)}},
+			scanned:      map[string]bool{"acme/web": true},
+			urlFilter:    "ci.example",
+			removed:      []int{1},
+			kept:         []int{2},
+		},
+		{
+			name:         "hooks without a known URL are removed regardless of the filter",
+			state:        savedState(ci, Hook{Repository: "acme/web", ID: 5}),
+			observations: []Observation{{Repository: "acme/web", Hook: hook(1, ci.URL)}},
+			scanned:      map[string]bool{"acme/web": true},
+			urlFilter:    "ci.example",
+			removed:      []int{5},
+			kept:         []int{1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := tt.state.Update(tt.observations, tt.scanned, tt.urlFilter)
+
+			if got := deliveryIDs(changes.NewDeliveries); !equalIDs(got, tt.newDeliveries) {
+				t.Errorf("new deliveries = %v, want %v", got, tt.newDeliveries)
+			}
+			if got := deliveryIDs(changes.NewFailures); !equalIDs(got, tt.newFailures) {
+				t.Errorf("new failures = %v, want %v", got, tt.newFailures)
+			}
+			if got := hookIDs(changes.Recovered); !equalIDs(got, tt.recovered) {
+				t.Errorf("recovered = %v, want %v", got, tt.recovered)
+			}
+			if got := hookIDs(changes.Added); !equalIDs(got, tt.added) {
+				t.Errorf("added = %v, want %v", got, tt.added)
+			}
+			if got := hookIDs(changes.Removed); !equalIDs(got, tt.removed) {
+				t.Errorf("removed = %v, want %v", got, tt.removed)
+			}
+			var kept []int
+			for _, h := range tt.state.Hooks {
+				kept = append(kept, h.ID)
+			}
+			sort.Ints(kept)
+			if !equalIDs(kept, tt.kept) {
+				t.Errorf("state keeps hooks %v, want %v", kept, tt.kept)
+			}
+		})
+	}
+}
+
+func TestStateUpdateAdvancesLastDelivery(t *testing.T) {
+	s := savedState(Hook{Repository: "acme/web", ID: 1, LastDeliveryID: 10})
+	s.Update([]Observation{
+		{Repository: "acme/web", Hook: hook(1, "https://ci.example.com/hook"), Deliveries: []github.Delivery{delivery(12, 500), delivery(11, 200)}},
+	}, map[string]bool{"acme/web": true}, "")
+
+	h := s.Hooks[hookKey("acme/web", 1)]
+	if h.LastDeliveryID != 12 || !h.Failing || h.URL != "https://ci.example.com/hook" {
+		t.Errorf("hook state = %+v, want last delivery 12, failing, with the observed URL", h)
+	}
+}
@@ -0,0 +1,136 @@
+package collect
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Version is the format version of state files written by this release
+// Version 1 only kept the newest delivery ID of every hook
+const Version = 2
+
+// State remembers every hook seen by the last run, so the next run only reports what changed
+type State struct {
+	Version   int             `json:"version"`
+	UpdatedAt time.Time       `json:"updated_at"`
+	Hooks     map[string]Hook `json:"hooks"` // By hook key OWNER/REPO#HOOK_ID
+}
+
+// Hook is the state of a hook after the last run
+type Hook struct {
+	Repository     string `json:"repository"`
+	ID             int    `json:"id"`
+	URL            string `json:"url,omitempty"`
+	LastDeliveryID int    `json:"last_delivery_id,omitempty"` // Newest delivery seen
+	Failing        bool   `json:"failing,omitempty"`          // The newest delivery failed
+}
+
+// Load reads the state file at path, a missing file yields an empty state
+func Load(path string) (*State, error) {
+	s := &State{Version: Version, Hooks: map[string]Hook{}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state: %w", err)
+	}
+
+	var file struct {
+		Version   int             `json:"version"`
+		UpdatedAt time.Time       `json:"updated_at"`
+		Hooks     json.RawMessage `json:"hooks"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse state %s: %w", path, err)
+	}
+	s.UpdatedAt = file.UpdatedAt
+
+	switch file.Version {
+	case 1:
+		var lastSeen map[string]int
+		if err := json.Unmarshal(file.Hooks, &lastSeen); err != nil {
+			return nil, fmt.Errorf("failed to parse state %s: %w", path, err)
+		}
+		for key, id := range lastSeen {
+			repo, hookID, ok := parseHookKey(key)
+			if !ok {
+				return nil, fmt.Errorf("failed to parse state %s: invalid hook %q", path, key)
+			}
+			s.Hooks[key] = Hook{Repository: repo, ID: hookID, LastDeliveryID: id}
+		}
+	case Version:
+		if err := json.Unmarshal(file.Hooks, &s.Hooks); err != nil {
+			return nil, fmt.Errorf("failed to parse state %s: %w", path, err)
+		}
+		if s.Hooks == nil {
+			s.Hooks = map[string]Hook{}
+		}
+	default:
+		return nil, fmt.Errorf("unsupported state version %d (supported: 1 to %d)", file.Version, Version)
+	}
+	return s, nil
+}
+
+// IsNew reports whether the state was never saved, i.e. this is the first run
+func (s *State) IsNew() bool {
+	return s.UpdatedAt.IsZero()
+}
+
+// Save writes the state to path
+// The file is replaced atomically, so an interrupted run never leaves a truncated state behind
+func (s *State) Save(path string) error {
+	s.Version = Version
+	s.UpdatedAt = time.Now().UTC()
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write state: %w", err)
+	}
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write state: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write state: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write state: %w", err)
+	}
+	return nil
+}
+
+// hookKey identifies a hook across repositories
+func hookKey(repo string, hookID int) string {
+	return fmt.Sprintf("%s#%d", repo, hookID)
+}
+
+// parseHookKey splits a hook key into repository and hook ID
+func parseHookKey(key string) (string, int, bool) {
+	i := strings.LastIndex(key, "#")
+	if i < 0 {
+		return "", 0, false
+	}
+	id, err := strconv.Atoi(key[i+1:])
+	if err != nil {
+		return "", 0, false
+	}
+	return key[:i], id, true
+}
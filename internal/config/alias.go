@@ -0,0 +1,118 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// AliasPrefix marks the first argument as the name of an alias of the config file, e.g. @failed-week
+const AliasPrefix = "@"
+
+// ExpandAlias replaces an alias given as the first argument with its arguments
+// Arguments following the alias are appended, so they extend or override the alias's flags
+// Other arguments are returned unchanged
+func (f *File) ExpandAlias(args []string) ([]string, error) {
+	if len(args) == 0 || !strings.HasPrefix(args[0], AliasPrefix) {
+		return args, nil
+	}
+	name := strings.TrimPrefix(args[0], AliasPrefix)
+	value, ok := f.Aliases[name]
+	if !ok {
+		if len(f.Aliases) == 0 {
+			return nil, fmt.Errorf("unknown alias %s%s, the config file defines no aliases", AliasPrefix, name)
+		}
+		return nil, fmt.Errorf("unknown alias %s%s, defined aliases: %s", AliasPrefix, name, strings.Join(f.AliasNames(), ", "))
+	}
+	expanded, err := SplitArgs(value)
+	if err != nil {
+		return nil, fmt.Errorf("alias %s%s: %w", AliasPrefix, name, err)
+	}
+	return append(expanded, args[1:]...), nil
+}
+
+// AliasNames returns the names of all aliases in alphabetical order
+func (f *File) AliasNames() []string {
+	names := make([]string, 0, len(f.Aliases))
+	for name := range f.Aliases {
+		names = append(names, AliasPrefix+name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// validateAliases checks that alias names are single words and their arguments can be split
+// Aliases cannot refer to other aliases
+func (f *File) validateAliases() error {
+	for name, value := range f.Aliases {
+		if name == "" || strings.HasPrefix(name, AliasPrefix) || strings.IndexFunc(name, unicode.IsSpace) >= 0 {
+			return fmt.Errorf("aliases: invalid name %q, use a single word without %s", name, AliasPrefix)
+		}
+		args, err := SplitArgs(value)
+		if err != nil {
+			return fmt.Errorf("alias %q: %w", name, err)
+		}
+		if len(args) == 0 {
+			return fmt.Errorf("alias %q: no arguments", name)
+		}
+		if strings.HasPrefix(args[0], AliasPrefix) {
+			return fmt.Errorf("alias %q: aliases cannot refer to other aliases", name)
+		}
+	}
+	return nil
+}
+
+// SplitArgs splits a command line into arguments like a POSIX shell, without any expansion
+// Single quotes keep everything literally, double quotes and backslashes escape spaces and quotes
+func SplitArgs(s string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inArg := false
+	var quote rune
+	escaped := false
+	for _, r := range s {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\\':
+			escaped = true
+			inArg = true
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inArg = true
+		case unicode.IsSpace(r):
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if escaped {
+		return nil, fmt.Errorf("trailing backslash")
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
@@ -0,0 +1,247 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// File holds the settings of the configuration file
+type File struct {
+	App            App                `yaml:"app"`
+	DefaultProfile string             `yaml:"default_profile"` // Profile used without --profile (optional)
+	Profiles       map[string]Profile `yaml:"profiles"`
+	SLOs           []SLO              `yaml:"slos"`
+	Retention      string             `yaml:"retention"` // Delete recorded deliveries older than this from the history, e.g. "90d" (optional)
+	Redact         Redact             `yaml:"redact"`
+	RecordHistory  bool               `yaml:"record_history"` // Record the deliveries of every run in the history database like --record (optional)
+	Theme          Theme              `yaml:"theme"`
+	Aliases        map[string]string  `yaml:"aliases"` // Arguments invoked as @name, e.g. failed: "--failed --sort=timestamp:desc"
+}
+
+// Theme customizes the colors of table output, unset values keep the colors of the preset
+type Theme struct {
+	Preset       string   `yaml:"preset"`        // Built-in theme: default, colorblind or none (default: default)
+	Success      string   `yaml:"success"`       // Color names, e.g. "bold green", or SGR parameters, e.g. "38;5;33"
+	Warning      string   `yaml:"warning"`       // Same format as success
+	Error        string   `yaml:"error"`         // Same format as success
+	WarningCodes []string `yaml:"warning_codes"` // Status codes shown in the warning color, e.g. 3xx or 429 (default: 3xx)
+	ErrorCodes   []string `yaml:"error_codes"`   // Status codes shown in the error color, 0 is no response (default: 0, 4xx, 5xx)
+}
+
+// Redact lists sensitive payload values replaced before payloads are printed or stored
+type Redact struct {
+	Fields   []string `yaml:"fields"`   // Dot-separated field paths, e.g. sender.email, * matches any key
+	Patterns []string `yaml:"patterns"` // Regular expressions matched against every string value
+}
+
+// Profile bundles the host, credentials and default organization of a GitHub instance
+type Profile struct {
+	Host      string `yaml:"host"`       // GitHub host, e.g. github.example.com (default: gh CLI default host)
+	CABundle  string `yaml:"ca_bundle"`  // Additional trusted CA certificates in PEM format
+	Org       string `yaml:"org"`        // Organization used if neither --org nor --repo is given
+	TokenEnv  string `yaml:"token_env"`  // Read the token from this environment variable
+	TokenFile string `yaml:"token_file"` // Read the token from this file
+	App       App    `yaml:"app"`        // Authenticate as a GitHub App instead of with a token
+}
+
+// App holds GitHub App credentials used instead of the gh CLI login
+type App struct {
+	ID             int64  `yaml:"id"`
+	PrivateKey     string `yaml:"private_key"`     // Path to the private key in PEM format
+	InstallationID int64  `yaml:"installation_id"` // Looked up for the organization or repository if not set
+}
+
+// SLO declares a service level objective for the hooks matching its selector
+type SLO struct {
+	Name          string `yaml:"name"`
+	Repository    string `yaml:"repository"`     // Only hooks of this repository (optional)
+	HookID        int    `yaml:"hook_id"`        // Only this hook (optional)
+	URL           string `yaml:"url"`            // Only hooks whose target URL contains this pattern (optional)
+	Window        string `yaml:"window"`         // Evaluation window, e.g. "7d" (default: 7d)
+	Availability  string `yaml:"availability"`   // Target fraction of successful deliveries, e.g. "99.5%"
+	Latency       string `yaml:"latency"`        // Latency threshold, e.g. "2s" (optional)
+	LatencyTarget string `yaml:"latency_target"` // Target fraction of deliveries within the latency threshold (default: 95%)
+}
+
+// DefaultConfigPath returns the default location of the configuration file
+func DefaultConfigPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine config directory: %w", err)
+	}
+	return filepath.Join(dir, "gh-hookmon", "config.yml"), nil
+}
+
+// LoadFile reads and validates the configuration file at path
+// A missing file yields an empty configuration unless required is set
+func LoadFile(path string, required bool) (*File, error) {
+	f := &File{}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && !required {
+		return f, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, f); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	if err := f.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	return f, nil
+}
+
+// Validate checks that the configuration file is valid
+func (f *File) Validate() error {
+	if (f.App.ID != 0) != (f.App.PrivateKey != "") {
+		return fmt.Errorf("app: id and private_key must be set together")
+	}
+
+	if f.DefaultProfile != "" {
+		if _, ok := f.Profiles[f.DefaultProfile]; !ok {
+			return fmt.Errorf("default_profile: unknown profile %q", f.DefaultProfile)
+		}
+	}
+	for name, profile := range f.Profiles {
+		if profile.TokenEnv != "" && profile.TokenFile != "" {
+			return fmt.Errorf("profile %q: token_env and token_file are mutually exclusive", name)
+		}
+		if (profile.App.ID != 0) != (profile.App.PrivateKey != "") {
+			return fmt.Errorf("profile %q: app: id and private_key must be set together", name)
+		}
+		if profile.App.ID != 0 && (profile.TokenEnv != "" || profile.TokenFile != "") {
+			return fmt.Errorf("profile %q: app cannot be combined with token_env or token_file", name)
+		}
+	}
+
+	if f.Retention != "" {
+		if _, err := ParseDuration(f.Retention); err != nil {
+			return fmt.Errorf("retention: %w", err)
+		}
+	}
+
+	for i, field := range f.Redact.Fields {
+		for _, key := range strings.Split(field, ".") {
+			if key == "" {
+				return fmt.Errorf("redact: fields[%d]: invalid field path %q", i, field)
+			}
+		}
+	}
+	for i, pattern := range f.Redact.Patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("redact: patterns[%d]: %w", i, err)
+		}
+	}
+
+	if err := f.validateAliases(); err != nil {
+		return err
+	}
+
+	names := make(map[string]bool)
+	for i, slo := range f.SLOs {
+		if slo.Name == "" {
+			return fmt.Errorf("slos[%d]: name is required", i)
+		}
+		if names[slo.Name] {
+			return fmt.Errorf("slos[%d]: duplicate name %q", i, slo.Name)
+		}
+		names[slo.Name] = true
+
+		if slo.Availability == "" && slo.Latency == "" {
+			return fmt.Errorf("slo %q: availability or latency is required", slo.Name)
+		}
+		if _, err := slo.GetWindow(); err != nil {
+			return fmt.Errorf("slo %q: window: %w", slo.Name, err)
+		}
+		if _, err := slo.GetAvailability(); err != nil {
+			return fmt.Errorf("slo %q: availability: %w", slo.Name, err)
+		}
+		if _, _, err := slo.GetLatency(); err != nil {
+			return fmt.Errorf("slo %q: %w", slo.Name, err)
+		}
+	}
+	return nil
+}
+
+// GetProfile returns the named profile, or the default profile if name is empty
+// Returns nil if no name is given and there is no default profile
+func (f *File) GetProfile(name string) (*Profile, error) {
+	if name == "" {
+		name = f.DefaultProfile
+	}
+	if name == "" {
+		return nil, nil
+	}
+	profile, ok := f.Profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown profile %q", name)
+	}
+	return &profile, nil
+}
+
+// Token returns the token of the profile's token source, or an empty string if none is configured
+func (p *Profile) Token() (string, error) {
+	switch {
+	case p.TokenEnv != "":
+		token := os.Getenv(p.TokenEnv)
+		if token == "" {
+			return "", fmt.Errorf("environment variable %s is not set", p.TokenEnv)
+		}
+		return token, nil
+	case p.TokenFile != "":
+		data, err := os.ReadFile(p.TokenFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read token file: %w", err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+	return "", nil
+}
+
+// GetWindow returns the evaluation window of the SLO
+func (s SLO) GetWindow() (time.Duration, error) {
+	if s.Window == "" {
+		return 7 * 24 * time.Hour, nil
+	}
+	return ParseDuration(s.Window)
+}
+
+// GetAvailability returns the availability target as a fraction (0 if not set)
+func (s SLO) GetAvailability() (float64, error) {
+	if s.Availability == "" {
+		return 0, nil
+	}
+	return ParsePercentage(s.Availability)
+}
+
+// GetLatency returns the latency threshold and the target fraction of deliveries within it
+// Returns a zero threshold if no latency objective is set
+func (s SLO) GetLatency() (threshold time.Duration, target float64, err error) {
+	if s.Latency == "" {
+		return 0, 0, nil
+	}
+	threshold, err = ParseDuration(s.Latency)
+	if err != nil {
+		return 0, 0, fmt.Errorf("latency: %w", err)
+	}
+	target = 0.95
+	if s.LatencyTarget != "" {
+		target, err = ParsePercentage(s.LatencyTarget)
+		if err != nil {
+			return 0, 0, fmt.Errorf("latency_target: %w", err)
+		}
+	}
+	return threshold, target, nil
+}
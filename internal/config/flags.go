@@ -2,31 +2,113 @@ package config
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 )
 
 // Config holds the application configuration
 type Config struct {
-	Org        string
-	Repo       string
-	Filter     string
-	Since      *time.Time
-	Until      *time.Time
-	JSONOutput bool
-	Failed     bool   // Filter for failed deliveries only
-	LastFailed bool   // Filter repos where last delivery failed
-	Head       int    // Limit to N most recent deliveries per repo (0 = no limit)
-	SortBy     string // Sort field and order: "field:order" (e.g., "repository:asc", "timestamp:desc")
-	Verbose    bool   // Enable verbose output
+	Org             string
+	Repo            string
+	OrgHooks        string   // Organization whose hooks are included for --repo, set when --org is combined with --repo
+	Team            string   // Team ORG/TEAM whose repositories are scanned instead of all repositories of the organization
+	IncludeForks    bool     // Also scan forks of the organization, which are skipped by default
+	Language        string   // Comma-separated primary languages of the organization repositories to scan, e.g. "Go,Rust"
+	ActiveSince     string   // Skip organization repositories without a push within this duration, e.g. "90d" (empty = scan all)
+	ReposList       string   // Comma-separated repositories to scan, "-" reads them from stdin one OWNER/REPO per line
+	ReposFile       string   // File listing the repositories to scan, one OWNER/REPO per line
+	Repos           []string // Repositories read from ReposList or ReposFile
+	Filter          string
+	DeliveryID      string // Delivery ID filter: explicit IDs ("111,222"), comparison (">=123") or range ("100-200")
+	Installation    string // Installation filter: "none", "any" or explicit installation IDs
+	ErrorClass      string // Error class filter: comma-separated classes, e.g. "dns,tls"
+	Ref             string // Git ref filter on the request payload, e.g. "refs/heads/main"
+	Branch          string // Branch filter on the request payload, shorthand for Ref "refs/heads/<branch>"
+	Sender          string // Sender filter on the request payload: comma-separated logins, e.g. "dependabot[bot]"
+	ExcludeSender   string // Comma-separated sender logins whose deliveries are dropped
+	PullRequest     string // Pull request filter on the request payload: comma-separated numbers
+	Issue           string // Issue filter on the request payload: comma-separated numbers
+	Since           *time.Time
+	Until           *time.Time
+	JSONOutput      bool
+	IncludeWarnings bool          // Wrap JSON output in an envelope with warnings and errors
+	FormatVersion   int           // Version of the JSON output (0 = latest)
+	Flatten         bool          // Emit flat objects with snake_case keys, including the repository and hook
+	Output          string        // Output format of delivery lists, see output.Formats (empty = table, or json if --json is set)
+	Wide            bool          // Show all table columns even if the table is wider than the terminal
+	Compact         bool          // Show status glyphs and codes instead of the status text in tables
+	DurationDelta   bool          // Compare every duration with the rolling median of its hook
+	SummaryBy       string        // Replace the delivery listing with one summary row per "repository" or "hook" (empty = list deliveries)
+	TimeFormat      string        // Timestamp format of tables: "rfc3339" or "relative" (empty = rfc3339)
+	Failed          bool          // Filter for failed deliveries only
+	LastFailed      bool          // Filter repos where last delivery failed
+	Head            int           // Limit to N most recent deliveries per repo (0 = no limit)
+	Limit           int           // Deliveries fetched per hook, pages of 100 are followed beyond the first
+	LatestPerHook   bool          // Only show the most recent delivery of each hook
+	ResponseExcerpt int           // Bytes of the response body kept with failed deliveries (0 = none)
+	ValidatePayload bool          // Validate request payloads against the webhook event schemas
+	PayloadSchema   string        // File or URL of the webhook event schemas (empty = octokit/webhooks schemas)
+	Details         bool          // Fetch the details of every listed delivery for its target URL, payload size and response summary
+	Extract         string        // Comma-separated dot paths of payload fields shown as extra columns, e.g. "pull_request.head.sha"
+	SortBy          string        // Sort field and order: "field:order" (e.g., "repository:asc", "timestamp:desc")
+	RefreshRepos    bool          // Bypass the cached organization repository list
+	Refresh         bool          // Bypass the cached repository list and the repositories remembered without hooks
+	HooklessTTL     time.Duration // How long repositories without hooks are skipped by delivery scans (0 = never skipped)
+	Strict          bool          // Fail instead of warning when a repository, hook or detail fetch fails
+	FailFast        bool          // Abort on the first failure and cancel remaining workers
+	Profile         string        // Name of the config file profile to use
+	Host            string        // GitHub host from the profile (empty = gh CLI default host)
+	CABundle        string        // Additional trusted CA certificates in PEM format
+	Token           string        // Explicit API token, bypasses the gh CLI login
+	AppID           int64         // GitHub App ID, authenticates as an app installation instead of the gh CLI login
+	PrivateKey      string        // Path to the GitHub App private key
+	AppInstallation int64         // GitHub App installation ID (looked up if not set)
+	CacheTTL        time.Duration // How long cached API results are reused
+	NoCache         bool          // Neither read nor write the on-disk cache
+	Offline         bool          // Answer from cached data only, without any API request
+	HistoryDB       string        // Path of the history database (empty = default location)
+	RecordHistory   bool          // Record fetched deliveries in the history database, collect always records
+	Retention       string        // Delete recorded deliveries older than this, e.g. "90d" (empty = keep all)
+	RequestTimeout  time.Duration // Time limit of each API request (0 = no limit)
+	Deadline        time.Duration // Time limit of the whole run, results are partial when it is exceeded (0 = no limit)
+	RepoTimeout     time.Duration // Time limit of scanning a single repository including retries (0 = no limit)
+	RepoRetries     int           // Number of times a repository failing with a transient error is scanned again
+	ShowSkipped     bool          // List the repositories skipped for missing permissions after a scan
+	OnForbidden     string        // Handling of repositories denied with 403 or 404: "skip", "report" or "fail" (empty = skip)
+	NoRedact        bool          // Show signatures and credentials in output instead of redacting them
+	OtelEndpoint    string        // OTLP/HTTP endpoint spans of the run are exported to (empty = no tracing)
+	Verbose         bool          // Enable verbose output
+}
+
+// ValidateCache checks the cache settings, which also apply to commands without --org or --repo
+func (c *Config) ValidateCache() error {
+	if c.CacheTTL <= 0 {
+		return fmt.Errorf("--cache-ttl must be positive, use --no-cache to bypass the cache")
+	}
+	if c.HooklessTTL < 0 {
+		return fmt.Errorf("--hookless-ttl must not be negative")
+	}
+	return nil
 }
 
 // Validate checks that the configuration is valid
 func (c *Config) Validate() error {
-	// Exactly one of --org or --repo must be set
-	if c.Org == "" && c.Repo == "" {
-		return fmt.Errorf("either --org or --repo must be specified")
+	// One of --org, --team, --repo, --repos or --repos-file must be set, --team implies its organization
+	if c.Org == "" && c.Repo == "" && c.ReposList == "" && c.ReposFile == "" {
+		return fmt.Errorf("either --org, --team, --repo, --repos or --repos-file must be specified")
+	}
+	if c.ReposList != "" && len(c.Repos) == 0 {
+		return fmt.Errorf("--repos does not list any repository")
 	}
+	if c.ReposFile != "" && len(c.Repos) == 0 {
+		return fmt.Errorf("--repos-file %s does not list any repository", c.ReposFile)
+	}
+	return c.ValidateOptional()
+}
+
+// ValidateOptional checks the configuration of commands for which --org and --repo are optional
+func (c *Config) ValidateOptional() error {
 	if c.Org != "" && c.Repo != "" {
 		return fmt.Errorf("cannot specify both --org and --repo")
 	}
@@ -39,6 +121,34 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	if c.ReposList != "" && c.ReposFile != "" {
+		return fmt.Errorf("cannot specify both --repos and --repos-file")
+	}
+	if (c.ReposList != "" || c.ReposFile != "") && (c.Org != "" || c.Repo != "") {
+		return fmt.Errorf("cannot specify --repos or --repos-file together with --org, --team or --repo")
+	}
+
+	if c.Team != "" {
+		org, team := c.GetTeam()
+		if org == "" || team == "" || strings.Contains(team, "/") {
+			return fmt.Errorf("--team must be in format ORG/TEAM")
+		}
+		if c.Repo != "" {
+			return fmt.Errorf("cannot specify both --team and --repo")
+		}
+		if !strings.EqualFold(org, c.Org) {
+			return fmt.Errorf("--team %s does not belong to --org %s", c.Team, c.Org)
+		}
+	}
+
+	// Organization hooks only receive the events of the organization's own repositories
+	if c.OrgHooks != "" {
+		owner, _, _ := strings.Cut(c.Repo, "/")
+		if !strings.EqualFold(owner, c.OrgHooks) {
+			return fmt.Errorf("--repo %s does not belong to --org %s", c.Repo, c.OrgHooks)
+		}
+	}
+
 	// Validate date range
 	if c.Since != nil && c.Until != nil {
 		if c.Since.After(*c.Until) {
@@ -50,12 +160,116 @@ func (c *Config) Validate() error {
 	if c.Head < 0 {
 		return fmt.Errorf("--head must be a non-negative integer")
 	}
+	if c.Limit < 1 {
+		return fmt.Errorf("--limit must be a positive integer")
+	}
+
+	// Validate summary mode
+	if c.SummaryBy != "" {
+		if c.SummaryBy != "repository" && c.SummaryBy != "hook" {
+			return fmt.Errorf("--summary-by must be one of: repository, hook")
+		}
+		if format := c.GetOutputFormat(); format != "table" && format != "json" {
+			return fmt.Errorf("--summary-by only supports table and JSON output, not --output=%s", format)
+		}
+		if c.IncludeWarnings {
+			return fmt.Errorf("cannot specify both --summary-by and --include-warnings")
+		}
+		if c.Flatten {
+			return fmt.Errorf("cannot specify both --summary-by and --flatten, summary rows are flat already")
+		}
+	}
+
+	// Validate time format
+	if c.TimeFormat != "" && c.TimeFormat != "rfc3339" && c.TimeFormat != "relative" {
+		return fmt.Errorf("--time-format must be one of: rfc3339, relative")
+	}
+
+	for _, path := range c.GetExtractPaths() {
+		if strings.HasPrefix(path, ".") || strings.HasSuffix(path, ".") || strings.Contains(path, "..") {
+			return fmt.Errorf("--extract: invalid field path %q", path)
+		}
+	}
+
+	if c.Ref != "" && c.Branch != "" {
+		return fmt.Errorf("cannot specify both --ref and --branch")
+	}
+
+	if c.LatestPerHook && c.Head > 0 {
+		return fmt.Errorf("cannot specify both --latest-per-hook and --head")
+	}
+
+	if (c.AppID != 0) != (c.PrivateKey != "") {
+		return fmt.Errorf("--app-id and --private-key must be specified together")
+	}
+	if c.Token != "" && c.AppID != 0 {
+		return fmt.Errorf("cannot specify both --token and --app-id")
+	}
+
+	if err := c.ValidateCache(); err != nil {
+		return err
+	}
+	if _, err := c.GetRetention(); err != nil {
+		return fmt.Errorf("--retention: %w", err)
+	}
+	if _, err := c.GetActiveSince(); err != nil {
+		return fmt.Errorf("--active-since: %w", err)
+	}
+
+	if c.Offline && c.NoCache {
+		return fmt.Errorf("cannot specify both --offline and --no-cache")
+	}
+	if c.Offline && c.RefreshRepos {
+		return fmt.Errorf("cannot specify both --offline and --refresh-repos")
+	}
+	if c.Offline && c.Refresh {
+		return fmt.Errorf("cannot specify both --offline and --refresh")
+	}
+
+	if c.RequestTimeout < 0 {
+		return fmt.Errorf("--request-timeout must not be negative")
+	}
+	if c.Deadline < 0 {
+		return fmt.Errorf("--deadline must not be negative")
+	}
+	if c.RepoTimeout < 0 {
+		return fmt.Errorf("--repo-timeout must not be negative")
+	}
+	if c.RepoRetries < 0 {
+		return fmt.Errorf("--repo-retries must not be negative")
+	}
+	if c.OnForbidden != "" && c.OnForbidden != "skip" && c.OnForbidden != "report" && c.OnForbidden != "fail" {
+		return fmt.Errorf("--on-forbidden must be one of: skip, report, fail")
+	}
+
+	if c.ResponseExcerpt < 0 {
+		return fmt.Errorf("--response-excerpt must not be negative")
+	}
+	if c.ValidatePayload && c.Offline {
+		return fmt.Errorf("cannot specify both --validate-payload and --offline, payloads are not cached")
+	}
 
 	// Validate --failed and --last-failed are mutually exclusive
 	if c.Failed && c.LastFailed {
 		return fmt.Errorf("cannot specify both --failed and --last-failed")
 	}
 
+	// Validate output flag, format names are checked against the formats of the output package
+	if c.JSONOutput && c.Output != "" && c.Output != "json" {
+		return fmt.Errorf("cannot specify both --json and --output=%s", c.Output)
+	}
+
+	// Validate --include-warnings is only used with JSON output
+	if c.IncludeWarnings && c.GetOutputFormat() != "json" {
+		return fmt.Errorf("--include-warnings requires JSON output (--output=json)")
+	}
+	if c.FormatVersion != 0 && c.GetOutputFormat() != "json" {
+		return fmt.Errorf("--format-version requires JSON output (--output=json)")
+	}
+	if format := c.GetOutputFormat(); c.Flatten && format != "json" && format != "ndjson" && format != "yaml" {
+		return fmt.Errorf("--flatten requires JSON, NDJSON or YAML output, not --output=%s", format)
+	}
+
 	// Validate sort flag
 	if c.SortBy != "" {
 		parts := strings.Split(c.SortBy, ":")
@@ -140,3 +354,99 @@ func (c *Config) GetSortConfig() (field string, ascending bool) {
 		return "timestamp", false
 	}
 }
+
+// GetTeam returns the organization and slug of --team, e.g. "myorg" and "platform" for "myorg/platform"
+func (c *Config) GetTeam() (org, team string) {
+	org, team, _ = strings.Cut(c.Team, "/")
+	return org, team
+}
+
+// GetLanguages returns the set of lower-case languages of --language, nil if all languages are scanned
+func (c *Config) GetLanguages() map[string]bool {
+	var languages map[string]bool
+	for _, language := range strings.Split(c.Language, ",") {
+		if language = strings.ToLower(strings.TrimSpace(language)); language != "" {
+			if languages == nil {
+				languages = make(map[string]bool)
+			}
+			languages[language] = true
+		}
+	}
+	return languages
+}
+
+// GetExtractPaths returns the payload field paths of --extract in the given order, without duplicates
+func (c *Config) GetExtractPaths() []string {
+	var paths []string
+	seen := make(map[string]bool)
+	for _, path := range strings.Split(c.Extract, ",") {
+		path = strings.TrimSpace(path)
+		if path != "" && !seen[path] {
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// GetOutputFormat returns the effective output format
+// --json is kept as a deprecated alias of --output=json
+func (c *Config) GetOutputFormat() string {
+	if c.Output != "" {
+		return c.Output
+	}
+	if c.JSONOutput {
+		return "json"
+	}
+	return "table"
+}
+
+// GetRetention returns how long recorded deliveries are kept, 0 if they are kept forever
+func (c *Config) GetRetention() (time.Duration, error) {
+	if c.Retention == "" {
+		return 0, nil
+	}
+	return ParseDuration(c.Retention)
+}
+
+// GetActiveSince returns how recently a repository must have been pushed to be scanned, 0 if all repositories are scanned
+func (c *Config) GetActiveSince() (time.Duration, error) {
+	if c.ActiveSince == "" {
+		return 0, nil
+	}
+	return ParseDuration(c.ActiveSince)
+}
+
+// ParseDuration parses a duration like "24h", "30m", "7d" or "2w"
+// In addition to Go duration units, "d" (days) and "w" (weeks) are supported
+func ParseDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	for suffix, unit := range map[string]time.Duration{
+		"d": 24 * time.Hour,
+		"w": 7 * 24 * time.Hour,
+	} {
+		if !strings.HasSuffix(s, suffix) {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimSuffix(s, suffix))
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		return time.Duration(n) * unit, nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid duration %q (expected e.g. 30m, 24h, 7d or 2w)", s)
+	}
+	return d, nil
+}
+
+// ParsePercentage parses a percentage like "5%" or "2.5" into a fraction (0.05, 0.025)
+func ParsePercentage(s string) (float64, error) {
+	value, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(s), "%"), 64)
+	if err != nil || value < 0 || value > 100 {
+		return 0, fmt.Errorf("invalid percentage %q (expected a value between 0%% and 100%%)", s)
+	}
+	return value / 100, nil
+}
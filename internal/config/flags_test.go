@@ -0,0 +1,39 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{in: "30m", want: 30 * time.Minute},
+		{in: "24h", want: 24 * time.Hour},
+		{in: "1h30m", want: 90 * time.Minute},
+		{in: "7d", want: 7 * 24 * time.Hour},
+		{in: " 2w ", want: 14 * 24 * time.Hour},
+		{in: "0d", want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseDuration(tt.in)
+			if err != nil {
+				t.Fatalf("ParseDuration(%q) returned error: %v", tt.in, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseDuration(%q) = %s, want %s", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDurationInvalid(t *testing.T) {
+	for _, in := range []string{"", "d", "1.5d", "-1d", "-2h", "7 days", "w2", "10"} {
+		if _, err := ParseDuration(in); err == nil {
+			t.Errorf("ParseDuration(%q) returned no error", in)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// LoadRepoList reads the repository list of --repos-file
+func LoadRepoList(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read repository list: %w", err)
+	}
+	defer file.Close()
+
+	repos, err := ReadRepoList(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return repos, nil
+}
+
+// ReadRepoList reads a repository list with one OWNER/REPO per line
+// Blank lines and comments starting with # are ignored, repeated repositories are only listed once
+func ReadRepoList(r io.Reader) ([]string, error) {
+	var repos []string
+	seen := make(map[string]bool)
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text, _, _ := strings.Cut(scanner.Text(), "#")
+		repo := strings.TrimSpace(text)
+		if repo == "" {
+			continue
+		}
+		if !isRepoName(repo) {
+			return nil, fmt.Errorf("line %d: %q is not in format OWNER/REPO", line, repo)
+		}
+		repos = appendRepo(repos, seen, repo)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read repository list: %w", err)
+	}
+
+	return repos, nil
+}
+
+// ParseRepoList parses a comma-separated repository list, e.g. "myorg/api,myorg/web"
+func ParseRepoList(spec string) ([]string, error) {
+	var repos []string
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(spec, ",") {
+		repo := strings.TrimSpace(part)
+		if repo == "" {
+			continue
+		}
+		if !isRepoName(repo) {
+			return nil, fmt.Errorf("%q is not in format OWNER/REPO", repo)
+		}
+		repos = appendRepo(repos, seen, repo)
+	}
+	return repos, nil
+}
+
+// isRepoName reports whether repo is in format OWNER/REPO
+func isRepoName(repo string) bool {
+	owner, name, ok := strings.Cut(repo, "/")
+	return ok && owner != "" && name != "" && !strings.Contains(name, "/") && !strings.ContainsAny(repo, " \t")
+}
+
+// appendRepo appends repo unless it was seen before, GitHub repository names are case-insensitive
+func appendRepo(repos []string, seen map[string]bool, repo string) []string {
+	key := strings.ToLower(repo)
+	if seen[key] {
+		return repos
+	}
+	seen[key] = true
+	return append(repos, repo)
+}
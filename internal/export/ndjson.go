@@ -0,0 +1,94 @@
+package export
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ohader/gh-hookmon/internal/archive"
+	"github.com/ohader/gh-hookmon/internal/filter"
+	"github.com/ohader/gh-hookmon/internal/snapshot"
+)
+
+// BigQuerySchema is the published BigQuery table schema of NDJSON exports
+//
+//go:embed bigquery-schema.json
+var BigQuerySchema []byte
+
+// row is a delivery of an NDJSON export, denormalized with its hook so it loads into a single table
+// The fields match BigQuerySchema
+type row struct {
+	DeliveryID      int       `json:"delivery_id"`
+	GUID            string    `json:"guid"`
+	Repository      string    `json:"repository"`
+	HookID          int       `json:"hook_id"`
+	HookURL         *string   `json:"hook_url"`
+	HookActive      bool      `json:"hook_active"`
+	DeliveredAt     time.Time `json:"delivered_at"`
+	Redelivery      bool      `json:"redelivery"`
+	DurationSeconds float64   `json:"duration_seconds"`
+	Status          *string   `json:"status"`
+	StatusCode      int       `json:"status_code"`
+	Failed          bool      `json:"failed"`
+	ErrorClass      *string   `json:"error_class"`
+	Event           string    `json:"event"`
+	Action          *string   `json:"action"`
+	InstallationID  *int      `json:"installation_id"`
+	ExportedAt      time.Time `json:"exported_at"`
+}
+
+// WriteNDJSON writes one JSON object per delivery to path, gzip-compressed if path ends with .gz
+// Returns the number of rows written
+func WriteNDJSON(snap *snapshot.Snapshot, path string) (int, error) {
+	w, err := archive.Create(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create export: %w", err)
+	}
+
+	encoder := json.NewEncoder(w)
+	rows := 0
+	for _, r := range snap.Repositories {
+		for _, h := range r.Hooks {
+			for _, d := range h.Deliveries {
+				err := encoder.Encode(row{
+					DeliveryID:      d.ID,
+					GUID:            d.GUID,
+					Repository:      r.Name,
+					HookID:          h.Hook.ID,
+					HookURL:         optional(h.Hook.GetTargetURL()),
+					HookActive:      h.Hook.Active,
+					DeliveredAt:     d.DeliveredAt.UTC(),
+					Redelivery:      d.Redelivery,
+					DurationSeconds: d.Duration,
+					Status:          optional(d.Status),
+					StatusCode:      d.StatusCode,
+					Failed:          filter.IsFailed(d.StatusCode),
+					ErrorClass:      optional(filter.Classify(d.StatusCode, d.Status)),
+					Event:           d.Event,
+					Action:          optional(d.Action),
+					InstallationID:  d.InstallationID,
+					ExportedAt:      snap.CreatedAt.UTC(),
+				})
+				if err != nil {
+					w.Close()
+					return rows, fmt.Errorf("failed to write export: %w", err)
+				}
+				rows++
+			}
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return rows, fmt.Errorf("failed to write export: %w", err)
+	}
+	return rows, nil
+}
+
+// optional returns nil for empty strings, which are exported as null
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,257 @@
+package export
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ohader/gh-hookmon/internal/archive"
+	"github.com/ohader/gh-hookmon/internal/filter"
+	"github.com/ohader/gh-hookmon/internal/snapshot"
+
+	_ "modernc.org/sqlite" // Pure Go SQLite driver, no cgo required
+)
+
+// SQLiteVersion is the schema version of SQLite exports, stored in the metadata table
+// It changes only when existing columns change, not when columns are added
+const SQLiteVersion = 1
+
+// SQLiteSchema is the documented schema of SQLite exports
+// Timestamps are RFC 3339 in UTC, booleans are 0 or 1
+const SQLiteSchema = `CREATE TABLE metadata (
+	key   TEXT PRIMARY KEY, -- format_version, exported_at, target
+	value TEXT NOT NULL
+);
+
+CREATE TABLE repositories (
+	name       TEXT PRIMARY KEY, -- OWNER/REPO
+	archived   INTEGER,          -- NULL if unknown (exports of a single repository)
+	visibility TEXT,             -- public, private or internal, NULL if unknown
+	topics     TEXT NOT NULL,    -- comma-separated
+	scanned    INTEGER NOT NULL  -- 1 if the webhooks of the repository were fetched
+);
+
+CREATE TABLE hooks (
+	repository         TEXT    NOT NULL REFERENCES repositories (name),
+	id                 INTEGER NOT NULL,
+	name               TEXT    NOT NULL, -- web for regular webhooks
+	url                TEXT    NOT NULL, -- target URL
+	content_type       TEXT    NOT NULL, -- json or form
+	active             INTEGER NOT NULL,
+	insecure_ssl       INTEGER NOT NULL,
+	has_secret         INTEGER NOT NULL,
+	events             TEXT    NOT NULL, -- comma-separated subscribed events
+	deliveries_fetched INTEGER NOT NULL, -- 0 if the deliveries of the hook could not be fetched
+	PRIMARY KEY (repository, id)
+);
+
+CREATE TABLE deliveries (
+	id              INTEGER PRIMARY KEY,
+	guid            TEXT    NOT NULL, -- shared by all attempts of a delivery
+	repository      TEXT    NOT NULL,
+	hook_id         INTEGER NOT NULL,
+	delivered_at    TEXT    NOT NULL,
+	redelivery      INTEGER NOT NULL,
+	duration        REAL    NOT NULL, -- seconds
+	status          TEXT    NOT NULL,
+	status_code     INTEGER NOT NULL, -- 0 if the receiver did not respond
+	failed          INTEGER NOT NULL, -- 1 for status code 0, 4xx and 5xx
+	error_class     TEXT,             -- timeout, dns, tls, client or server, NULL for successful deliveries
+	event           TEXT    NOT NULL,
+	action          TEXT    NOT NULL, -- empty for events without actions
+	installation_id INTEGER,          -- GitHub App installation, NULL for classic webhooks
+	url             TEXT    NOT NULL, -- target URL at the time of the export
+	FOREIGN KEY (repository, hook_id) REFERENCES hooks (repository, id)
+);
+
+CREATE INDEX deliveries_hook ON deliveries (repository, hook_id, delivered_at);
+CREATE INDEX deliveries_delivered_at ON deliveries (delivered_at);
+`
+
+// WriteSQLite writes the dataset to a new SQLite database at path, replacing an existing file
+// The database is built next to path and moved into place when complete, paths ending with .gz are gzip-compressed
+func WriteSQLite(snap *snapshot.Snapshot, path string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create export: %w", err)
+	}
+	tmpPath := tmp.Name()
+	tmp.Close()
+	defer os.Remove(tmpPath)
+
+	if err := writeSQLite(snap, tmpPath); err != nil {
+		return err
+	}
+
+	if strings.HasSuffix(path, archive.Extension) {
+		return compressFile(tmpPath, path)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	return nil
+}
+
+// writeSQLite creates the schema in an empty database and inserts the dataset in a single transaction
+func writeSQLite(snap *snapshot.Snapshot, path string) error {
+	db, err := sql.Open("sqlite", "file:"+path)
+	if err != nil {
+		return fmt.Errorf("failed to create export: %w", err)
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(SQLiteSchema); err != nil {
+		return fmt.Errorf("failed to create export schema: %w", err)
+	}
+	if err := insertMetadata(tx, snap); err != nil {
+		return err
+	}
+	if err := insertRepositories(tx, snap); err != nil {
+		return err
+	}
+	if err := insertHooks(tx, snap); err != nil {
+		return err
+	}
+	if err := insertDeliveries(tx, snap); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	return db.Close()
+}
+
+// insertMetadata records the format version, export time and target
+func insertMetadata(tx *sql.Tx, snap *snapshot.Snapshot) error {
+	metadata := [][2]string{
+		{"format_version", fmt.Sprint(SQLiteVersion)},
+		{"exported_at", snap.CreatedAt.UTC().Format(time.RFC3339)},
+		{"target", snap.Target()},
+	}
+	for _, m := range metadata {
+		if _, err := tx.Exec(`INSERT INTO metadata (key, value) VALUES (?, ?)`, m[0], m[1]); err != nil {
+			return fmt.Errorf("failed to write export metadata: %w", err)
+		}
+	}
+	return nil
+}
+
+// insertRepositories records the repositories of the organization and all scanned repositories
+func insertRepositories(tx *sql.Tx, snap *snapshot.Snapshot) error {
+	scanned := make(map[string]bool, len(snap.Repositories))
+	for _, r := range snap.Repositories {
+		scanned[r.Name] = true
+	}
+
+	stmt, err := tx.Prepare(`INSERT INTO repositories (name, archived, visibility, topics, scanned) VALUES (?, ?, ?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("failed to write repositories: %w", err)
+	}
+	defer stmt.Close()
+
+	known := make(map[string]bool, len(snap.OrgRepositories))
+	for _, r := range snap.OrgRepositories {
+		known[r.FullName] = true
+		if _, err := stmt.Exec(r.FullName, r.IsArchived, nullString(strings.ToLower(r.Visibility)), strings.Join(r.Topics, ","), scanned[r.FullName]); err != nil {
+			return fmt.Errorf("failed to write repository %s: %w", r.FullName, err)
+		}
+	}
+	for _, r := range snap.Repositories {
+		if known[r.Name] {
+			continue
+		}
+		if _, err := stmt.Exec(r.Name, nil, nil, "", true); err != nil {
+			return fmt.Errorf("failed to write repository %s: %w", r.Name, err)
+		}
+	}
+	return nil
+}
+
+// insertHooks records the webhooks of all scanned repositories
+func insertHooks(tx *sql.Tx, snap *snapshot.Snapshot) error {
+	stmt, err := tx.Prepare(`INSERT INTO hooks (repository, id, name, url, content_type, active, insecure_ssl, has_secret, events, deliveries_fetched)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("failed to write hooks: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, r := range snap.Repositories {
+		for _, h := range r.Hooks {
+			if _, err := stmt.Exec(r.Name, h.Hook.ID, h.Hook.Name, h.Hook.GetTargetURL(), h.Hook.Config.ContentType, h.Hook.Active,
+				h.Hook.Config.InsecureSSL.String() == "1", h.Hook.Config.Secret != "", strings.Join(h.Hook.Events, ","), h.Deliveries != nil); err != nil {
+				return fmt.Errorf("failed to write hook %d of %s: %w", h.Hook.ID, r.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// insertDeliveries records the deliveries of all hooks
+func insertDeliveries(tx *sql.Tx, snap *snapshot.Snapshot) error {
+	stmt, err := tx.Prepare(`INSERT OR REPLACE INTO deliveries (id, guid, repository, hook_id, delivered_at, redelivery, duration,
+		status, status_code, failed, error_class, event, action, installation_id, url)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("failed to write deliveries: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, r := range snap.Repositories {
+		for _, h := range r.Hooks {
+			for _, d := range h.Deliveries {
+				var installationID interface{}
+				if d.InstallationID != nil {
+					installationID = *d.InstallationID
+				}
+				if _, err := stmt.Exec(d.ID, d.GUID, r.Name, h.Hook.ID, d.DeliveredAt.UTC().Format(time.RFC3339), d.Redelivery, d.Duration,
+					d.Status, d.StatusCode, filter.IsFailed(d.StatusCode), nullString(filter.Classify(d.StatusCode, d.Status)),
+					d.Event, d.Action, installationID, h.Hook.GetTargetURL()); err != nil {
+					return fmt.Errorf("failed to write delivery %d: %w", d.ID, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// compressFile writes a gzip-compressed copy of src to dst
+func compressFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed to compress export: %w", err)
+	}
+	defer in.Close()
+
+	out, err := archive.Create(dst)
+	if err != nil {
+		return fmt.Errorf("failed to create export: %w", err)
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return fmt.Errorf("failed to compress export: %w", err)
+	}
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("failed to compress export: %w", err)
+	}
+	return nil
+}
+
+// nullString returns nil for empty strings, which are stored as NULL
+func nullString(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
@@ -0,0 +1,96 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Error classes of failed deliveries
+const (
+	ClassTimeout = "timeout" // No response: timed out, connection refused or reset
+	ClassDNS     = "dns"     // The target host could not be resolved
+	ClassTLS     = "tls"     // The TLS handshake or certificate verification failed
+	ClassClient  = "client"  // The receiver rejected the delivery with a 4xx status code
+	ClassServer  = "server"  // The receiver failed with a 5xx status code
+)
+
+// Classes lists all error classes
+var Classes = []string{ClassTimeout, ClassDNS, ClassTLS, ClassClient, ClassServer}
+
+// Classify returns the error class of a delivery from its status code and the status GitHub reports,
+// or an empty string if the delivery did not fail
+// DNS and TLS problems are only detected for deliveries without an HTTP response
+func Classify(statusCode int, status string) string {
+	if !IsFailed(statusCode) {
+		return ""
+	}
+	switch {
+	case statusCode >= 500:
+		return ClassServer
+	case statusCode >= 400:
+		return ClassClient
+	}
+
+	status = strings.ToLower(status)
+	switch {
+	case containsAny(status, "resolve host", "getaddrinfo", "name or service not known", "no such host", "dns"):
+		return ClassDNS
+	case containsAny(status, "ssl", "tls", "certificate", "x509", "handshake"):
+		return ClassTLS
+	default:
+		return ClassTimeout
+	}
+}
+
+// ClassFilter matches deliveries by their error class
+type ClassFilter struct {
+	classes map[string]bool
+}
+
+// ParseClassFilter parses a comma-separated list of error classes, e.g. "dns,tls"
+// An empty specification returns nil, which matches every delivery
+func ParseClassFilter(spec string) (*ClassFilter, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, nil
+	}
+
+	f := &ClassFilter{classes: make(map[string]bool)}
+	for _, part := range strings.Split(spec, ",") {
+		class := strings.ToLower(strings.TrimSpace(part))
+		if !isClass(class) {
+			return nil, fmt.Errorf("invalid error class %q, expected one of %s", part, strings.Join(Classes, ", "))
+		}
+		f.classes[class] = true
+	}
+	return f, nil
+}
+
+// Matches reports whether a delivery with the given status code and status belongs to one of the classes
+// A nil filter matches every delivery
+func (f *ClassFilter) Matches(statusCode int, status string) bool {
+	if f == nil {
+		return true
+	}
+	return f.classes[Classify(statusCode, status)]
+}
+
+// isClass reports whether class is a known error class
+func isClass(class string) bool {
+	for _, c := range Classes {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+// containsAny reports whether s contains any of the substrings
+func containsAny(s string, substrings ...string) bool {
+	for _, sub := range substrings {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,48 @@
+package filter
+
+import "testing"
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		status     string
+		want       string
+	}{
+		{name: "ok", statusCode: 200, status: "OK", want: ""},
+		{name: "redirect", statusCode: 302, status: "Found", want: ""},
+		{name: "client error", statusCode: 404, status: "Not Found", want: ClassClient},
+		{name: "server error", statusCode: 502, status: "Bad Gateway", want: ClassServer},
+		{name: "server error mentioning tls", statusCode: 503, status: "TLS terminator unavailable", want: ClassServer},
+		{name: "timeout", statusCode: 0, status: "timed out", want: ClassTimeout},
+		{name: "connection refused", statusCode: 0, status: "failed to connect to host", want: ClassTimeout},
+		{name: "unknown host", statusCode: 0, status: "Couldn't resolve host name", want: ClassDNS},
+		{name: "no such host", statusCode: 0, status: "lookup ci.example.com: no such host", want: ClassDNS},
+		{name: "certificate", statusCode: 0, status: "Peer certificate cannot be authenticated with given CA certificates", want: ClassTLS},
+		{name: "handshake", statusCode: 0, status: "SSL Handshake failed", want: ClassTLS},
+		{name: "empty status", statusCode: 0, status: "", want: ClassTimeout},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Classify(tt.statusCode, tt.status); got != tt.want {
+				t.Errorf("Classify(%d, %q) = %q, want %q", tt.statusCode, tt.status, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseClassFilter(t *testing.T) {
+	f, err := ParseClassFilter("DNS, tls")
+	if err != nil {
+		t.Fatalf("ParseClassFilter returned error: %v", err)
+	}
+	if !f.Matches(0, "no such host") || !f.Matches(0, "x509: certificate signed by unknown authority") {
+		t.Errorf("filter must match dns and tls failures")
+	}
+	if f.Matches(0, "timed out") || f.Matches(500, "") || f.Matches(200, "OK") {
+		t.Errorf("filter must not match other classes or successful deliveries")
+	}
+	if _, err := ParseClassFilter("dns,bogus"); err == nil {
+		t.Errorf("ParseClassFilter with an unknown class returned no error")
+	}
+}
@@ -0,0 +1,110 @@
+package filter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// IDFilter matches delivery IDs against a comparison, a range or an explicit list
+type IDFilter struct {
+	ids map[int]bool
+	min *int
+	max *int
+}
+
+// ParseIDFilter parses a delivery ID specification
+// Supported forms are:
+// - explicit IDs: "111" or "111,222,333"
+// - comparisons: ">=123", ">123", "<=123", "<123"
+// - inclusive ranges: "100-200"
+// An empty specification returns nil, which matches every delivery
+func ParseIDFilter(spec string) (*IDFilter, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, nil
+	}
+
+	f := &IDFilter{}
+
+	// Comparison operators (two-character operators are checked first)
+	for _, op := range []string{">=", "<=", ">", "<"} {
+		if !strings.HasPrefix(spec, op) {
+			continue
+		}
+		value, err := parseID(strings.TrimPrefix(spec, op))
+		if err != nil {
+			return nil, err
+		}
+		switch op {
+		case ">=":
+			f.min = &value
+		case ">":
+			value++
+			f.min = &value
+		case "<=":
+			f.max = &value
+		case "<":
+			value--
+			f.max = &value
+		}
+		return f, nil
+	}
+
+	// Inclusive range
+	if lower, upper, ok := strings.Cut(spec, "-"); ok {
+		from, err := parseID(lower)
+		if err != nil {
+			return nil, err
+		}
+		to, err := parseID(upper)
+		if err != nil {
+			return nil, err
+		}
+		if from > to {
+			return nil, fmt.Errorf("invalid delivery ID range %q: lower bound is greater than upper bound", spec)
+		}
+		f.min = &from
+		f.max = &to
+		return f, nil
+	}
+
+	// Explicit comma-separated list
+	f.ids = make(map[int]bool)
+	for _, part := range strings.Split(spec, ",") {
+		value, err := parseID(part)
+		if err != nil {
+			return nil, err
+		}
+		f.ids[value] = true
+	}
+
+	return f, nil
+}
+
+// Matches checks if a delivery ID satisfies the filter
+// A nil filter matches all IDs
+func (f *IDFilter) Matches(id int) bool {
+	if f == nil {
+		return true
+	}
+	if f.ids != nil {
+		return f.ids[id]
+	}
+	if f.min != nil && id < *f.min {
+		return false
+	}
+	if f.max != nil && id > *f.max {
+		return false
+	}
+	return true
+}
+
+func parseID(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	value, err := strconv.Atoi(s)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid delivery ID %q", s)
+	}
+	return value, nil
+}
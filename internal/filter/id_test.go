@@ -0,0 +1,58 @@
+package filter
+
+import "testing"
+
+func TestParseIDFilter(t *testing.T) {
+	tests := []struct {
+		spec    string
+		match   []int
+		noMatch []int
+	}{
+		{spec: "111", match: []int{111}, noMatch: []int{110, 112}},
+		{spec: "111, 222,333", match: []int{111, 222, 333}, noMatch: []int{0, 112, 334}},
+		{spec: ">=123", match: []int{123, 124, 1000}, noMatch: []int{0, 122}},
+		{spec: ">123", match: []int{124}, noMatch: []int{122, 123}},
+		{spec: "<=123", match: []int{0, 123}, noMatch: []int{124}},
+		{spec: "<123", match: []int{0, 122}, noMatch: []int{123, 124}},
+		{spec: "100-200", match: []int{100, 150, 200}, noMatch: []int{99, 201}},
+		{spec: " 100 - 100 ", match: []int{100}, noMatch: []int{99, 101}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			f, err := ParseIDFilter(tt.spec)
+			if err != nil {
+				t.Fatalf("ParseIDFilter(%q) returned error: %v", tt.spec, err)
+			}
+			for _, id := range tt.match {
+				if !f.Matches(id) {
+					t.Errorf("ParseIDFilter(%q).Matches(%d) = false, want true", tt.spec, id)
+				}
+			}
+			for _, id := range tt.noMatch {
+				if f.Matches(id) {
+					t.Errorf("ParseIDFilter(%q).Matches(%d) = true, want false", tt.spec, id)
+				}
+			}
+		})
+	}
+}
+
+func TestParseIDFilterEmpty(t *testing.T) {
+	for _, spec := range []string{"", "  "} {
+		f, err := ParseIDFilter(spec)
+		if err != nil || f != nil {
+			t.Fatalf("ParseIDFilter(%q) = %v, %v, want nil, nil", spec, f, err)
+		}
+		if !f.Matches(42) {
+			t.Errorf("nil filter must match every ID")
+		}
+	}
+}
+
+func TestParseIDFilterInvalid(t *testing.T) {
+	for _, spec := range []string{"abc", ">=", ">=x", "200-100", "1-", "-5", "1,,2", "1,x", "<-1"} {
+		if _, err := ParseIDFilter(spec); err == nil {
+			t.Errorf("ParseIDFilter(%q) returned no error", spec)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package filter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// InstallationFilter matches deliveries by the GitHub App installation that triggered them
+type InstallationFilter struct {
+	ids  map[int]bool
+	none bool // Only deliveries without an installation (classic webhooks)
+	any  bool // Only deliveries with any installation (GitHub Apps)
+}
+
+// ParseInstallationFilter parses an installation specification
+// Supported forms are:
+// - "none": deliveries of classic webhooks without an installation
+// - "any": deliveries triggered by any GitHub App installation
+// - explicit installation IDs: "123" or "123,456"
+// An empty specification returns nil, which matches every delivery
+func ParseInstallationFilter(spec string) (*InstallationFilter, error) {
+	spec = strings.TrimSpace(spec)
+	switch spec {
+	case "":
+		return nil, nil
+	case "none":
+		return &InstallationFilter{none: true}, nil
+	case "any":
+		return &InstallationFilter{any: true}, nil
+	}
+
+	f := &InstallationFilter{ids: make(map[int]bool)}
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		value, err := strconv.Atoi(part)
+		if err != nil || value <= 0 {
+			return nil, fmt.Errorf("invalid installation ID %q, expected an ID, 'none' or 'any'", part)
+		}
+		f.ids[value] = true
+	}
+	return f, nil
+}
+
+// Matches checks if a delivery's installation ID satisfies the filter
+// A nil installation ID means the delivery was not triggered by an installation
+// A nil filter matches all deliveries
+func (f *InstallationFilter) Matches(installationID *int) bool {
+	if f == nil {
+		return true
+	}
+	switch {
+	case f.none:
+		return installationID == nil
+	case f.any:
+		return installationID != nil
+	default:
+		return installationID != nil && f.ids[*installationID]
+	}
+}
@@ -0,0 +1,57 @@
+package filter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// NumberFilter matches deliveries by the pull request or issue they were generated for
+type NumberFilter struct {
+	pullRequests map[int]bool
+	issues       map[int]bool
+}
+
+// ParseNumberFilter parses comma-separated pull request and issue numbers, e.g. "1234" or "1234,1240"
+// A delivery matches if it belongs to any of the pull requests or any of the issues
+// Empty specifications return nil, which matches every delivery
+func ParseNumberFilter(pullRequests, issues string) (*NumberFilter, error) {
+	f := &NumberFilter{}
+	var err error
+	if f.pullRequests, err = parseNumbers(pullRequests); err != nil {
+		return nil, fmt.Errorf("--pr: %w", err)
+	}
+	if f.issues, err = parseNumbers(issues); err != nil {
+		return nil, fmt.Errorf("--issue: %w", err)
+	}
+	if len(f.pullRequests) == 0 && len(f.issues) == 0 {
+		return nil, nil
+	}
+	return f, nil
+}
+
+// Matches reports whether a delivery of the given pull request or issue number matches the filter, 0 means none
+// A nil filter matches every delivery
+func (f *NumberFilter) Matches(pullRequest, issue int) bool {
+	if f == nil {
+		return true
+	}
+	return (pullRequest > 0 && f.pullRequests[pullRequest]) || (issue > 0 && f.issues[issue])
+}
+
+// parseNumbers parses a comma-separated list of positive numbers
+func parseNumbers(spec string) (map[int]bool, error) {
+	numbers := make(map[int]bool)
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(part), "#"))
+		if part == "" {
+			continue
+		}
+		value, err := strconv.Atoi(part)
+		if err != nil || value <= 0 {
+			return nil, fmt.Errorf("invalid number %q", part)
+		}
+		numbers[value] = true
+	}
+	return numbers, nil
+}
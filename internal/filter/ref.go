@@ -0,0 +1,39 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RefFilter matches deliveries by the git ref of their request payload
+type RefFilter struct {
+	ref string // Fully qualified ref, e.g. refs/heads/main
+}
+
+// ParseRefFilter parses a git ref, e.g. "refs/heads/main" or "refs/tags/v1.0"
+// Refs without the refs/ prefix are taken as branch names
+// An empty specification returns nil, which matches every delivery
+func ParseRefFilter(spec string) (*RefFilter, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, nil
+	}
+	if strings.ContainsAny(spec, " \t~^:?*[\\") || strings.Contains(spec, "..") || strings.HasSuffix(spec, "/") {
+		return nil, fmt.Errorf("invalid git ref %q", spec)
+	}
+
+	if !strings.HasPrefix(spec, "refs/") {
+		spec = "refs/heads/" + spec
+	}
+	return &RefFilter{ref: spec}, nil
+}
+
+// Matches reports whether a delivery's fully qualified ref is the ref of the filter
+// Deliveries without a ref, e.g. of issue events, never match
+// A nil filter matches every delivery
+func (f *RefFilter) Matches(ref string) bool {
+	if f == nil {
+		return true
+	}
+	return ref == f.ref
+}
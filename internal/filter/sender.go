@@ -0,0 +1,47 @@
+package filter
+
+import (
+	"strings"
+)
+
+// SenderFilter matches deliveries by the login of the user or bot that triggered them
+type SenderFilter struct {
+	include map[string]bool
+	exclude map[string]bool
+}
+
+// ParseSenderFilter parses comma-separated lists of logins to include and to exclude, e.g. "dependabot[bot],renovate[bot]"
+// Logins are compared case-insensitively, like on GitHub
+// Empty specifications return nil, which matches every delivery
+func ParseSenderFilter(include, exclude string) *SenderFilter {
+	f := &SenderFilter{include: parseLogins(include), exclude: parseLogins(exclude)}
+	if len(f.include) == 0 && len(f.exclude) == 0 {
+		return nil
+	}
+	return f
+}
+
+// Matches reports whether a delivery's sender login is included and not excluded
+// Deliveries without a sender only match filters without included logins
+// A nil filter matches every delivery
+func (f *SenderFilter) Matches(login string) bool {
+	if f == nil {
+		return true
+	}
+	login = strings.ToLower(login)
+	if len(f.include) > 0 && !f.include[login] {
+		return false
+	}
+	return !f.exclude[login]
+}
+
+// parseLogins returns the lower-case logins of a comma-separated list
+func parseLogins(spec string) map[string]bool {
+	logins := make(map[string]bool)
+	for _, part := range strings.Split(spec, ",") {
+		if login := strings.ToLower(strings.TrimSpace(part)); login != "" {
+			logins[login] = true
+		}
+	}
+	return logins
+}
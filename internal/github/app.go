@@ -0,0 +1,128 @@
+package github
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// AppCredentials identify a GitHub App that authenticates as one of its installations
+type AppCredentials struct {
+	AppID          int64
+	PrivateKey     *rsa.PrivateKey
+	InstallationID int64 // Looked up for the monitored organization or repository if zero
+}
+
+// LoadPrivateKey reads a GitHub App private key in PEM format (PKCS#1 or PKCS#8)
+func LoadPrivateKey(path string) (*rsa.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read private key: %w", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("failed to parse private key %s: no PEM data found", path)
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key %s: %w", path, err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("failed to parse private key %s: not an RSA key", path)
+	}
+	return key, nil
+}
+
+// NewAppClient creates a GitHub API client authenticated as an installation of a GitHub App
+// The installation token is minted for target, which is an organization or OWNER/REPO
+// Installation tokens expire after one hour
+func NewAppClient(opts Options, creds AppCredentials, target string) (*Client, error) {
+	host := opts.resolveHost()
+
+	jwt, err := creds.jwt(time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	// go-gh sends tokens with the "token" scheme, JWTs require "Bearer"
+	appClient, err := api.NewRESTClient(api.ClientOptions{
+		Host:      host,
+		AuthToken: jwt,
+		Headers:   map[string]string{"Authorization": "Bearer " + jwt},
+		Transport: opts.Transport,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	installationID := creds.InstallationID
+	if installationID == 0 {
+		path := fmt.Sprintf("orgs/%s/installation", target)
+		if strings.Contains(target, "/") {
+			path = fmt.Sprintf("repos/%s/installation", target)
+		}
+		var installation struct {
+			ID int64 `json:"id"`
+		}
+		if err := appClient.Get(path, &installation); err != nil {
+			return nil, fmt.Errorf("failed to find installation of app %d for %s: %w", creds.AppID, target, err)
+		}
+		installationID = installation.ID
+	}
+
+	var token struct {
+		Token string `json:"token"`
+	}
+	path := fmt.Sprintf("app/installations/%d/access_tokens", installationID)
+	if err := appClient.Post(path, bytes.NewReader(nil), &token); err != nil {
+		return nil, fmt.Errorf("failed to create token for installation %d: %w", installationID, err)
+	}
+
+	opts.Host = host
+	return NewTokenClient(opts, token.Token)
+}
+
+// jwt creates the JSON Web Token identifying the app, valid for 10 minutes
+func (a *AppCredentials) jwt(now time.Time) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]interface{}{
+		// Issued in the past to allow for clock drift
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(9 * time.Minute).Unix(),
+		"iss": strconv.FormatInt(a.AppID, 10),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	encoding := base64.RawURLEncoding
+	unsigned := encoding.EncodeToString(header) + "." + encoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, a.PrivateKey, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign app token: %w", err)
+	}
+	return unsigned + "." + encoding.EncodeToString(signature), nil
+}
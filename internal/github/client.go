@@ -1,23 +1,208 @@
 package github
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
 	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/cli/go-gh/v2/pkg/auth"
 )
 
 // Client wraps the GitHub API client
+// Its requests run in the context bound with WithContext, e.g. the trace span of a repository scan
 type Client struct {
+	*clientState
+	ctx context.Context
+}
+
+// clientState is shared by a client and the copies returned by WithContext
+type clientState struct {
 	rest *api.RESTClient
+	gql  *api.GraphQLClient
+	memo requestMemo
+	host string
+
+	metaOnce sync.Once
+	meta     *ServerInfo
+	metaErr  error
+
+	scopesOnce     sync.Once
+	scopes         []string
+	scopesReported bool
+	scopesErr      error
+}
+
+// Options configure how the client connects to GitHub
+type Options struct {
+	Host      string            // GitHub host, resolved from the gh CLI environment (GH_HOST or the default host) if empty
+	Transport http.RoundTripper // HTTP transport (default: http.DefaultTransport), see TimeoutTransport for time limits
 }
 
 // NewClient creates a new GitHub API client
-// Uses gh CLI's authentication automatically
-func NewClient() (*Client, error) {
-	rest, err := api.DefaultRESTClient()
+// Uses gh CLI's authentication for the host automatically
+func NewClient(opts Options) (*Client, error) {
+	return newClient(api.ClientOptions{Host: opts.Host, Transport: opts.Transport})
+}
+
+// NewTokenClient creates a GitHub API client authenticating with the given token
+func NewTokenClient(opts Options, token string) (*Client, error) {
+	return newClient(api.ClientOptions{Host: opts.resolveHost(), AuthToken: token, Transport: opts.Transport})
+}
+
+// ErrOffline is returned for every API request of an offline client
+var ErrOffline = errors.New("API requests are disabled in offline mode")
+
+// NewOfflineClient creates a client that fails every API request with ErrOffline
+// No authentication is needed, as nothing is ever sent
+func NewOfflineClient(opts Options) (*Client, error) {
+	return newClient(api.ClientOptions{Host: opts.resolveHost(), AuthToken: "offline", Transport: offlineTransport{}})
+}
+
+// offlineTransport rejects all requests
+type offlineTransport struct{}
+
+func (offlineTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	return nil, ErrOffline
+}
+
+// NewTransport creates an HTTP transport that respects HTTPS_PROXY, HTTP_PROXY and NO_PROXY
+// Certificates in the PEM bundle at caBundle are trusted in addition to the system
+// certificates, e.g. for corporate proxies inspecting TLS traffic
+func NewTransport(caBundle string) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyFromEnvironment
+
+	if caBundle != "" {
+		pem, err := os.ReadFile(caBundle)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("failed to parse CA bundle %s: no certificates found", caBundle)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+
+	return transport, nil
+}
+
+// TimeoutTransport limits each request sent through base to timeout, including reading the response body
+// Unlike http.Client.Timeout it only covers the request itself, not the time outer transports wait
+// before sending it again, e.g. for a secondary rate limit; a zero timeout does not limit requests
+func TimeoutTransport(base http.RoundTripper, timeout time.Duration) http.RoundTripper {
+	if timeout <= 0 {
+		return base
+	}
+	return &timeoutTransport{base: base, timeout: timeout}
+}
+
+type timeoutTransport struct {
+	base    http.RoundTripper
+	timeout time.Duration
+}
+
+func (t *timeoutTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(req.Context(), t.timeout)
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelBody releases the timeout of a request once its response body is closed
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// resolveHost returns the host or the gh CLI default host if it is empty
+func (o Options) resolveHost() string {
+	if o.Host != "" {
+		return o.Host
+	}
+	host, _ := auth.DefaultHost()
+	return host
+}
+
+func newClient(opts api.ClientOptions) (*Client, error) {
+	rest, err := api.NewRESTClient(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	gql, err := api.NewGraphQLClient(opts)
 	if err != nil {
 		return nil, err
 	}
 
 	return &Client{
-		rest: rest,
+		clientState: &clientState{
+			rest: rest,
+			gql:  gql,
+			host: opts.Host,
+		},
+		ctx: context.Background(),
 	}, nil
 }
+
+// WithContext returns a copy of the client sending its requests in ctx
+// The copy shares the remembered responses and server information of the client
+func (c *Client) WithContext(ctx context.Context) *Client {
+	return &Client{clientState: c.clientState, ctx: ctx}
+}
+
+// Context returns the context the client's requests are sent in
+func (c *Client) Context() context.Context {
+	return c.ctx
+}
+
+// Host returns the GitHub host the client talks to
+func (c *Client) Host() string {
+	return Options{Host: c.host}.resolveHost()
+}
+
+// IsServerError reports whether err is an API response with a 5xx status code, e.g. 502 Bad Gateway
+// Such errors are usually transient and worth retrying
+func IsServerError(err error) bool {
+	var httpErr *api.HTTPError
+	return errors.As(err, &httpErr) && httpErr.StatusCode >= 500
+}
+
+// IsUnauthorized reports whether err is an API response with status 401, i.e. a missing, invalid or expired token
+func IsUnauthorized(err error) bool {
+	var httpErr *api.HTTPError
+	return errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusUnauthorized
+}
+
+// HTTPStatus returns the status code of an API error response, 0 if err is no API response
+func HTTPStatus(err error) int {
+	var httpErr *api.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode
+	}
+	return 0
+}
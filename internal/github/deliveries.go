@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net/http"
 	"sort"
 	"strings"
 	"time"
@@ -11,18 +12,34 @@ import (
 
 // Delivery represents a webhook delivery
 type Delivery struct {
-	ID          int       `json:"id"`
-	GUID        string    `json:"guid"`
-	DeliveredAt time.Time `json:"delivered_at"`
-	Redelivery  bool      `json:"redelivery"`
-	Duration    float64   `json:"duration"`
-	Status      string    `json:"status"`
-	StatusCode  int       `json:"status_code"`
-	Event       string    `json:"event"`
-	Action      string    `json:"action"`
-	URL         string    `json:"url,omitempty"` // Only available in detailed view
-	Repository  string    `json:"-"`             // Added by us to track which repo
-	HookID      int       `json:"-"`             // Added by us to track which hook
+	ID              int                    `json:"id"`
+	GUID            string                 `json:"guid"`
+	DeliveredAt     time.Time              `json:"delivered_at"`
+	Redelivery      bool                   `json:"redelivery"`
+	Duration        float64                `json:"duration"`
+	Status          string                 `json:"status"`
+	StatusCode      int                    `json:"status_code"`
+	Event           string                 `json:"event"`
+	Action          string                 `json:"action"`
+	InstallationID  *int                   `json:"installation_id,omitempty"`  // GitHub App installation, nil for classic webhooks
+	RepositoryID    *int                   `json:"repository_id,omitempty"`    // Repository the event belongs to, nil for events without a repository
+	URL             string                 `json:"url,omitempty"`              // Only available in detailed view
+	ErrorClass      string                 `json:"error_class,omitempty"`      // Added by us for output, empty for successful deliveries
+	ResponseExcerpt string                 `json:"response_excerpt,omitempty"` // Added by us, beginning of the response body of a failed delivery
+	PayloadValid    *bool                  `json:"payload_valid,omitempty"`    // Added by us, whether the request payload matches its event schema, nil if not validated
+	PayloadProblems []string               `json:"payload_problems,omitempty"` // Added by us, deviations of the request payload from its event schema
+	Ref             string                 `json:"ref,omitempty"`              // Added by us, fully qualified git ref of the request payload, only available in detailed view
+	Sender          string                 `json:"sender,omitempty"`           // Added by us, login of the sender of the request payload, only available in detailed view
+	PullRequest     int                    `json:"pull_request,omitempty"`     // Added by us, pull request number of the request payload, only available in detailed view
+	Issue           int                    `json:"issue,omitempty"`            // Added by us, issue number of the request payload, only available in detailed view
+	Fields          map[string]interface{} `json:"fields,omitempty"`           // Added by us, payload values of the --extract paths, only available in detailed view
+	PayloadSize     int                    `json:"payload_size,omitempty"`     // Added by us, size of the JSON-encoded request payload in bytes, only available in detailed view
+	ResponseSummary string                 `json:"response_summary,omitempty"` // Added by us, beginning of the response body on a single line, only available in detailed view
+	Attempts        int                    `json:"attempts,omitempty"`         // Added by us, number of fetched deliveries of the same event, the original delivery and its redeliveries
+	DurationDelta   *float64               `json:"duration_delta,omitempty"`   // Added by us, relative difference of the duration to the rolling median of the hook, e.g. 3.4 for +340%
+	Org             string                 `json:"org,omitempty"`              // Added by us, organization of an organization hook, empty for repository hooks
+	Repository      string                 `json:"-"`                          // Added by us to track which repo
+	HookID          int                    `json:"-"`                          // Added by us to track which hook
 }
 
 // DeliveryDetail represents a detailed webhook delivery with full information
@@ -38,29 +55,18 @@ type DeliveryDetail struct {
 	} `json:"response"`
 }
 
-// ListOrgHookDeliveries retrieves all deliveries for an organization hook
-func (c *Client) ListOrgHookDeliveries(org string, hookID int, perPage int) ([]Delivery, error) {
-	if perPage <= 0 {
-		perPage = 100
-	}
-
-	var deliveries []Delivery
-	path := fmt.Sprintf("orgs/%s/hooks/%d/deliveries?per_page=%d", org, hookID, perPage)
+// ListOrgHookDeliveries retrieves the most recent deliveries of an organization hook, up to limit
+func (c *Client) ListOrgHookDeliveries(org string, hookID int, limit int) ([]Delivery, error) {
+	return c.ListOrgHookDeliveriesSince(org, hookID, limit, time.Time{})
+}
 
-	response, err := c.rest.Request("GET", path, nil)
+// ListOrgHookDeliveriesSince retrieves the most recent deliveries of an organization hook, at least limit
+// and as many more as needed to reach back to since (zero = up to limit)
+func (c *Client) ListOrgHookDeliveriesSince(org string, hookID int, limit int, since time.Time) ([]Delivery, error) {
+	deliveries, err := c.listDeliveries(fmt.Sprintf("orgs/%s/hooks/%d/deliveries", org, hookID), limit, since)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list deliveries for org hook %d: %w", hookID, err)
 	}
-	defer response.Body.Close()
-
-	body, err := io.ReadAll(response.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
-	}
-
-	if err := json.Unmarshal(body, &deliveries); err != nil {
-		return nil, fmt.Errorf("failed to parse deliveries response: %w", err)
-	}
 
 	// Tag each delivery with the org and hook ID for reference
 	for i := range deliveries {
@@ -71,29 +77,18 @@ func (c *Client) ListOrgHookDeliveries(org string, hookID int, perPage int) ([]D
 	return deliveries, nil
 }
 
-// ListRepoHookDeliveries retrieves all deliveries for a repository hook
-func (c *Client) ListRepoHookDeliveries(repo string, hookID int, perPage int) ([]Delivery, error) {
-	if perPage <= 0 {
-		perPage = 100
-	}
-
-	var deliveries []Delivery
-	path := fmt.Sprintf("repos/%s/hooks/%d/deliveries?per_page=%d", repo, hookID, perPage)
+// ListRepoHookDeliveries retrieves the most recent deliveries of a repository hook, up to limit
+func (c *Client) ListRepoHookDeliveries(repo string, hookID int, limit int) ([]Delivery, error) {
+	return c.ListRepoHookDeliveriesSince(repo, hookID, limit, time.Time{})
+}
 
-	response, err := c.rest.Request("GET", path, nil)
+// ListRepoHookDeliveriesSince retrieves the most recent deliveries of a repository hook, at least limit
+// and as many more as needed to reach back to since (zero = up to limit)
+func (c *Client) ListRepoHookDeliveriesSince(repo string, hookID int, limit int, since time.Time) ([]Delivery, error) {
+	deliveries, err := c.listDeliveries(fmt.Sprintf("repos/%s/hooks/%d/deliveries", repo, hookID), limit, since)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list deliveries for repo hook %d: %w", hookID, err)
 	}
-	defer response.Body.Close()
-
-	body, err := io.ReadAll(response.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
-	}
-
-	if err := json.Unmarshal(body, &deliveries); err != nil {
-		return nil, fmt.Errorf("failed to parse deliveries response: %w", err)
-	}
 
 	// Tag each delivery with the repo and hook ID for reference
 	for i := range deliveries {
@@ -104,25 +99,130 @@ func (c *Client) ListRepoHookDeliveries(repo string, hookID int, perPage int) ([
 	return deliveries, nil
 }
 
+// maxPerPage is the largest page size of the REST API
+const maxPerPage = 100
+
+// deliveryPage is a requested page of deliveries whose body has not been read yet
+type deliveryPage struct {
+	response *http.Response
+	err      error
+}
+
+// listDeliveries retrieves up to limit deliveries of a hook, following the pagination cursor of the Link header
+// The cursor of a page is only known from the response of the previous page, so pages cannot be prefetched.
+// Instead the next page is requested as soon as the headers of a page arrived, while its body is still read and parsed.
+// With a non-zero since, pages beyond limit are requested one by one until a page reaches back to since.
+func (c *Client) listDeliveries(path string, limit int, since time.Time) ([]Delivery, error) {
+	if limit <= 0 {
+		limit = maxPerPage
+	}
+	perPage := min(limit, maxPerPage)
+	pages := (limit + perPage - 1) / perPage
+
+	responses := make(chan deliveryPage, 1)
+	more := make(chan struct{}, 1)
+	done := make(chan struct{})
+	defer func() {
+		// Stop requesting pages and close the bodies of pages that were not read
+		close(done)
+		for page := range responses {
+			if page.response != nil {
+				page.response.Body.Close()
+			}
+		}
+	}()
+
+	go func() {
+		defer close(responses)
+		next := fmt.Sprintf("%s?per_page=%d", path, perPage)
+		for i := 0; next != ""; i++ {
+			if i >= pages {
+				// Only the parsed previous page tells whether the window is covered already
+				if since.IsZero() {
+					return
+				}
+				select {
+				case <-more:
+				case <-done:
+					return
+				}
+			}
+			select {
+			case <-done:
+				return
+			default:
+			}
+			response, err := c.rest.RequestWithContext(c.ctx, "GET", next, nil)
+			if err == nil {
+				next = nextPageURL(response.Header.Get("Link"))
+			}
+			select {
+			case responses <- deliveryPage{response: response, err: err}:
+			case <-done:
+				if response != nil {
+					response.Body.Close()
+				}
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	var deliveries []Delivery
+	read := 0
+	for page := range responses {
+		if page.err != nil {
+			return nil, page.err
+		}
+		body, err := io.ReadAll(page.response.Body)
+		page.response.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response body: %w", err)
+		}
+
+		var batch []Delivery
+		if err := json.Unmarshal(body, &batch); err != nil {
+			return nil, fmt.Errorf("failed to parse deliveries response: %w", err)
+		}
+		deliveries = append(deliveries, batch...)
+
+		read++
+		if read >= pages && !since.IsZero() {
+			// Deliveries are listed newest first, the last one of a page is its oldest
+			if len(batch) == 0 || batch[len(batch)-1].DeliveredAt.Before(since) {
+				break
+			}
+			more <- struct{}{}
+		}
+	}
+
+	if since.IsZero() && len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+	return deliveries, nil
+}
+
+// nextPageURL returns the URL of the next page from a Link header, empty on the last page
+func nextPageURL(link string) string {
+	for _, part := range strings.Split(link, ",") {
+		target, params, ok := strings.Cut(strings.TrimSpace(part), ";")
+		if ok && strings.Contains(params, `rel="next"`) {
+			return strings.Trim(strings.TrimSpace(target), "<>")
+		}
+	}
+	return ""
+}
+
 // GetOrgHookDeliveryDetail retrieves detailed information for a specific delivery
 func (c *Client) GetOrgHookDeliveryDetail(org string, hookID int, deliveryID int) (*DeliveryDetail, error) {
 	var detail DeliveryDetail
 	path := fmt.Sprintf("orgs/%s/hooks/%d/deliveries/%d", org, hookID, deliveryID)
 
-	response, err := c.rest.Request("GET", path, nil)
-	if err != nil {
+	if err := c.get(path, &detail); err != nil {
 		return nil, fmt.Errorf("failed to get delivery detail: %w", err)
 	}
-	defer response.Body.Close()
-
-	body, err := io.ReadAll(response.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
-	}
-
-	if err := json.Unmarshal(body, &detail); err != nil {
-		return nil, fmt.Errorf("failed to parse delivery detail: %w", err)
-	}
 
 	detail.Repository = org
 	detail.HookID = hookID
@@ -135,20 +235,9 @@ func (c *Client) GetRepoHookDeliveryDetail(repo string, hookID int, deliveryID i
 	var detail DeliveryDetail
 	path := fmt.Sprintf("repos/%s/hooks/%d/deliveries/%d", repo, hookID, deliveryID)
 
-	response, err := c.rest.Request("GET", path, nil)
-	if err != nil {
+	if err := c.get(path, &detail); err != nil {
 		return nil, fmt.Errorf("failed to get delivery detail: %w", err)
 	}
-	defer response.Body.Close()
-
-	body, err := io.ReadAll(response.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
-	}
-
-	if err := json.Unmarshal(body, &detail); err != nil {
-		return nil, fmt.Errorf("failed to parse delivery detail: %w", err)
-	}
 
 	detail.Repository = repo
 	detail.HookID = hookID
@@ -156,6 +245,18 @@ func (c *Client) GetRepoHookDeliveryDetail(repo string, hookID int, deliveryID i
 	return &detail, nil
 }
 
+// RedeliverRepoHookDelivery requests a new delivery attempt for a repository hook delivery
+func (c *Client) RedeliverRepoHookDelivery(repo string, hookID int, deliveryID int) error {
+	var result map[string]interface{}
+	path := fmt.Sprintf("repos/%s/hooks/%d/deliveries/%d/attempts", repo, hookID, deliveryID)
+
+	if err := c.rest.DoWithContext(c.ctx, "POST", path, nil, &result); err != nil {
+		return fmt.Errorf("failed to redeliver delivery %d: %w", deliveryID, err)
+	}
+
+	return nil
+}
+
 // SortDeliveriesByTime sorts deliveries by timestamp
 // ascending=true sorts oldest first, ascending=false sorts newest first
 func SortDeliveriesByTime(deliveries []Delivery, ascending bool) {
@@ -167,10 +268,10 @@ func SortDeliveriesByTime(deliveries []Delivery, ascending bool) {
 	})
 }
 
-// SortDeliveriesByRepository sorts deliveries alphabetically by repository name
+// SortDeliveriesByRepository sorts deliveries by repository name, ignoring case and in natural order (repo-2 before repo-10)
 func SortDeliveriesByRepository(deliveries []Delivery, ascending bool) {
 	sort.Slice(deliveries, func(i, j int) bool {
-		cmp := strings.Compare(deliveries[i].Repository, deliveries[j].Repository)
+		cmp := CompareNatural(deliveries[i].Repository, deliveries[j].Repository)
 		if ascending {
 			return cmp < 0
 		}
@@ -215,3 +316,56 @@ func ApplySort(deliveries []Delivery, sortBy string, ascending bool) {
 		SortDeliveriesByTime(deliveries, false)
 	}
 }
+
+// CompareNatural compares strings case-insensitively, with runs of digits compared by their numeric value
+// Names differing only in case or leading zeros are ordered byte-wise, so the order is deterministic
+func CompareNatural(a, b string) int {
+	x, y := strings.ToLower(a), strings.ToLower(b)
+	for x != "" && y != "" {
+		if isDigit(x[0]) && isDigit(y[0]) {
+			nx, ny := digitPrefix(x), digitPrefix(y)
+			// Compare the values without leading zeros, a longer number is larger
+			vx, vy := strings.TrimLeft(nx, "0"), strings.TrimLeft(ny, "0")
+			if len(vx) != len(vy) {
+				return compareInt(len(vx), len(vy))
+			}
+			if cmp := strings.Compare(vx, vy); cmp != 0 {
+				return cmp
+			}
+			x, y = x[len(nx):], y[len(ny):]
+			continue
+		}
+		if x[0] != y[0] {
+			return compareInt(int(x[0]), int(y[0]))
+		}
+		x, y = x[1:], y[1:]
+	}
+	if x != "" || y != "" {
+		return compareInt(len(x), len(y))
+	}
+	return strings.Compare(a, b)
+}
+
+// digitPrefix returns the leading run of ASCII digits of s
+func digitPrefix(s string) string {
+	i := 0
+	for i < len(s) && isDigit(s[i]) {
+		i++
+	}
+	return s[:i]
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func compareInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,50 @@
+package github
+
+import "testing"
+
+func TestNextPageURL(t *testing.T) {
+	const next = "https://api.github.com/repositories/1/hooks/2/deliveries?per_page=100&cursor=v1_123"
+	tests := []struct {
+		name string
+		link string
+		want string
+	}{
+		{
+			name: "no header",
+			link: "",
+			want: "",
+		},
+		{
+			name: "next only",
+			link: `<` + next + `>; rel="next"`,
+			want: next,
+		},
+		{
+			name: "next among other relations",
+			link: `<https://api.github.com/repositories/1/hooks/2/deliveries?per_page=100>; rel="first", <` + next + `>; rel="next", <https://api.github.com/repositories/1/hooks/2/deliveries?per_page=100&cursor=v1_1>; rel="prev"`,
+			want: next,
+		},
+		{
+			name: "last page",
+			link: `<https://api.github.com/repositories/1/hooks/2/deliveries?per_page=100>; rel="first", <https://api.github.com/repositories/1/hooks/2/deliveries?per_page=100&cursor=v1_1>; rel="prev"`,
+			want: "",
+		},
+		{
+			name: "whitespace around entries",
+			link: `<https://api.github.com/repositories/1/hooks/2/deliveries?per_page=100>;rel="first" ,  <` + next + `> ; rel="next"  `,
+			want: next,
+		},
+		{
+			name: "malformed entry without parameters",
+			link: `<` + next + `>`,
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextPageURL(tt.link); got != tt.want {
+				t.Errorf("nextPageURL(%q) = %q, want %q", tt.link, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package github
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// RepoEvent represents an entry of a repository's public activity feed
+type RepoEvent struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ListRepoEvents retrieves the most recent activity of a repository
+// GitHub only keeps the events of the last 90 days
+func (c *Client) ListRepoEvents(repo string) ([]RepoEvent, error) {
+	var events []RepoEvent
+	err := c.get(fmt.Sprintf("repos/%s/events?per_page=100", repo), &events)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list repository events: %w", err)
+	}
+	return events, nil
+}
+
+// WebhookEvent returns the webhook event name corresponding to the activity type
+// e.g. "PullRequestReviewEvent" becomes "pull_request_review"
+func (e *RepoEvent) WebhookEvent() string {
+	name := strings.TrimSuffix(e.Type, "Event")
+	if name == "Gollum" {
+		return "gollum"
+	}
+
+	var b strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,82 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// requestMemo remembers the response bodies of GET requests for the lifetime of a client, a single run
+// Code paths needing the same hook list or delivery detail, e.g. several detail-based filters and
+// columns, then pay for one request; concurrent identical requests wait for the first one
+// Failed requests are forgotten, so they are sent again when retried
+type requestMemo struct {
+	mu      sync.Mutex
+	entries map[string]*memoEntry
+}
+
+type memoEntry struct {
+	done chan struct{} // Closed once body and err are set
+	body []byte
+	err  error
+}
+
+// get decodes the response to a GET request of path into out, sending the request only once per client
+func (c *Client) get(path string, out interface{}) error {
+	body, err := c.memo.fetch(path, func() ([]byte, error) {
+		response, err := c.rest.RequestWithContext(c.ctx, "GET", path, nil)
+		if err != nil {
+			return nil, err
+		}
+		defer response.Body.Close()
+
+		body, err := io.ReadAll(response.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response body: %w", err)
+		}
+		return body, nil
+	})
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, out)
+}
+
+// fetch returns the remembered body of path, or the body returned by send
+func (m *requestMemo) fetch(path string, send func() ([]byte, error)) ([]byte, error) {
+	m.mu.Lock()
+	if m.entries == nil {
+		m.entries = make(map[string]*memoEntry)
+	}
+	entry, found := m.entries[path]
+	if !found {
+		entry = &memoEntry{done: make(chan struct{})}
+		m.entries[path] = entry
+	}
+	m.mu.Unlock()
+
+	if found {
+		<-entry.done
+		return entry.body, entry.err
+	}
+
+	entry.body, entry.err = send()
+	if entry.err != nil {
+		m.forget(path)
+	}
+	close(entry.done)
+	return entry.body, entry.err
+}
+
+// forget drops the remembered responses of path and every path below it, e.g. after changing a resource
+func (m *requestMemo) forget(path string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for key := range m.entries {
+		if key == path || strings.HasPrefix(key, path+"/") {
+			delete(m.entries, key)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package github
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Feature is an API capability that older GitHub Enterprise Server versions lack
+type Feature struct {
+	Name       string
+	MinVersion string // Minimum GitHub Enterprise Server version
+}
+
+var (
+	// FeatureHookDeliveries lists and inspects webhook deliveries
+	FeatureHookDeliveries = Feature{Name: "the webhook deliveries API", MinVersion: "3.2"}
+	// FeatureRedelivery triggers new attempts of webhook deliveries
+	FeatureRedelivery = Feature{Name: "the webhook redelivery API", MinVersion: "3.2"}
+)
+
+// ServerInfo describes the GitHub instance the client talks to
+type ServerInfo struct {
+	InstalledVersion string `json:"installed_version"` // Only reported by GitHub Enterprise Server
+}
+
+// IsEnterprise checks if the server is a GitHub Enterprise Server
+func (s *ServerInfo) IsEnterprise() bool {
+	return s.InstalledVersion != ""
+}
+
+// Supports checks if the server provides the feature
+// GitHub.com and GHE.com always run the latest version
+func (s *ServerInfo) Supports(f Feature) bool {
+	if !s.IsEnterprise() {
+		return true
+	}
+	return compareVersions(s.InstalledVersion, f.MinVersion) >= 0
+}
+
+// Require returns an error explaining why the feature is unavailable, or nil if it is supported
+func (s *ServerInfo) Require(f Feature) error {
+	if s.Supports(f) {
+		return nil
+	}
+	return fmt.Errorf("%s requires GitHub Enterprise Server %s or later, this server runs %s", f.Name, f.MinVersion, s.InstalledVersion)
+}
+
+// GetServerInfo retrieves the server information from the meta endpoint
+// The result is fetched once and shared by all callers
+func (c *Client) GetServerInfo() (*ServerInfo, error) {
+	c.metaOnce.Do(func() {
+		var info ServerInfo
+		if err := c.rest.DoWithContext(c.ctx, "GET", "meta", nil, &info); err != nil {
+			c.metaErr = fmt.Errorf("failed to detect server version: %w", err)
+			return
+		}
+		c.meta = &info
+	})
+	return c.meta, c.metaErr
+}
+
+// compareVersions compares dotted version numbers like "3.10.2" numerically
+// Returns -1, 0 or 1, non-numeric suffixes (e.g. "3.12.0.rc1") are ignored
+func compareVersions(a, b string) int {
+	partsA := strings.Split(a, ".")
+	partsB := strings.Split(b, ".")
+	for i := 0; i < len(partsA) || i < len(partsB); i++ {
+		var x, y int
+		if i < len(partsA) {
+			x, _ = strconv.Atoi(partsA[i])
+		}
+		if i < len(partsB) {
+			y, _ = strconv.Atoi(partsB[i])
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
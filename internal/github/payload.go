@@ -0,0 +1,81 @@
+package github
+
+import (
+	"strconv"
+	"strings"
+)
+
+// PayloadRef returns the fully qualified git ref a webhook request payload refers to, empty if it has none
+// Push, create and delete events carry the ref itself, pull request events the ref of their base branch
+func PayloadRef(payload interface{}) string {
+	fields, _ := payload.(map[string]interface{})
+	if ref, ok := fields["ref"].(string); ok && ref != "" {
+		switch {
+		case strings.HasPrefix(ref, "refs/"):
+			return ref
+		case fields["ref_type"] == "tag":
+			// Create and delete events use short names and tell tags and branches apart by ref_type
+			return "refs/tags/" + ref
+		default:
+			return "refs/heads/" + ref
+		}
+	}
+
+	pullRequest, _ := fields["pull_request"].(map[string]interface{})
+	base, _ := pullRequest["base"].(map[string]interface{})
+	if ref, ok := base["ref"].(string); ok && ref != "" {
+		return "refs/heads/" + ref
+	}
+	return ""
+}
+
+// PayloadNumbers returns the number of the pull request or issue a webhook request payload belongs to, 0 if it has none
+// Comments on pull requests arrive as issue_comment events, their issue is reported as pull request
+func PayloadNumbers(payload interface{}) (pullRequest, issue int) {
+	fields, _ := payload.(map[string]interface{})
+	if pr, ok := fields["pull_request"].(map[string]interface{}); ok {
+		return number(pr["number"]), 0
+	}
+	if is, ok := fields["issue"].(map[string]interface{}); ok {
+		if _, ok := is["pull_request"]; ok {
+			return number(is["number"]), 0
+		}
+		return 0, number(is["number"])
+	}
+	return 0, 0
+}
+
+// number returns a JSON number as int, 0 if it is not a number
+func number(value interface{}) int {
+	n, _ := value.(float64)
+	return int(n)
+}
+
+// PayloadSender returns the login of the user or bot that triggered a webhook request payload, empty if it has none
+func PayloadSender(payload interface{}) string {
+	fields, _ := payload.(map[string]interface{})
+	sender, _ := fields["sender"].(map[string]interface{})
+	login, _ := sender["login"].(string)
+	return login
+}
+
+// PayloadField returns the value at a dot-separated path of a webhook request payload, e.g. pull_request.head.sha
+// Numeric keys index arrays, e.g. commits.0.id, nil is returned if the path does not exist
+func PayloadField(payload interface{}, path string) interface{} {
+	value := payload
+	for _, key := range strings.Split(path, ".") {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			value = v[key]
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil
+			}
+			value = v[i]
+		default:
+			return nil
+		}
+	}
+	return value
+}
@@ -0,0 +1,181 @@
+package github
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimit is the API budget of a rate limit resource, as reported by the X-RateLimit headers of a response
+type RateLimit struct {
+	Resource  string // Rate limit resource, e.g. core or graphql
+	Limit     int
+	Remaining int
+	Reset     time.Time // When the budget is refilled
+}
+
+// RateLimits records the rate limits reported by the API responses of a run
+// Responses of concurrent requests arrive in any order, so the lowest remaining budget of a reset window wins
+type RateLimits struct {
+	// OnSecondaryLimit is called whenever a request hit a secondary rate limit, before waiting to send it again
+	OnSecondaryLimit func(wait time.Duration)
+
+	mu          sync.Mutex
+	resources   map[string]RateLimit
+	requests    map[string]int // Responses counted per resource
+	pausedUntil time.Time      // No request is sent before, after a secondary rate limit was hit
+}
+
+// Secondary rate limits guard against abusive bulk requests, GitHub asks clients hitting one to wait
+// for Retry-After, until the budget resets if it is used up, or otherwise at least a minute
+const (
+	secondaryLimitRetries = 3
+	secondaryLimitWait    = time.Minute
+)
+
+// NewRateLimits creates an empty rate limit record
+func NewRateLimits() *RateLimits {
+	return &RateLimits{
+		resources: make(map[string]RateLimit),
+		requests:  make(map[string]int),
+	}
+}
+
+// Transport records the rate limit headers of every response passing through base
+func (r *RateLimits) Transport(base http.RoundTripper) http.RoundTripper {
+	return &rateLimitTransport{base: base, limits: r}
+}
+
+type rateLimitTransport struct {
+	base   http.RoundTripper
+	limits *RateLimits
+}
+
+// RoundTrip sends a request hitting a secondary rate limit again up to secondaryLimitRetries times
+// While waiting for a secondary rate limit, all other requests wait as well
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		if err := t.limits.waitPause(req); err != nil {
+			return nil, err
+		}
+		resp, err := t.base.RoundTrip(req)
+		if err != nil {
+			return resp, err
+		}
+		t.limits.Observe(resp.Header)
+
+		wait, limited := secondaryLimit(resp)
+		if !limited || attempt >= secondaryLimitRetries || (req.Body != nil && req.GetBody == nil) {
+			return resp, nil
+		}
+		resp.Body.Close()
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+		t.limits.pause(wait)
+		if t.limits.OnSecondaryLimit != nil {
+			t.limits.OnSecondaryLimit(wait)
+		}
+	}
+}
+
+// secondaryLimit reports whether a response is a secondary rate limit error and how long to wait before retrying
+// The body of such responses is kept readable for the caller
+func secondaryLimit(resp *http.Response) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil || !strings.Contains(strings.ToLower(string(body)), "secondary rate limit") {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			return max(time.Until(time.Unix(reset, 0)), 0), true
+		}
+	}
+	return secondaryLimitWait, true
+}
+
+// pause holds back all requests for wait
+func (r *RateLimits) pause(wait time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if until := time.Now().Add(wait); until.After(r.pausedUntil) {
+		r.pausedUntil = until
+	}
+}
+
+// waitPause waits until a pause after a secondary rate limit is over, or the request is cancelled
+func (r *RateLimits) waitPause(req *http.Request) error {
+	r.mu.Lock()
+	wait := time.Until(r.pausedUntil)
+	r.mu.Unlock()
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-req.Context().Done():
+		return req.Context().Err()
+	}
+}
+
+// Observe records the rate limit headers of a response, responses without them are ignored
+func (r *RateLimits) Observe(header http.Header) {
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+	limit, _ := strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	reset, _ := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64)
+	resource := header.Get("X-RateLimit-Resource")
+	if resource == "" {
+		resource = "core"
+	}
+
+	observed := RateLimit{Resource: resource, Limit: limit, Remaining: remaining, Reset: time.Unix(reset, 0)}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests[resource]++
+	current, ok := r.resources[resource]
+	if !ok || observed.Reset.After(current.Reset) || (observed.Reset.Equal(current.Reset) && observed.Remaining < current.Remaining) {
+		r.resources[resource] = observed
+	}
+}
+
+// Get returns the last known budget of a resource, false if no response reported it yet
+func (r *RateLimits) Get(resource string) (RateLimit, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	limit, ok := r.resources[resource]
+	return limit, ok
+}
+
+// Requests returns the number of responses of a resource recorded so far
+func (r *RateLimits) Requests(resource string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.requests[resource]
+}
@@ -0,0 +1,212 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// Repository represents a repository of an organization
+type Repository struct {
+	FullName   string     `json:"full_name"`
+	IsArchived bool       `json:"is_archived"`
+	IsFork     bool       `json:"is_fork"`
+	Language   string     `json:"language"`            // Primary language, empty if GitHub detected none
+	PushedAt   *time.Time `json:"pushed_at,omitempty"` // Time of the last push, nil if it is unknown
+	Visibility string     `json:"visibility"`
+	Topics     []string   `json:"topics"`
+}
+
+// GetRepositoryID retrieves the ID of a repository
+func (c *Client) GetRepositoryID(repo string) (int, error) {
+	var result struct {
+		ID int `json:"id"`
+	}
+	if err := c.get(fmt.Sprintf("repos/%s", repo), &result); err != nil {
+		return 0, fmt.Errorf("failed to get repository %s: %w", repo, err)
+	}
+	return result.ID, nil
+}
+
+// orgReposQuery fetches one page of organization repositories with their metadata
+const orgReposQuery = `query($org: String!, $perPage: Int!, $cursor: String) {
+  organization(login: $org) {
+    repositories(first: $perPage, after: $cursor, orderBy: {field: NAME, direction: ASC}) {
+      nodes {
+        nameWithOwner
+        isArchived
+        isFork
+        primaryLanguage {
+          name
+        }
+        pushedAt
+        visibility
+        repositoryTopics(first: 20) {
+          nodes {
+            topic {
+              name
+            }
+          }
+        }
+      }
+      pageInfo {
+        hasNextPage
+        endCursor
+      }
+    }
+  }
+}`
+
+// ListOrgRepos retrieves all repositories for an organization
+// Uses the GraphQL API to fetch repository metadata in the same round trip
+func (c *Client) ListOrgRepos(org string) ([]Repository, error) {
+	type response struct {
+		Organization *struct {
+			Repositories struct {
+				Nodes []struct {
+					NameWithOwner   string `json:"nameWithOwner"`
+					IsArchived      bool   `json:"isArchived"`
+					IsFork          bool   `json:"isFork"`
+					PrimaryLanguage *struct {
+						Name string `json:"name"`
+					} `json:"primaryLanguage"`
+					PushedAt         *time.Time `json:"pushedAt"`
+					Visibility       string     `json:"visibility"`
+					RepositoryTopics struct {
+						Nodes []struct {
+							Topic struct {
+								Name string `json:"name"`
+							} `json:"topic"`
+						} `json:"nodes"`
+					} `json:"repositoryTopics"`
+				} `json:"nodes"`
+				PageInfo struct {
+					HasNextPage bool   `json:"hasNextPage"`
+					EndCursor   string `json:"endCursor"`
+				} `json:"pageInfo"`
+			} `json:"repositories"`
+		} `json:"organization"`
+	}
+
+	var repos []Repository
+	variables := map[string]interface{}{
+		"org":     org,
+		"perPage": 100,
+		"cursor":  nil,
+	}
+
+	for {
+		var page response
+		if err := c.gql.DoWithContext(c.ctx, orgReposQuery, variables, &page); err != nil {
+			// Older GitHub Enterprise Server versions lack parts of the schema
+			var gqlErr *api.GraphQLError
+			if errors.As(err, &gqlErr) {
+				return c.listOrgReposREST(org)
+			}
+			return nil, fmt.Errorf("failed to list organization repositories: %w", err)
+		}
+
+		if page.Organization == nil {
+			return nil, fmt.Errorf("organization %s not found", org)
+		}
+
+		for _, node := range page.Organization.Repositories.Nodes {
+			repo := Repository{
+				FullName:   node.NameWithOwner,
+				IsArchived: node.IsArchived,
+				IsFork:     node.IsFork,
+				PushedAt:   node.PushedAt,
+				Visibility: node.Visibility,
+				Topics:     make([]string, 0, len(node.RepositoryTopics.Nodes)),
+			}
+			if node.PrimaryLanguage != nil {
+				repo.Language = node.PrimaryLanguage.Name
+			}
+			for _, t := range node.RepositoryTopics.Nodes {
+				repo.Topics = append(repo.Topics, t.Topic.Name)
+			}
+			repos = append(repos, repo)
+		}
+
+		pageInfo := page.Organization.Repositories.PageInfo
+		if !pageInfo.HasNextPage {
+			break
+		}
+		variables["cursor"] = pageInfo.EndCursor
+	}
+
+	return repos, nil
+}
+
+// listOrgReposREST retrieves all repositories for an organization using the REST API
+// Fallback for servers whose GraphQL schema does not support the repository query
+func (c *Client) listOrgReposREST(org string) ([]Repository, error) {
+	repos, err := c.listReposREST(fmt.Sprintf("orgs/%s/repos", org))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organization repositories: %w", err)
+	}
+	return repos, nil
+}
+
+// ListTeamRepos retrieves all repositories a team of an organization has access to
+func (c *Client) ListTeamRepos(org, team string) ([]Repository, error) {
+	repos, err := c.listReposREST(fmt.Sprintf("orgs/%s/teams/%s/repos", org, team))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list repositories of team %s/%s: %w", org, team, err)
+	}
+	return repos, nil
+}
+
+// listReposREST retrieves all pages of a REST repository listing
+func (c *Client) listReposREST(path string) ([]Repository, error) {
+	type restRepo struct {
+		FullName   string     `json:"full_name"`
+		Archived   bool       `json:"archived"`
+		Fork       bool       `json:"fork"`
+		Language   string     `json:"language"`
+		PushedAt   *time.Time `json:"pushed_at"`
+		Private    bool       `json:"private"`
+		Visibility string     `json:"visibility"`
+		Topics     []string   `json:"topics"`
+	}
+
+	var repos []Repository
+	for page := 1; ; page++ {
+		var batch []restRepo
+		if err := c.rest.DoWithContext(c.ctx, "GET", fmt.Sprintf("%s?per_page=100&page=%d", path, page), nil, &batch); err != nil {
+			return nil, err
+		}
+
+		for _, r := range batch {
+			visibility := strings.ToUpper(r.Visibility)
+			if visibility == "" {
+				visibility = "PUBLIC"
+				if r.Private {
+					visibility = "PRIVATE"
+				}
+			}
+			topics := r.Topics
+			if topics == nil {
+				topics = []string{}
+			}
+			repos = append(repos, Repository{
+				FullName:   r.FullName,
+				IsArchived: r.Archived,
+				IsFork:     r.Fork,
+				Language:   r.Language,
+				PushedAt:   r.PushedAt,
+				Visibility: visibility,
+				Topics:     topics,
+			})
+		}
+
+		if len(batch) < 100 {
+			break
+		}
+	}
+
+	return repos, nil
+}
@@ -0,0 +1,75 @@
+package github
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ScopeRequirement is an OAuth scope an operation needs, any scope of AnyOf grants it
+type ScopeRequirement struct {
+	Name  string // Scope to request, e.g. with gh auth refresh -s
+	AnyOf []string
+}
+
+var (
+	// ScopeRepoHooks reads the webhooks of repositories and their deliveries, repo includes it
+	ScopeRepoHooks = ScopeRequirement{Name: "admin:repo_hook", AnyOf: []string{"admin:repo_hook", "write:repo_hook", "read:repo_hook", "repo"}}
+	// ScopeOrgHooks reads the webhooks of organizations and their deliveries
+	ScopeOrgHooks = ScopeRequirement{Name: "admin:org_hook", AnyOf: []string{"admin:org_hook"}}
+)
+
+// SatisfiedBy reports whether one of the granted scopes grants the requirement
+func (r ScopeRequirement) SatisfiedBy(granted []string) bool {
+	for _, scope := range granted {
+		for _, accepted := range r.AnyOf {
+			if scope == accepted {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// TokenScopes returns the OAuth scopes of the client's token from the X-OAuth-Scopes response header
+// Only classic personal access tokens and OAuth app tokens have scopes, reported is false for
+// fine-grained tokens and GitHub App installations, whose permissions cannot be checked upfront
+// The scopes are fetched once and shared by all callers
+func (c *Client) TokenScopes() (scopes []string, reported bool, err error) {
+	c.scopesOnce.Do(func() {
+		c.scopes, c.scopesReported, c.scopesErr = c.fetchTokenScopes()
+	})
+	return c.scopes, c.scopesReported, c.scopesErr
+}
+
+func (c *Client) fetchTokenScopes() (scopes []string, reported bool, err error) {
+	response, err := c.rest.RequestWithContext(c.ctx, "GET", "", nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to check token scopes: %w", err)
+	}
+	defer response.Body.Close()
+
+	header, reported := response.Header["X-Oauth-Scopes"]
+	if !reported {
+		return nil, false, nil
+	}
+	for _, value := range header {
+		for _, scope := range strings.Split(value, ",") {
+			if scope = strings.TrimSpace(scope); scope != "" {
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+	return scopes, true, nil
+}
+
+// CurrentUser returns the login of the user the client authenticates as
+// GitHub App installations are not a user, the request fails for them
+func (c *Client) CurrentUser() (string, error) {
+	var user struct {
+		Login string `json:"login"`
+	}
+	if err := c.get("user", &user); err != nil {
+		return "", fmt.Errorf("failed to get the authenticated user: %w", err)
+	}
+	return user.Login, nil
+}
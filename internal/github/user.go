@@ -0,0 +1,36 @@
+package github
+
+import "fmt"
+
+// ListUserOrgs returns the logins of the organizations the authenticated user is a member of
+func (c *Client) ListUserOrgs() ([]string, error) {
+	var orgs []struct {
+		Login string `json:"login"`
+	}
+	if err := c.get("user/orgs?per_page=100", &orgs); err != nil {
+		return nil, fmt.Errorf("failed to list organizations of the authenticated user: %w", err)
+	}
+
+	logins := make([]string, 0, len(orgs))
+	for _, org := range orgs {
+		logins = append(logins, org.Login)
+	}
+	return logins, nil
+}
+
+// ListRecentRepos returns the limit repositories of the authenticated user pushed to most recently
+// They include the repositories of the user's organizations and those the user collaborates on
+func (c *Client) ListRecentRepos(limit int) ([]string, error) {
+	var repos []struct {
+		FullName string `json:"full_name"`
+	}
+	if err := c.get(fmt.Sprintf("user/repos?sort=pushed&per_page=%d", limit), &repos); err != nil {
+		return nil, fmt.Errorf("failed to list repositories of the authenticated user: %w", err)
+	}
+
+	names := make([]string, 0, len(repos))
+	for _, repo := range repos {
+		names = append(names, repo.FullName)
+	}
+	return names, nil
+}
@@ -1,26 +1,54 @@
 package github
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
-	"io"
+	"net/url"
 	"strings"
 )
 
 // Hook represents a GitHub webhook
 type Hook struct {
-	ID     int    `json:"id"`
-	URL    string `json:"url"`
-	Active bool   `json:"active"`
+	ID     int      `json:"id"`
+	Name   string   `json:"name"`
+	URL    string   `json:"url"`
+	Active bool     `json:"active"`
+	Events []string `json:"events"`
 	Config struct {
-		URL string `json:"url"`
+		URL         string      `json:"url"`
+		ContentType string      `json:"content_type"`
+		InsecureSSL json.Number `json:"insecure_ssl"`     // "0" or "1", sent as string or number
+		Secret      string      `json:"secret,omitempty"` // Masked by GitHub, only tells whether a secret is set
 	} `json:"config"`
 }
 
+// RepoHook is a webhook together with the repository it belongs to
+type RepoHook struct {
+	Repository string
+	Hook
+}
+
+// HookConfigUpdate holds webhook configuration fields, empty fields are left unchanged
+type HookConfigUpdate struct {
+	URL         string `json:"url,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	InsecureSSL string `json:"insecure_ssl,omitempty"`
+	Secret      string `json:"secret,omitempty"`
+}
+
+// HookUpdate holds webhook fields to change, empty fields are left unchanged
+type HookUpdate struct {
+	Active       *bool    `json:"active,omitempty"`
+	Events       []string `json:"events,omitempty"`
+	AddEvents    []string `json:"add_events,omitempty"`
+	RemoveEvents []string `json:"remove_events,omitempty"`
+}
+
 // ListOrgWebhooks retrieves all webhooks for an organization
 func (c *Client) ListOrgWebhooks(org string) ([]Hook, error) {
 	var hooks []Hook
-	err := c.rest.Get(fmt.Sprintf("orgs/%s/hooks", org), &hooks)
+	err := c.get(fmt.Sprintf("orgs/%s/hooks", org), &hooks)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list organization webhooks: %w", err)
 	}
@@ -30,61 +58,71 @@ func (c *Client) ListOrgWebhooks(org string) ([]Hook, error) {
 // ListRepoWebhooks retrieves all webhooks for a repository
 func (c *Client) ListRepoWebhooks(repo string) ([]Hook, error) {
 	var hooks []Hook
-	err := c.rest.Get(fmt.Sprintf("repos/%s/hooks", repo), &hooks)
+	err := c.get(fmt.Sprintf("repos/%s/hooks", repo), &hooks)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list repository webhooks: %w", err)
 	}
 	return hooks, nil
 }
 
-// ListOrgRepos retrieves all repositories for an organization
-func (c *Client) ListOrgRepos(org string) ([]string, error) {
-	type repo struct {
-		FullName string `json:"full_name"`
+// CreateRepoWebhook creates a webhook for a repository
+func (c *Client) CreateRepoWebhook(repo string, config HookConfigUpdate, events []string, active bool) (*Hook, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"name":   "web",
+		"active": active,
+		"events": events,
+		"config": config,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode webhook: %w", err)
 	}
 
-	var repos []repo
-	page := 1
-	perPage := 100
-
-	for {
-		var pageRepos []repo
-		path := fmt.Sprintf("orgs/%s/repos?per_page=%d&page=%d", org, perPage, page)
-
-		response, err := c.rest.Request("GET", path, nil)
-		if err != nil {
-			return nil, fmt.Errorf("failed to list organization repositories: %w", err)
-		}
-		defer response.Body.Close()
-
-		body, err := io.ReadAll(response.Body)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read response body: %w", err)
-		}
-
-		if err := json.Unmarshal(body, &pageRepos); err != nil {
-			return nil, fmt.Errorf("failed to parse repositories response: %w", err)
-		}
-
-		if len(pageRepos) == 0 {
-			break
-		}
+	var hook Hook
+	if err := c.rest.DoWithContext(c.ctx, "POST", fmt.Sprintf("repos/%s/hooks", repo), bytes.NewReader(body), &hook); err != nil {
+		return nil, fmt.Errorf("failed to create repository webhook: %w", err)
+	}
+	c.memo.forget(fmt.Sprintf("repos/%s/hooks", repo))
+	return &hook, nil
+}
 
-		repos = append(repos, pageRepos...)
+// UpdateRepoWebhook changes the active state and events of a repository webhook
+func (c *Client) UpdateRepoWebhook(repo string, hookID int, update HookUpdate) (*Hook, error) {
+	body, err := json.Marshal(update)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode webhook update: %w", err)
+	}
 
-		if len(pageRepos) < perPage {
-			break
-		}
+	var hook Hook
+	if err := c.rest.DoWithContext(c.ctx, "PATCH", fmt.Sprintf("repos/%s/hooks/%d", repo, hookID), bytes.NewReader(body), &hook); err != nil {
+		return nil, fmt.Errorf("failed to update repository webhook %d: %w", hookID, err)
+	}
+	c.memo.forget(fmt.Sprintf("repos/%s/hooks", repo))
+	return &hook, nil
+}
 
-		page++
+// UpdateRepoWebhookConfig changes configuration fields of a repository webhook
+// Uses the config endpoint, so fields not given (e.g. the secret) are kept
+func (c *Client) UpdateRepoWebhookConfig(repo string, hookID int, config HookConfigUpdate) error {
+	body, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook config: %w", err)
 	}
 
-	names := make([]string, len(repos))
-	for i, r := range repos {
-		names[i] = r.FullName
+	var result map[string]interface{}
+	if err := c.rest.DoWithContext(c.ctx, "PATCH", fmt.Sprintf("repos/%s/hooks/%d/config", repo, hookID), bytes.NewReader(body), &result); err != nil {
+		return fmt.Errorf("failed to update config of repository webhook %d: %w", hookID, err)
 	}
+	c.memo.forget(fmt.Sprintf("repos/%s/hooks", repo))
+	return nil
+}
 
-	return names, nil
+// DeleteRepoWebhook deletes a repository webhook
+func (c *Client) DeleteRepoWebhook(repo string, hookID int) error {
+	if err := c.rest.DoWithContext(c.ctx, "DELETE", fmt.Sprintf("repos/%s/hooks/%d", repo, hookID), nil, nil); err != nil {
+		return fmt.Errorf("failed to delete repository webhook %d: %w", hookID, err)
+	}
+	c.memo.forget(fmt.Sprintf("repos/%s/hooks", repo))
+	return nil
 }
 
 // GetWebhookTargetURL extracts the target URL from a webhook
@@ -98,6 +136,34 @@ func (h *Hook) GetTargetURL() string {
 	return ""
 }
 
+// GetTargetHost returns the hostname of the webhook target URL
+func (h *Hook) GetTargetHost() string {
+	u, err := url.Parse(h.GetTargetURL())
+	if err != nil {
+		return ""
+	}
+	return u.Hostname()
+}
+
+// GetContentType returns the payload content type, "json" or "form"
+// GitHub sends form-encoded payloads if no content type is configured
+func (h *Hook) GetContentType() string {
+	if h.Config.ContentType == "" {
+		return "form"
+	}
+	return h.Config.ContentType
+}
+
+// IsInsecureSSL checks if SSL certificate verification is disabled for the webhook
+func (h *Hook) IsInsecureSSL() bool {
+	return h.Config.InsecureSSL == "1"
+}
+
+// HasSecret checks if the webhook is configured with a shared secret
+func (h *Hook) HasSecret() bool {
+	return h.Config.Secret != ""
+}
+
 // MatchesFilter checks if the webhook's target URL matches the filter pattern
 func (h *Hook) MatchesFilter(pattern string) bool {
 	if pattern == "" {
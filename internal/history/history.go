@@ -0,0 +1,355 @@
+package history
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ohader/gh-hookmon/internal/github"
+
+	_ "modernc.org/sqlite" // Pure Go SQLite driver, no cgo required
+)
+
+// Store is the local SQLite database of webhook deliveries collected by earlier runs
+type Store struct {
+	db   *sql.DB
+	path string
+}
+
+// migrations create and evolve the schema, the database's user_version is the number of applied migrations
+var migrations = []string{
+	`CREATE TABLE deliveries (
+		id              INTEGER PRIMARY KEY,
+		guid            TEXT    NOT NULL,
+		repository      TEXT    NOT NULL,
+		hook_id         INTEGER NOT NULL,
+		delivered_at    INTEGER NOT NULL,
+		redelivery      INTEGER NOT NULL,
+		duration        REAL    NOT NULL,
+		status          TEXT    NOT NULL,
+		status_code     INTEGER NOT NULL,
+		event           TEXT    NOT NULL,
+		action          TEXT    NOT NULL,
+		installation_id INTEGER,
+		url             TEXT    NOT NULL,
+		recorded_at     INTEGER NOT NULL
+	);
+	CREATE INDEX deliveries_repository ON deliveries (repository, delivered_at);
+	CREATE INDEX deliveries_delivered_at ON deliveries (delivered_at);`,
+	`ALTER TABLE deliveries ADD COLUMN response_excerpt TEXT NOT NULL DEFAULT '';`,
+}
+
+// DefaultPath returns the default location of the database in the user's cache directory
+// (e.g. ~/.cache/gh-hookmon/history.db)
+func DefaultPath() (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine cache directory: %w", err)
+	}
+	return filepath.Join(base, "gh-hookmon", "history.db"), nil
+}
+
+// Open opens the database at path, creating it and migrating its schema if needed
+func Open(path string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history: %w", err)
+	}
+	// SQLite allows a single writer, concurrent workers take turns
+	db.SetMaxOpenConns(1)
+
+	s := &Store{db: db, path: path}
+	if err := s.migrate(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// migrate applies all migrations the database has not seen yet
+func (s *Store) migrate() error {
+	var version int
+	if err := s.db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return fmt.Errorf("failed to read history schema version: %w", err)
+	}
+	if version > len(migrations) {
+		return fmt.Errorf("history database %s was created by a newer release (schema version %d)", s.path, version)
+	}
+
+	for i := version; i < len(migrations); i++ {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return fmt.Errorf("failed to migrate history: %w", err)
+		}
+		if _, err := tx.Exec(migrations[i]); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to migrate history to schema version %d: %w", i+1, err)
+		}
+		if _, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", i+1)); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to migrate history to schema version %d: %w", i+1, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("failed to migrate history to schema version %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// Path returns the location of the database
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Close closes the database
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Record stores deliveries not stored yet and returns the number of new deliveries
+// Deliveries never change once made, so stored deliveries are kept as they are
+func (s *Store) Record(deliveries []github.Delivery) (int, error) {
+	if len(deliveries) == 0 {
+		return 0, nil
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to record deliveries: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`INSERT INTO deliveries
+		(id, guid, repository, hook_id, delivered_at, redelivery, duration, status, status_code, event, action, installation_id, url, response_excerpt, recorded_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO NOTHING`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to record deliveries: %w", err)
+	}
+	defer stmt.Close()
+
+	now := time.Now().Unix()
+	added := 0
+	for _, d := range deliveries {
+		var installationID sql.NullInt64
+		if d.InstallationID != nil {
+			installationID = sql.NullInt64{Int64: int64(*d.InstallationID), Valid: true}
+		}
+		result, err := stmt.Exec(d.ID, d.GUID, d.Repository, d.HookID, d.DeliveredAt.Unix(), d.Redelivery, d.Duration,
+			d.Status, d.StatusCode, d.Event, d.Action, installationID, d.URL, d.ResponseExcerpt, now)
+		if err != nil {
+			return 0, fmt.Errorf("failed to record delivery %d: %w", d.ID, err)
+		}
+		if n, err := result.RowsAffected(); err == nil {
+			added += int(n)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to record deliveries: %w", err)
+	}
+	return added, nil
+}
+
+// SetResponseExcerpts stores the response excerpts of recorded deliveries, deliveries without an excerpt are skipped
+func (s *Store) SetResponseExcerpts(deliveries []github.Delivery) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to record response excerpts: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare("UPDATE deliveries SET response_excerpt = ? WHERE id = ?")
+	if err != nil {
+		return fmt.Errorf("failed to record response excerpts: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, d := range deliveries {
+		if d.ResponseExcerpt == "" {
+			continue
+		}
+		if _, err := stmt.Exec(d.ResponseExcerpt, d.ID); err != nil {
+			return fmt.Errorf("failed to record response excerpt of delivery %d: %w", d.ID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to record response excerpts: %w", err)
+	}
+	return nil
+}
+
+// Prune deletes the deliveries made before the given time and returns the number of deleted deliveries
+// The file only shrinks once the database is vacuumed
+func (s *Store) Prune(before time.Time) (int64, error) {
+	result, err := s.db.Exec("DELETE FROM deliveries WHERE delivered_at < ?", before.Unix())
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune history: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// Query selects stored deliveries, empty fields do not restrict the selection
+type Query struct {
+	Org   string   // Repositories of the organization
+	Repo  string   // A single repository OWNER/REPO
+	Repos []string // Any of these repositories
+	Since *time.Time
+	Until *time.Time
+}
+
+// Deliveries returns the stored deliveries matching the query, newest first
+func (s *Store) Deliveries(q Query) ([]github.Delivery, error) {
+	where, args := q.where()
+	query := `SELECT id, guid, repository, hook_id, delivered_at, redelivery, duration, status, status_code, event, action, installation_id, url, response_excerpt
+		FROM deliveries` + where + " ORDER BY delivered_at DESC, id DESC"
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query history: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := make([]github.Delivery, 0)
+	for rows.Next() {
+		var d github.Delivery
+		var deliveredAt int64
+		var installationID sql.NullInt64
+		if err := rows.Scan(&d.ID, &d.GUID, &d.Repository, &d.HookID, &deliveredAt, &d.Redelivery, &d.Duration,
+			&d.Status, &d.StatusCode, &d.Event, &d.Action, &installationID, &d.URL, &d.ResponseExcerpt); err != nil {
+			return nil, fmt.Errorf("failed to read history: %w", err)
+		}
+		d.DeliveredAt = time.Unix(deliveredAt, 0).UTC()
+		if installationID.Valid {
+			id := int(installationID.Int64)
+			d.InstallationID = &id
+		}
+		deliveries = append(deliveries, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	return deliveries, nil
+}
+
+// where returns the WHERE clause of the query, empty if it matches all deliveries, and its arguments
+func (q Query) where() (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	if q.Repo != "" {
+		conditions = append(conditions, "repository = ? COLLATE NOCASE")
+		args = append(args, q.Repo)
+	}
+	if len(q.Repos) > 0 {
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(q.Repos)), ", ")
+		conditions = append(conditions, "repository COLLATE NOCASE IN ("+placeholders+")")
+		for _, repo := range q.Repos {
+			args = append(args, repo)
+		}
+	}
+	if q.Org != "" {
+		conditions = append(conditions, `repository LIKE ? ESCAPE '\'`)
+		args = append(args, escapeLike(q.Org)+"/%")
+	}
+	if q.Since != nil {
+		conditions = append(conditions, "delivered_at >= ?")
+		args = append(args, q.Since.Unix())
+	}
+	if q.Until != nil {
+		conditions = append(conditions, "delivered_at <= ?")
+		args = append(args, q.Until.Unix())
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// Oldest returns the time of the oldest stored delivery matching the query, nil if there is none
+func (s *Store) Oldest(q Query) (*time.Time, error) {
+	where, args := q.where()
+	var oldest sql.NullInt64
+	if err := s.db.QueryRow("SELECT MIN(delivered_at) FROM deliveries"+where, args...).Scan(&oldest); err != nil {
+		return nil, fmt.Errorf("failed to query history: %w", err)
+	}
+	if !oldest.Valid {
+		return nil, nil
+	}
+	t := time.Unix(oldest.Int64, 0).UTC()
+	return &t, nil
+}
+
+// Stats summarizes the stored history
+type Stats struct {
+	Path         string     `json:"path"`
+	Size         int64      `json:"size_bytes"`
+	Deliveries   int        `json:"deliveries"`
+	Repositories int        `json:"repositories"`
+	Hooks        int        `json:"hooks"`
+	Oldest       *time.Time `json:"oldest,omitempty"`
+	Newest       *time.Time `json:"newest,omitempty"`
+}
+
+// Stats summarizes the stored deliveries and the size of the database file
+func (s *Store) Stats() (Stats, error) {
+	stats := Stats{Path: s.path}
+
+	var oldest, newest sql.NullInt64
+	err := s.db.QueryRow(`SELECT COUNT(*), COUNT(DISTINCT repository), COUNT(DISTINCT repository || '#' || hook_id),
+		MIN(delivered_at), MAX(delivered_at) FROM deliveries`).Scan(&stats.Deliveries, &stats.Repositories, &stats.Hooks, &oldest, &newest)
+	if err != nil {
+		return stats, fmt.Errorf("failed to summarize history: %w", err)
+	}
+	if oldest.Valid {
+		t := time.Unix(oldest.Int64, 0).UTC()
+		stats.Oldest = &t
+	}
+	if newest.Valid {
+		t := time.Unix(newest.Int64, 0).UTC()
+		stats.Newest = &t
+	}
+
+	size, err := s.size()
+	if err != nil {
+		return stats, err
+	}
+	stats.Size = size
+	return stats, nil
+}
+
+// Vacuum compacts the database file and returns its size before and after
+func (s *Store) Vacuum() (before, after int64, err error) {
+	if before, err = s.size(); err != nil {
+		return 0, 0, err
+	}
+	if _, err := s.db.Exec("VACUUM"); err != nil {
+		return 0, 0, fmt.Errorf("failed to vacuum history: %w", err)
+	}
+	if after, err = s.size(); err != nil {
+		return 0, 0, err
+	}
+	return before, after, nil
+}
+
+// size returns the size of the database file
+func (s *Store) size() (int64, error) {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to determine history size: %w", err)
+	}
+	return info.Size(), nil
+}
+
+// escapeLike escapes the wildcards of a LIKE pattern
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
@@ -0,0 +1,63 @@
+package output
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/ohader/gh-hookmon/internal/stats"
+)
+
+// FormatAnomalies outputs one line per detected failure spike
+func FormatAnomalies(anomalies []stats.Anomaly, w io.Writer) {
+	if len(anomalies) == 0 {
+		fmt.Fprintln(w, "No failure spikes detected")
+		return
+	}
+
+	for _, a := range anomalies {
+		url := a.Hook.URL
+		if url == "" {
+			url = "-"
+		}
+		fmt.Fprintf(w, "SPIKE %s hook %d %s: %s failed (%d of %d) vs %s baseline (%d of %d), %.1fx, z=%.1f\n",
+			a.Hook.Repository, a.Hook.HookID, url,
+			formatPercent(a.Current.FailureRate()), a.Current.Failures, a.Current.Deliveries,
+			formatPercent(a.Baseline.FailureRate()), a.Baseline.Failures, a.Baseline.Deliveries,
+			a.Ratio, a.ZScore)
+	}
+}
+
+// FormatAnomaliesJSON outputs detected failure spikes in JSON format
+func FormatAnomaliesJSON(anomalies []stats.Anomaly, w io.Writer) error {
+	type jsonAnomaly struct {
+		Repository          string  `json:"repository"`
+		HookID              int     `json:"hook_id"`
+		URL                 string  `json:"url"`
+		Deliveries          int     `json:"deliveries"`
+		Failures            int     `json:"failures"`
+		FailureRate         float64 `json:"failure_rate"`
+		BaselineDeliveries  int     `json:"baseline_deliveries"`
+		BaselineFailures    int     `json:"baseline_failures"`
+		BaselineFailureRate float64 `json:"baseline_failure_rate"`
+		Ratio               float64 `json:"ratio"`
+		ZScore              float64 `json:"z_score"`
+	}
+
+	display := make([]jsonAnomaly, len(anomalies))
+	for i, a := range anomalies {
+		display[i] = jsonAnomaly{
+			Repository:          a.Hook.Repository,
+			HookID:              a.Hook.HookID,
+			URL:                 a.Hook.URL,
+			Deliveries:          a.Current.Deliveries,
+			Failures:            a.Current.Failures,
+			FailureRate:         a.Current.FailureRate(),
+			BaselineDeliveries:  a.Baseline.Deliveries,
+			BaselineFailures:    a.Baseline.Failures,
+			BaselineFailureRate: a.Baseline.FailureRate(),
+			Ratio:               a.Ratio,
+			ZScore:              a.ZScore,
+		}
+	}
+	return encodeJSON(display, w)
+}
@@ -0,0 +1,62 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Finding is a problem detected by an audit check
+type Finding struct {
+	Check      string `json:"check"`
+	Repository string `json:"repository"`
+	HookID     int    `json:"hook_id"`
+	URL        string `json:"url"`
+	Domain     string `json:"domain"`
+	Message    string `json:"message"`
+}
+
+// FormatFindings outputs audit findings grouped by target domain
+func FormatFindings(findings []Finding, w io.Writer) {
+	if len(findings) == 0 {
+		fmt.Fprintln(w, "No audit findings")
+		return
+	}
+
+	groups := make(map[string][]Finding)
+	for _, f := range findings {
+		groups[f.Domain] = append(groups[f.Domain], f)
+	}
+
+	domains := make([]string, 0, len(groups))
+	for domain := range groups {
+		domains = append(domains, domain)
+	}
+	sort.Strings(domains)
+
+	for i, domain := range domains {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		name := domain
+		if name == "" {
+			name = "(unknown domain)"
+		}
+		fmt.Fprintf(w, "%s (%d findings)\n", name, len(groups[domain]))
+		for _, f := range groups[domain] {
+			url := f.URL
+			if url == "" {
+				url = "-"
+			}
+			fmt.Fprintf(w, "  [%s] %s hook %d %s: %s\n", f.Check, f.Repository, f.HookID, url, f.Message)
+		}
+	}
+}
+
+// FormatFindingsJSON outputs audit findings in JSON format
+func FormatFindingsJSON(findings []Finding, w io.Writer) error {
+	if findings == nil {
+		findings = []Finding{}
+	}
+	return encodeJSON(findings, w)
+}
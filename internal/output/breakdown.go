@@ -0,0 +1,87 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ohader/gh-hookmon/internal/stats"
+	"github.com/olekukonko/tablewriter"
+	"github.com/olekukonko/tablewriter/tw"
+)
+
+// FormatBreakdownTable outputs the summary of every group of a breakdown as an ASCII table
+func FormatBreakdownTable(groups []stats.Group, dim stats.Dimension, w io.Writer) {
+	if len(groups) == 0 {
+		fmt.Fprintln(w, "No deliveries found")
+		return
+	}
+
+	header := append(append([]string(nil), dim.Columns...), "Deliveries", "Failures", "Failure Rate", "Median", "P95", "Last Failure")
+	// Automatic formatting would turn P95 into P 95, so headers are upper-cased here instead
+	for i := range header {
+		header[i] = strings.ToUpper(header[i])
+	}
+	table := tablewriter.NewTable(w,
+		tablewriter.WithHeaderAutoFormat(tw.Off),
+		tablewriter.WithHeader(header),
+	)
+
+	for _, g := range groups {
+		rate := formatPercent(g.FailureRate())
+		if g.Failures > 0 {
+			rate = paint(rate, theme.Error)
+		}
+		lastFailure := "-"
+		if !g.LastFailureAt.IsZero() {
+			lastFailure = g.LastFailureAt.Format(time.RFC3339)
+		}
+
+		row := make([]string, 0, len(header))
+		for _, value := range g.Values {
+			if value == "" {
+				value = "-"
+			}
+			row = append(row, value)
+		}
+		row = append(row,
+			fmt.Sprintf("%d", g.Deliveries),
+			fmt.Sprintf("%d", g.Failures),
+			rate,
+			fmt.Sprintf("%.2fs", g.Percentile(50)),
+			fmt.Sprintf("%.2fs", g.Percentile(95)),
+			lastFailure,
+		)
+		table.Append(row)
+	}
+
+	table.Render()
+	table.Close()
+}
+
+// FormatBreakdownJSON outputs the summary of every group of a breakdown in JSON format
+// The group values are keyed by the lower-case column names of the dimension, time buckets by their beginning
+func FormatBreakdownJSON(groups []stats.Group, dim stats.Dimension, w io.Writer) error {
+	display := make([]map[string]interface{}, len(groups))
+	for i, g := range groups {
+		entry := map[string]interface{}{
+			"deliveries":   g.Deliveries,
+			"failures":     g.Failures,
+			"failure_rate": g.FailureRate(),
+			"p50_seconds":  g.Percentile(50),
+			"p95_seconds":  g.Percentile(95),
+		}
+		for j, column := range dim.Columns {
+			entry[strings.ToLower(column)] = g.Values[j]
+		}
+		if dim.Period != "" {
+			entry[strings.ToLower(dim.Columns[0])] = g.Start
+		}
+		if !g.LastFailureAt.IsZero() {
+			entry["last_failure_at"] = g.LastFailureAt
+		}
+		display[i] = entry
+	}
+	return encodeJSON(display, w)
+}
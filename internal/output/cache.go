@@ -0,0 +1,39 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ohader/gh-hookmon/internal/cache"
+)
+
+// FormatCacheStats outputs a summary of the on-disk cache
+func FormatCacheStats(s cache.Stats, w io.Writer) {
+	fmt.Fprintf(w, "Directory: %s\n", s.Dir)
+	fmt.Fprintf(w, "Entries:   %d (%d expired)\n", s.Entries, s.Expired)
+	fmt.Fprintf(w, "Size:      %s\n", FormatBytes(s.Size))
+	if s.Oldest != nil {
+		fmt.Fprintf(w, "Oldest:    %s\n", s.Oldest.Local().Format(time.DateTime))
+		fmt.Fprintf(w, "Newest:    %s\n", s.Newest.Local().Format(time.DateTime))
+	}
+}
+
+// FormatCacheStatsJSON outputs a summary of the on-disk cache in JSON format
+func FormatCacheStatsJSON(s cache.Stats, w io.Writer) error {
+	return encodeJSON(s, w)
+}
+
+// FormatBytes formats a size with a binary unit, e.g. 1.5 KiB
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
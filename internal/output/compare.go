@@ -0,0 +1,166 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ohader/gh-hookmon/internal/stats"
+	"github.com/olekukonko/tablewriter"
+	"github.com/olekukonko/tablewriter/tw"
+)
+
+// FormatCompareTable outputs the health of two organizations side by side, followed by their shared target URLs
+// Target URLs of only one organization are listed below, they are the hooks a migration has to move or drop
+func FormatCompareTable(a, b *stats.OrgHealth, w io.Writer) {
+	shared, onlyA, onlyB := stats.CompareEndpoints(a, b)
+
+	table := tablewriter.NewTable(w,
+		tablewriter.WithHeaderAutoFormat(tw.Off),
+		tablewriter.WithHeader([]string{"", a.Org, b.Org}),
+	)
+	rows := [][]string{
+		{"Repositories with webhooks", fmt.Sprintf("%d", a.Repositories), fmt.Sprintf("%d", b.Repositories)},
+		{"Webhooks", formatHookCount(a), formatHookCount(b)},
+		{"Target URLs", fmt.Sprintf("%d", len(a.Endpoints)), fmt.Sprintf("%d", len(b.Endpoints))},
+		{"Deliveries", fmt.Sprintf("%d", a.Deliveries), fmt.Sprintf("%d", b.Deliveries)},
+		{"Failures", fmt.Sprintf("%d", a.Failures), fmt.Sprintf("%d", b.Failures)},
+		{"Failure rate", formatCompareRate(a.Summary), formatCompareRate(b.Summary)},
+		{"Median", formatCompareLatency(a.Summary, 50), formatCompareLatency(b.Summary, 50)},
+		{"P95", formatCompareLatency(a.Summary, 95), formatCompareLatency(b.Summary, 95)},
+	}
+	for _, row := range rows {
+		table.Append(row)
+	}
+	table.Render()
+	table.Close()
+
+	fmt.Fprintln(w)
+	if len(shared) == 0 {
+		fmt.Fprintln(w, "No shared target URLs")
+	} else {
+		fmt.Fprintf(w, "%d shared target URLs:\n", len(shared))
+		header := []string{"URL", a.Org + " hooks", a.Org + " failure rate", b.Org + " hooks", b.Org + " failure rate"}
+		for i := range header {
+			header[i] = strings.ToUpper(header[i])
+		}
+		table := tablewriter.NewTable(w,
+			tablewriter.WithHeaderAutoFormat(tw.Off),
+			tablewriter.WithHeader(header),
+		)
+		for _, e := range shared {
+			table.Append([]string{
+				e.URL,
+				fmt.Sprintf("%d", e.A.Hooks),
+				formatCompareRate(e.A.Summary),
+				fmt.Sprintf("%d", e.B.Hooks),
+				formatCompareRate(e.B.Summary),
+			})
+		}
+		table.Render()
+		table.Close()
+	}
+
+	for _, only := range []struct {
+		org  string
+		urls []string
+	}{{a.Org, onlyA}, {b.Org, onlyB}} {
+		if len(only.urls) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "\nTarget URLs only in %s:\n", only.org)
+		for _, url := range only.urls {
+			fmt.Fprintf(w, "  %s\n", url)
+		}
+	}
+}
+
+// formatHookCount formats the number of webhooks of an organization, e.g. 12 (2 inactive)
+func formatHookCount(h *stats.OrgHealth) string {
+	if h.InactiveHooks == 0 {
+		return fmt.Sprintf("%d", h.Hooks)
+	}
+	return fmt.Sprintf("%d (%d inactive)", h.Hooks, h.InactiveHooks)
+}
+
+// formatCompareRate formats a failure rate in the error color if there were failures, - without deliveries
+func formatCompareRate(s *stats.Summary) string {
+	if s.Deliveries == 0 {
+		return "-"
+	}
+	rate := formatPercent(s.FailureRate())
+	if s.Failures > 0 {
+		rate = paint(rate, theme.Error)
+	}
+	return rate
+}
+
+// formatCompareLatency formats a percentile of the delivery durations, - without deliveries
+func formatCompareLatency(s *stats.Summary, p float64) string {
+	if s.Deliveries == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.2fs", s.Percentile(p))
+}
+
+// FormatCompareJSON outputs the health of two organizations and their shared target URLs in JSON format
+func FormatCompareJSON(a, b *stats.OrgHealth, w io.Writer) error {
+	type jsonOrg struct {
+		Org           string   `json:"org"`
+		Repositories  int      `json:"repositories"`
+		Hooks         int      `json:"hooks"`
+		InactiveHooks int      `json:"inactive_hooks"`
+		TargetURLs    int      `json:"target_urls"`
+		Deliveries    int      `json:"deliveries"`
+		Failures      int      `json:"failures"`
+		FailureRate   float64  `json:"failure_rate"`
+		P50           float64  `json:"p50_seconds"`
+		P95           float64  `json:"p95_seconds"`
+		OnlyURLs      []string `json:"only_urls"` // Target URLs the other organization does not use
+	}
+	type jsonEndpoint struct {
+		Hooks       int     `json:"hooks"`
+		Deliveries  int     `json:"deliveries"`
+		Failures    int     `json:"failures"`
+		FailureRate float64 `json:"failure_rate"`
+	}
+	type jsonShared struct {
+		URL string       `json:"url"`
+		A   jsonEndpoint `json:"a"`
+		B   jsonEndpoint `json:"b"`
+	}
+	type jsonComparison struct {
+		A      jsonOrg      `json:"a"`
+		B      jsonOrg      `json:"b"`
+		Shared []jsonShared `json:"shared_urls"`
+	}
+
+	shared, onlyA, onlyB := stats.CompareEndpoints(a, b)
+	org := func(h *stats.OrgHealth, only []string) jsonOrg {
+		if only == nil {
+			only = []string{}
+		}
+		return jsonOrg{
+			Org:           h.Org,
+			Repositories:  h.Repositories,
+			Hooks:         h.Hooks,
+			InactiveHooks: h.InactiveHooks,
+			TargetURLs:    len(h.Endpoints),
+			Deliveries:    h.Deliveries,
+			Failures:      h.Failures,
+			FailureRate:   h.FailureRate(),
+			P50:           h.Percentile(50),
+			P95:           h.Percentile(95),
+			OnlyURLs:      only,
+		}
+	}
+	endpoint := func(e *stats.EndpointHealth) jsonEndpoint {
+		return jsonEndpoint{Hooks: e.Hooks, Deliveries: e.Deliveries, Failures: e.Failures, FailureRate: e.FailureRate()}
+	}
+
+	display := jsonComparison{A: org(a, onlyA), B: org(b, onlyB), Shared: make([]jsonShared, len(shared))}
+	for i, e := range shared {
+		display.Shared[i] = jsonShared{URL: e.URL, A: endpoint(e.A), B: endpoint(e.B)}
+	}
+	return encodeJSON(display, w)
+}
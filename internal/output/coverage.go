@@ -0,0 +1,104 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/ohader/gh-hookmon/internal/stats"
+)
+
+// FormatCoverage outputs the event coverage of each hook as an indented block
+func FormatCoverage(coverages []stats.EventCoverage, w io.Writer) {
+	if len(coverages) == 0 {
+		fmt.Fprintln(w, "No matching webhooks found")
+		return
+	}
+
+	for i, c := range coverages {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		url := c.Hook.URL
+		if url == "" {
+			url = "-"
+		}
+		fmt.Fprintf(w, "%s hook %d %s\n", c.Hook.Repository, c.Hook.HookID, url)
+		fmt.Fprintf(w, "  subscribed:      %s\n", joinOrDash(c.Subscribed))
+		fmt.Fprintf(w, "  delivered:       %s\n", joinOrDash(formatEventCounts(c.Delivered)))
+		if len(c.NeverDelivered) > 0 {
+			fmt.Fprintf(w, "  never delivered: %s\n", strings.Join(c.NeverDelivered, ", "))
+		}
+		if len(c.Missed) > 0 {
+			fmt.Fprintf(w, "  missed:          %s (repository activity without deliveries)\n", strings.Join(c.Missed, ", "))
+		}
+		if len(c.Unexpected) > 0 {
+			fmt.Fprintf(w, "  unexpected:      %s\n", strings.Join(c.Unexpected, ", "))
+		}
+	}
+}
+
+// FormatCoverageJSON outputs the event coverage of each hook in JSON format
+func FormatCoverageJSON(coverages []stats.EventCoverage, w io.Writer) error {
+	type jsonCoverage struct {
+		Repository     string         `json:"repository"`
+		HookID         int            `json:"hook_id"`
+		URL            string         `json:"url"`
+		Subscribed     []string       `json:"subscribed"`
+		Delivered      map[string]int `json:"delivered"`
+		NeverDelivered []string       `json:"never_delivered"`
+		Missed         []string       `json:"missed"`
+		Unexpected     []string       `json:"unexpected"`
+	}
+
+	display := make([]jsonCoverage, len(coverages))
+	for i, c := range coverages {
+		display[i] = jsonCoverage{
+			Repository:     c.Hook.Repository,
+			HookID:         c.Hook.HookID,
+			URL:            c.Hook.URL,
+			Subscribed:     nonNil(c.Subscribed),
+			Delivered:      c.Delivered,
+			NeverDelivered: nonNil(c.NeverDelivered),
+			Missed:         nonNil(c.Missed),
+			Unexpected:     nonNil(c.Unexpected),
+		}
+	}
+	return encodeJSON(display, w)
+}
+
+// formatEventCounts formats delivery counts per event, most frequent first
+func formatEventCounts(counts map[string]int) []string {
+	events := make([]string, 0, len(counts))
+	for event := range counts {
+		events = append(events, event)
+	}
+	sort.Slice(events, func(i, j int) bool {
+		if counts[events[i]] != counts[events[j]] {
+			return counts[events[i]] > counts[events[j]]
+		}
+		return events[i] < events[j]
+	})
+
+	formatted := make([]string, len(events))
+	for i, event := range events {
+		formatted[i] = fmt.Sprintf("%s (%d)", event, counts[event])
+	}
+	return formatted
+}
+
+func joinOrDash(values []string) string {
+	if len(values) == 0 {
+		return "-"
+	}
+	return strings.Join(values, ", ")
+}
+
+// nonNil makes empty lists encode as [] instead of null
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
@@ -0,0 +1,79 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ohader/gh-hookmon/internal/filter"
+	"github.com/ohader/gh-hookmon/internal/github"
+)
+
+// FormatDetailText outputs a delivery with its request and response headers and the response body
+func FormatDetailText(detail *github.DeliveryDetail, w io.Writer) {
+	d := prepareDeliveries([]github.Delivery{detail.Delivery})[0]
+
+	fmt.Fprintf(w, "Delivery:     %d (%s)\n", d.ID, d.GUID)
+	fmt.Fprintf(w, "Repository:   %s\n", d.Repository)
+	fmt.Fprintf(w, "Hook:         %d\n", d.HookID)
+	if d.URL != "" {
+		fmt.Fprintf(w, "URL:          %s\n", d.URL)
+	}
+	delivered := d.DeliveredAt.Format(time.RFC3339)
+	if d.Redelivery {
+		delivered += " (redelivery)"
+	}
+	fmt.Fprintf(w, "Delivered:    %s\n", delivered)
+	event := d.Event
+	if d.Action != "" {
+		event += "." + d.Action
+	}
+	fmt.Fprintf(w, "Event:        %s\n", event)
+	status := fmt.Sprintf("%d %s", d.StatusCode, d.Status)
+	if d.ErrorClass != "" {
+		status += fmt.Sprintf(" (%s)", d.ErrorClass)
+	}
+	fmt.Fprintf(w, "Status:       %s\n", colorize(status, !filter.IsFailed(d.StatusCode)))
+	fmt.Fprintf(w, "Duration:     %.2fs\n", d.Duration)
+
+	writeHeaders(w, "Request headers", detail.Request.Headers)
+	writeHeaders(w, "Response headers", detail.Response.Headers)
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Response body:")
+	body := strings.TrimRight(detail.Response.Payload, "\n")
+	if body == "" {
+		fmt.Fprintln(w, "  (empty)")
+		return
+	}
+	for _, line := range strings.Split(body, "\n") {
+		fmt.Fprintf(w, "  %s\n", line)
+	}
+}
+
+// FormatDetailJSON outputs a delivery with its request and response in JSON format
+func FormatDetailJSON(detail *github.DeliveryDetail, w io.Writer) error {
+	display := *detail
+	display.Delivery = prepareDeliveries([]github.Delivery{detail.Delivery})[0]
+	return encodeJSON(display, w)
+}
+
+// writeHeaders outputs headers sorted by name under a title
+func writeHeaders(w io.Writer, title string, headers map[string]string) {
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "%s:\n", title)
+	if len(headers) == 0 {
+		fmt.Fprintln(w, "  (none)")
+		return
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %s: %s\n", name, headers[name])
+	}
+}
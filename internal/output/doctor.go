@@ -0,0 +1,55 @@
+package output
+
+import (
+	"fmt"
+	"io"
+)
+
+// Statuses of a doctor check
+const (
+	CheckOK      = "ok"
+	CheckWarning = "warning"
+	CheckFailed  = "failed"
+)
+
+// DoctorCheck is the result of a setup check of the doctor subcommand
+type DoctorCheck struct {
+	Name   string `json:"name"`
+	Status string `json:"status"` // ok, warning or failed
+	Detail string `json:"detail"`
+	Fix    string `json:"fix,omitempty"` // How to resolve a warning or failure
+}
+
+// FormatDoctor outputs doctor checks as one line per check, followed by the fix of warnings and failures
+func FormatDoctor(checks []DoctorCheck, w io.Writer, ascii bool) {
+	for _, c := range checks {
+		fmt.Fprintf(w, "%s %s: %s\n", checkGlyph(c.Status, ascii), c.Name, c.Detail)
+		if c.Fix != "" && c.Status != CheckOK {
+			fmt.Fprintf(w, "  Fix: %s\n", c.Fix)
+		}
+	}
+}
+
+// FormatDoctorJSON outputs doctor checks in JSON format
+func FormatDoctorJSON(checks []DoctorCheck, w io.Writer) error {
+	if checks == nil {
+		checks = []DoctorCheck{}
+	}
+	return encodeJSON(checks, w)
+}
+
+// checkGlyph renders the status of a check in the colors of delivery statuses
+func checkGlyph(status string, ascii bool) string {
+	glyphs := [3]string{"✓", "⚠", "✗"}
+	if ascii {
+		glyphs = [3]string{"+", "!", "x"}
+	}
+	switch status {
+	case CheckOK:
+		return paint(glyphs[0], theme.Success)
+	case CheckWarning:
+		return paint(glyphs[1], theme.Warning)
+	default:
+		return paint(glyphs[2], theme.Error)
+	}
+}
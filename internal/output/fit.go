@@ -0,0 +1,112 @@
+package output
+
+import (
+	"regexp"
+	"unicode/utf8"
+)
+
+// Columns of the delivery table that are dropped first when the table does not fit the terminal
+var droppableColumns = []string{"Hook ID", "Action", "Size"}
+
+// Columns of the delivery table that are shortened next, down to their minimum width
+var shrinkableColumns = []struct {
+	name     string
+	minWidth int
+}{
+	{"URL", 20},
+	{"Response", 20},
+}
+
+// Columns dropped as a last resort, their information is also part of the status
+var lastResortColumns = []string{"Code", "Class"}
+
+// ansiPattern matches the color escape sequences of table cells
+var ansiPattern = regexp.MustCompile("\033\\[[0-9;]*m")
+
+// fitColumns drops and shrinks low-priority columns until the table fits into width characters
+// A width of 0 or less keeps all columns, a table that still does not fit is wrapped by the terminal
+func fitColumns(header []string, rows [][]string, width int) ([]string, [][]string) {
+	if width <= 0 {
+		return header, rows
+	}
+
+	widths := make([]int, len(header))
+	for i, h := range header {
+		widths[i] = cellWidth(h)
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], cellWidth(cell))
+		}
+	}
+	// Every column is padded by a space on both sides and followed by a border
+	excess := func() int {
+		total := 1
+		for _, w := range widths {
+			total += w + 3
+		}
+		return total - width
+	}
+	drop := func(name string) {
+		i := indexOf(header, name)
+		if i < 0 {
+			return
+		}
+		header = append(header[:i:i], header[i+1:]...)
+		widths = append(widths[:i:i], widths[i+1:]...)
+		for r, row := range rows {
+			rows[r] = append(row[:i:i], row[i+1:]...)
+		}
+	}
+
+	for _, name := range droppableColumns {
+		if excess() <= 0 {
+			return header, rows
+		}
+		drop(name)
+	}
+	for _, column := range shrinkableColumns {
+		i := indexOf(header, column.name)
+		over := excess()
+		if over <= 0 {
+			return header, rows
+		}
+		if i < 0 || widths[i] <= column.minWidth {
+			continue
+		}
+		widths[i] = max(widths[i]-over, column.minWidth)
+		for _, row := range rows {
+			row[i] = truncateCell(row[i], widths[i])
+		}
+	}
+	for _, name := range lastResortColumns {
+		if excess() <= 0 {
+			return header, rows
+		}
+		drop(name)
+	}
+	return header, rows
+}
+
+// cellWidth returns the number of characters of a cell as shown in the terminal
+func cellWidth(cell string) int {
+	return utf8.RuneCountInString(ansiPattern.ReplaceAllString(cell, ""))
+}
+
+// truncateCell shortens a cell without color to n characters, ending with "..."
+func truncateCell(cell string, n int) string {
+	if utf8.RuneCountInString(cell) <= n {
+		return cell
+	}
+	runes := []rune(cell)
+	return string(runes[:n-3]) + "..."
+}
+
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,146 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/ohader/gh-hookmon/internal/github"
+)
+
+// flatDelivery is a delivery of --flatten output, with the repository and hook the nested layout leaves out
+type flatDelivery struct {
+	Repository string `json:"repository"`
+	HookID     int    `json:"hook_id"`
+	github.Delivery
+}
+
+// flattenDeliveries renders deliveries as flat JSON objects with snake_case keys only
+// Extracted payload fields become top-level field_<path> keys in the order of fields, e.g. field_pull_request_head_sha,
+// objects and arrays among their values are encoded as JSON strings, so every value is a scalar
+func flattenDeliveries(deliveries []github.Delivery, fields []string) ([]json.RawMessage, error) {
+	prepared := prepareDeliveries(deliveries)
+	result := make([]json.RawMessage, len(prepared))
+	for i, d := range prepared {
+		data, err := flattenDelivery(d, fields)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = data
+	}
+	return result, nil
+}
+
+// flattenDelivery encodes a single delivery as a flat JSON object
+func flattenDelivery(d github.Delivery, fields []string) (json.RawMessage, error) {
+	values := d.Fields
+	d.Fields = nil
+	data, err := json.Marshal(flatDelivery{Repository: d.Repository, HookID: d.HookID, Delivery: d})
+	if err != nil || len(values) == 0 {
+		return data, err
+	}
+
+	// Fields of --extract first, in their order, fields without a path of --extract after them
+	paths := append([]string(nil), fields...)
+	var others []string
+	for path := range values {
+		if !containsString(fields, path) {
+			others = append(others, path)
+		}
+	}
+	sort.Strings(others)
+	paths = append(paths, others...)
+
+	var b bytes.Buffer
+	b.Write(data[:len(data)-1])
+	for _, path := range paths {
+		value, ok := values[path]
+		if !ok {
+			continue
+		}
+		switch value.(type) {
+		case map[string]interface{}, []interface{}:
+			value = fieldText(value)
+		}
+		key, err := json.Marshal(flatFieldKey(path))
+		if err != nil {
+			return nil, err
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		b.WriteByte(',')
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(encoded)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// flatFieldKey returns the snake_case key of an extracted payload field, e.g. field_pull_request_head_sha
+func flatFieldKey(path string) string {
+	var b strings.Builder
+	b.WriteString("field_")
+	underscore := false
+	for _, r := range strings.ToLower(path) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+			underscore = false
+		} else if !underscore {
+			b.WriteByte('_')
+			underscore = true
+		}
+	}
+	return strings.TrimRight(b.String(), "_")
+}
+
+// containsString reports whether values contains s
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// FormatFlatJSON outputs deliveries as a JSON array of flat objects, see flattenDeliveries
+func FormatFlatJSON(deliveries []github.Delivery, w io.Writer, fields []string) error {
+	flat, err := flattenDeliveries(deliveries, fields)
+	if err != nil {
+		return err
+	}
+	return encodeJSON(flat, w)
+}
+
+// FormatFlatNDJSON outputs one flat JSON object per delivery and line
+func FormatFlatNDJSON(deliveries []github.Delivery, w io.Writer, fields []string) error {
+	flat, err := flattenDeliveries(deliveries, fields)
+	if err != nil {
+		return err
+	}
+	for _, d := range flat {
+		if _, err := w.Write(append(d, '\n')); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FormatFlatYAML outputs deliveries as a YAML list of flat mappings
+func FormatFlatYAML(deliveries []github.Delivery, w io.Writer, fields []string) error {
+	flat, err := flattenDeliveries(deliveries, fields)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(flat)
+	if err != nil {
+		return err
+	}
+	return writeYAML(data, w)
+}
@@ -0,0 +1,224 @@
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ohader/gh-hookmon/internal/github"
+	"gopkg.in/yaml.v3"
+)
+
+// Format is an output format of delivery lists
+type Format struct {
+	Name        string // Name used with --output, e.g. csv
+	Description string
+	Write       func(deliveries []github.Delivery, w io.Writer, opts TableOptions) error
+}
+
+// Formats lists the output formats of delivery lists, the first one is the default
+var Formats = []Format{
+	{
+		Name:        "table",
+		Description: "Human-readable table with color-coded status",
+		Write: func(deliveries []github.Delivery, w io.Writer, opts TableOptions) error {
+			FormatTable(deliveries, w, opts)
+			return nil
+		},
+	},
+	{
+		Name:        "json",
+		Description: "JSON array of deliveries",
+		Write: func(deliveries []github.Delivery, w io.Writer, opts TableOptions) error {
+			if opts.Flatten {
+				return FormatFlatJSON(deliveries, w, opts.Fields)
+			}
+			return FormatJSON(deliveries, w)
+		},
+	},
+	{
+		Name:        "ndjson",
+		Description: "One JSON object per delivery and line, for streaming into other tools",
+		Write: func(deliveries []github.Delivery, w io.Writer, opts TableOptions) error {
+			if opts.Flatten {
+				return FormatFlatNDJSON(deliveries, w, opts.Fields)
+			}
+			return FormatNDJSON(deliveries, w)
+		},
+	},
+	{
+		Name:        "yaml",
+		Description: "YAML list of deliveries with the fields of the JSON output",
+		Write: func(deliveries []github.Delivery, w io.Writer, opts TableOptions) error {
+			if opts.Flatten {
+				return FormatFlatYAML(deliveries, w, opts.Fields)
+			}
+			return FormatYAML(deliveries, w)
+		},
+	},
+	{
+		Name:        "csv",
+		Description: "CSV with a header row, for spreadsheets",
+		Write: func(deliveries []github.Delivery, w io.Writer, opts TableOptions) error {
+			return FormatCSV(deliveries, w, opts.Fields)
+		},
+	},
+	{
+		Name:        "markdown",
+		Description: "Markdown table, e.g. for issues and pull requests",
+		Write: func(deliveries []github.Delivery, w io.Writer, opts TableOptions) error {
+			return FormatMarkdown(deliveries, w, opts.Fields)
+		},
+	},
+	{
+		Name:        "guids",
+		Description: "One delivery GUID per line",
+		Write: func(deliveries []github.Delivery, w io.Writer, _ TableOptions) error {
+			return FormatGUIDs(deliveries, w)
+		},
+	},
+	{
+		Name:        "ids",
+		Description: "One delivery ID per line",
+		Write: func(deliveries []github.Delivery, w io.Writer, _ TableOptions) error {
+			return FormatIDs(deliveries, w)
+		},
+	},
+}
+
+// FormatByName returns the output format with the given name
+func FormatByName(name string) (Format, bool) {
+	for _, f := range Formats {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return Format{}, false
+}
+
+// FormatNames returns the names of all output formats
+func FormatNames() []string {
+	names := make([]string, len(Formats))
+	for i, f := range Formats {
+		names[i] = f.Name
+	}
+	return names
+}
+
+// FormatNDJSON outputs one JSON object per delivery and line
+func FormatNDJSON(deliveries []github.Delivery, w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	for _, d := range prepareDeliveries(deliveries) {
+		if err := encoder.Encode(d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FormatYAML outputs deliveries as a YAML list with the keys and key order of the JSON output
+func FormatYAML(deliveries []github.Delivery, w io.Writer) error {
+	data, err := json.Marshal(prepareDeliveries(deliveries))
+	if err != nil {
+		return err
+	}
+	return writeYAML(data, w)
+}
+
+// writeYAML converts JSON data to YAML, keeping the key order
+func writeYAML(data []byte, w io.Writer) error {
+	// JSON is valid YAML, decoding it into a node keeps the key order
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return err
+	}
+	resetStyle(&node)
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// resetStyle switches a node decoded from JSON to block style and unquoted strings where possible
+func resetStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetStyle(child)
+	}
+}
+
+// csvColumns are the columns of CSV and Markdown output, followed by the extracted payload fields
+var csvColumns = []string{
+	"id", "guid", "repository", "hook_id", "delivered_at", "status", "status_code", "error_class",
+	"event", "action", "url", "duration", "redelivery", "installation_id", "response_excerpt",
+}
+
+// FormatCSV outputs deliveries as CSV with a header row
+func FormatCSV(deliveries []github.Delivery, w io.Writer, fields []string) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(append(csvColumns[:len(csvColumns):len(csvColumns)], fields...)); err != nil {
+		return err
+	}
+	for _, d := range prepareDeliveries(deliveries) {
+		if err := writer.Write(csvRecord(d, fields)); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// FormatMarkdown outputs deliveries as a Markdown table
+func FormatMarkdown(deliveries []github.Delivery, w io.Writer, fields []string) error {
+	columns := append(csvColumns[:len(csvColumns):len(csvColumns)], fields...)
+	var b bytes.Buffer
+	b.WriteString("| " + strings.Join(columns, " | ") + " |\n")
+	b.WriteString("|" + strings.Repeat("---|", len(columns)) + "\n")
+	for _, d := range prepareDeliveries(deliveries) {
+		cells := csvRecord(d, fields)
+		for i, cell := range cells {
+			cell = strings.Join(strings.Fields(cell), " ")
+			cells[i] = strings.ReplaceAll(cell, "|", "\\|")
+		}
+		b.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// csvRecord returns the values of a delivery in the order of csvColumns, followed by the extracted payload fields
+func csvRecord(d github.Delivery, fields []string) []string {
+	installation := ""
+	if d.InstallationID != nil {
+		installation = strconv.Itoa(*d.InstallationID)
+	}
+	record := []string{
+		strconv.Itoa(d.ID),
+		d.GUID,
+		d.Repository,
+		strconv.Itoa(d.HookID),
+		d.DeliveredAt.Format(time.RFC3339),
+		d.Status,
+		strconv.Itoa(d.StatusCode),
+		d.ErrorClass,
+		d.Event,
+		d.Action,
+		d.URL,
+		fmt.Sprintf("%.2f", d.Duration),
+		strconv.FormatBool(d.Redelivery),
+		installation,
+		d.ResponseExcerpt,
+	}
+	for _, path := range fields {
+		record = append(record, fieldText(d.Fields[path]))
+	}
+	return record
+}
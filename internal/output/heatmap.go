@@ -0,0 +1,141 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/ohader/gh-hookmon/internal/stats"
+)
+
+// HeatmapOptions configures the heatmap view
+type HeatmapOptions struct {
+	Metric        stats.HeatmapMetric
+	MinDeliveries int    // Failure rates of cells with fewer deliveries are not shaded
+	Window        string // Time window the deliveries were taken from, e.g. 7d
+	ASCII         bool   // Use ASCII shades, e.g. for terminals without UTF-8
+}
+
+// heatmapShades are the glyphs of the four intensity levels, from the lowest quarter of the peak to the peak
+var (
+	heatmapShades      = [4]string{"░", "▒", "▓", "█"}
+	heatmapASCIIShades = [4]string{".", ":", "*", "#"}
+)
+
+// FormatHeatmap outputs the heatmap as a grid of weekdays and hours, shaded relative to the peak cell
+// Cells without deliveries are left blank, cells with deliveries but no failures are dotted for --metric=failure-rate
+func FormatHeatmap(h *stats.Heatmap, opts HeatmapOptions, w io.Writer) {
+	day, hour, ok := h.Peak(opts.Metric, opts.MinDeliveries)
+	if !ok && opts.Metric == stats.HeatmapVolume {
+		fmt.Fprintf(w, "No deliveries found in the last %s\n", opts.Window)
+		return
+	}
+
+	shades, empty := heatmapShades, "·"
+	if opts.ASCII {
+		shades, empty = heatmapASCIIShades, "-"
+	}
+	peak := h.Value(day, hour, opts.Metric, opts.MinDeliveries)
+
+	title := "Deliveries"
+	if opts.Metric == stats.HeatmapFailureRate {
+		title = "Failure rate"
+	}
+	fmt.Fprintf(w, "%s per weekday and hour (%s), last %s\n\n", title, zoneName(h.Location), opts.Window)
+
+	fmt.Fprint(w, "    ")
+	for hr := 0; hr < 24; hr++ {
+		fmt.Fprintf(w, " %02d", hr)
+	}
+	fmt.Fprintln(w)
+
+	for d, weekday := range stats.HeatmapWeekdays {
+		fmt.Fprintf(w, "%s ", weekday.String()[:3])
+		for hr := 0; hr < 24; hr++ {
+			cell := h.Cells[d][hr]
+			value := h.Value(d, hr, opts.Metric, opts.MinDeliveries)
+			switch {
+			case cell.Deliveries == 0:
+				fmt.Fprint(w, "   ")
+			case value == 0:
+				fmt.Fprintf(w, " %s", strings.Repeat(empty, 2))
+			default:
+				level := min(int(math.Ceil(value/peak*4))-1, 3)
+				glyphs := strings.Repeat(shades[level], 2)
+				if opts.Metric == stats.HeatmapFailureRate {
+					glyphs = paint(glyphs, theme.Error)
+				}
+				fmt.Fprintf(w, " %s", glyphs)
+			}
+		}
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintln(w)
+
+	if !ok {
+		fmt.Fprintf(w, "No failed deliveries in the last %s\n", opts.Window)
+		return
+	}
+	legend := make([]string, len(shades))
+	for i, shade := range shades {
+		legend[i] = fmt.Sprintf("%s up to %s", shade, formatHeatmapValue(peak*float64(i+1)/4, opts.Metric))
+	}
+	fmt.Fprintf(w, "Legend: %s\n", strings.Join(legend, "  "))
+
+	peakCell := h.Cells[day][hour]
+	if opts.Metric == stats.HeatmapFailureRate {
+		fmt.Fprintf(w, "Peak: %s %02d:00 with %s of %d deliveries failed\n",
+			stats.HeatmapWeekdays[day].String()[:3], hour, formatPercent(peakCell.FailureRate()), peakCell.Deliveries)
+		return
+	}
+	fmt.Fprintf(w, "Peak: %s %02d:00 with %d deliveries, %d failed\n",
+		stats.HeatmapWeekdays[day].String()[:3], hour, peakCell.Deliveries, peakCell.Failures)
+}
+
+// formatHeatmapValue formats a legend bound, volumes are rounded up to whole deliveries
+func formatHeatmapValue(value float64, metric stats.HeatmapMetric) string {
+	if metric == stats.HeatmapFailureRate {
+		return formatPercent(value)
+	}
+	return fmt.Sprintf("%d", int(math.Ceil(value)))
+}
+
+// zoneName names a time zone, the local zone by its current abbreviation, e.g. CET
+func zoneName(loc *time.Location) string {
+	if loc == time.Local {
+		name, _ := time.Now().In(loc).Zone()
+		return name
+	}
+	return loc.String()
+}
+
+// FormatHeatmapJSON outputs every cell of the heatmap in JSON format, Monday 00:00 first
+func FormatHeatmapJSON(h *stats.Heatmap, w io.Writer) error {
+	type jsonCell struct {
+		Weekday     string  `json:"weekday"`
+		Hour        int     `json:"hour"`
+		Deliveries  int     `json:"deliveries"`
+		Failures    int     `json:"failures"`
+		FailureRate float64 `json:"failure_rate"`
+	}
+	type jsonHeatmap struct {
+		TimeZone string     `json:"time_zone"`
+		Cells    []jsonCell `json:"cells"`
+	}
+
+	display := jsonHeatmap{TimeZone: zoneName(h.Location), Cells: make([]jsonCell, 0, 7*24)}
+	for d, weekday := range stats.HeatmapWeekdays {
+		for hr, cell := range h.Cells[d] {
+			display.Cells = append(display.Cells, jsonCell{
+				Weekday:     weekday.String(),
+				Hour:        hr,
+				Deliveries:  cell.Deliveries,
+				Failures:    cell.Failures,
+				FailureRate: cell.FailureRate(),
+			})
+		}
+	}
+	return encodeJSON(display, w)
+}
@@ -0,0 +1,27 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ohader/gh-hookmon/internal/history"
+)
+
+// FormatHistoryStats outputs a summary of the history database
+func FormatHistoryStats(s history.Stats, w io.Writer) {
+	fmt.Fprintf(w, "Database:     %s\n", s.Path)
+	fmt.Fprintf(w, "Size:         %s\n", FormatBytes(s.Size))
+	fmt.Fprintf(w, "Deliveries:   %d\n", s.Deliveries)
+	fmt.Fprintf(w, "Repositories: %d\n", s.Repositories)
+	fmt.Fprintf(w, "Hooks:        %d\n", s.Hooks)
+	if s.Oldest != nil {
+		fmt.Fprintf(w, "Oldest:       %s\n", s.Oldest.Local().Format(time.DateTime))
+		fmt.Fprintf(w, "Newest:       %s\n", s.Newest.Local().Format(time.DateTime))
+	}
+}
+
+// FormatHistoryStatsJSON outputs a summary of the history database in JSON format
+func FormatHistoryStatsJSON(s history.Stats, w io.Writer) error {
+	return encodeJSON(s, w)
+}
@@ -0,0 +1,90 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ohader/gh-hookmon/internal/github"
+	"github.com/olekukonko/tablewriter"
+)
+
+// FormatHooksTable outputs webhooks as an ASCII table
+func FormatHooksTable(hooks []github.RepoHook, w io.Writer) {
+	if len(hooks) == 0 {
+		fmt.Fprintln(w, "No matching webhooks found")
+		return
+	}
+
+	table := tablewriter.NewTable(w,
+		tablewriter.WithHeader([]string{
+			"Repository",
+			"Hook ID",
+			"Active",
+			"Events",
+			"Content Type",
+			"Secret",
+			"URL",
+		}),
+	)
+
+	for _, h := range hooks {
+		active := paint("yes", theme.Success)
+		if !h.Active {
+			active = paint("no", theme.Warning)
+		}
+
+		secret := "no"
+		if h.HasSecret() {
+			secret = "yes"
+		}
+
+		events := strings.Join(h.Events, ", ")
+		if events == "" {
+			events = "-"
+		}
+
+		url := h.GetTargetURL()
+		if url == "" {
+			url = "-"
+		}
+
+		table.Append([]string{
+			h.Repository,
+			fmt.Sprintf("%d", h.ID),
+			active,
+			events,
+			h.GetContentType(),
+			secret,
+			url,
+		})
+	}
+
+	table.Render()
+	table.Close()
+}
+
+// FormatHooksJSON outputs webhooks in JSON format
+func FormatHooksJSON(hooks []github.RepoHook, w io.Writer) error {
+	type jsonHook struct {
+		Repository string   `json:"repository"`
+		ID         int      `json:"id"`
+		Active     bool     `json:"active"`
+		Events     []string `json:"events"`
+		URL        string   `json:"url"`
+		HasSecret  bool     `json:"has_secret"`
+	}
+
+	display := make([]jsonHook, len(hooks))
+	for i, h := range hooks {
+		display[i] = jsonHook{
+			Repository: h.Repository,
+			ID:         h.ID,
+			Active:     h.Active,
+			Events:     h.Events,
+			URL:        h.GetTargetURL(),
+			HasSecret:  h.HasSecret(),
+		}
+	}
+	return encodeJSON(display, w)
+}
@@ -0,0 +1,28 @@
+package output
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/ohader/gh-hookmon/internal/github"
+)
+
+// FormatGUIDs outputs one delivery GUID per line, suitable for piping into other commands
+func FormatGUIDs(deliveries []github.Delivery, w io.Writer) error {
+	for _, d := range deliveries {
+		if _, err := fmt.Fprintln(w, d.GUID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FormatIDs outputs one delivery ID per line, suitable for piping into other commands
+func FormatIDs(deliveries []github.Delivery, w io.Writer) error {
+	for _, d := range deliveries {
+		if _, err := fmt.Fprintln(w, d.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
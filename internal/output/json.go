@@ -1,25 +1,129 @@
 package output
 
 import (
+	_ "embed"
 	"encoding/json"
 	"io"
 
+	"github.com/ohader/gh-hookmon/internal/filter"
 	"github.com/ohader/gh-hookmon/internal/github"
 )
 
+// FormatVersion is the latest version of the JSON output
+// It is increased only when existing fields are renamed, removed or change their meaning, not when fields are added,
+// older versions stay available with --format-version so pipelines can migrate at their own pace
+const FormatVersion = 1
+
+// SupportsFormatVersion checks if the JSON output can be rendered in version v
+func SupportsFormatVersion(v int) bool {
+	return v >= 1 && v <= FormatVersion
+}
+
+// OutputSchema is the published JSON Schema of the JSON output of delivery listings
+// It must be kept in line with Delivery, Issue, Report and the summary rows
+//
+//go:embed output-schema.json
+var OutputSchema []byte
+
+// Kinds of issues, stable identifiers for automation deciding whether to trust a result
+const (
+	IssueRepositoryFailed  = "repository_failed"  // A repository could not be scanned, its deliveries are missing
+	IssueRepositoryTimeout = "repository_timeout" // A repository exceeded --repo-timeout, its deliveries are missing
+	IssueForbidden         = "forbidden"          // The token may not read a repository's webhooks (403 or 404)
+	IssueHookFailed        = "hook_failed"        // The deliveries of a hook could not be listed
+	IssueDetailFailed      = "detail_failed"      // A delivery detail could not be fetched, the delivery is dropped
+	IssueTruncated         = "truncated"          // A hook has more deliveries than --limit fetched
+	IssueDeadline          = "deadline"           // --deadline passed before all work was done
+	IssueRateLimited       = "rate_limited"       // A secondary rate limit paused the requests and lowered concurrency
+	IssueRateLimitBudget   = "rate_limit_budget"  // The scan is projected to use up the API budget
+	IssueStaleCache        = "stale_cache"        // Cached data older than --cache-ttl was used
+	IssueActivityFailed    = "activity_failed"    // The repository events of coverage --activity could not be listed
+	IssueHistoryFailed     = "history_failed"     // Deliveries could not be recorded in the history database
+)
+
+// Issue describes a non-fatal problem encountered while collecting deliveries
+type Issue struct {
+	Kind       string `json:"kind"` // One of the Issue* kinds
+	Repository string `json:"repository,omitempty"`
+	HookID     int    `json:"hook_id,omitempty"`
+	DeliveryID int    `json:"delivery_id,omitempty"`
+	Message    string `json:"message"`
+}
+
+// Report wraps deliveries together with the problems encountered while collecting them
+// Errors are failed API operations whose data is missing from the result,
+// warnings are conditions that may make the result incomplete,
+// skipped are repositories the token may not read, listed with --on-forbidden=report
+type Report struct {
+	FormatVersion int               `json:"format_version"` // Version of the JSON output, see FormatVersion
+	Deliveries    []github.Delivery `json:"deliveries"`
+	Warnings      []Issue           `json:"warnings"`
+	Errors        []Issue           `json:"errors"`
+	Skipped       []Issue           `json:"skipped"`
+}
+
 // FormatJSON outputs deliveries in JSON format
 func FormatJSON(deliveries []github.Delivery, w io.Writer) error {
-	// Transform deliveries for display
+	return encodeJSON(prepareDeliveries(deliveries), w)
+}
+
+// FormatJSONReport outputs deliveries wrapped in an envelope with warnings and errors
+// With opts.Flatten the deliveries are flat objects, see flattenDeliveries
+func FormatJSONReport(report Report, w io.Writer, opts TableOptions) error {
+	report.Deliveries = prepareDeliveries(report.Deliveries)
+	if report.FormatVersion == 0 {
+		report.FormatVersion = FormatVersion
+	}
+	// Always emit arrays, never null, so consumers can rely on the shape
+	if report.Warnings == nil {
+		report.Warnings = []Issue{}
+	}
+	if report.Errors == nil {
+		report.Errors = []Issue{}
+	}
+	if report.Skipped == nil {
+		report.Skipped = []Issue{}
+	}
+	if !opts.Flatten {
+		return encodeJSON(report, w)
+	}
+
+	type flatReport struct {
+		FormatVersion int               `json:"format_version"`
+		Deliveries    []json.RawMessage `json:"deliveries"`
+		Warnings      []Issue           `json:"warnings"`
+		Errors        []Issue           `json:"errors"`
+		Skipped       []Issue           `json:"skipped"`
+	}
+	deliveries, err := flattenDeliveries(report.Deliveries, opts.Fields)
+	if err != nil {
+		return err
+	}
+	return encodeJSON(flatReport{
+		FormatVersion: report.FormatVersion,
+		Deliveries:    deliveries,
+		Warnings:      report.Warnings,
+		Errors:        report.Errors,
+		Skipped:       report.Skipped,
+	}, w)
+}
+
+// prepareDeliveries transforms deliveries for display
+func prepareDeliveries(deliveries []github.Delivery) []github.Delivery {
 	displayDeliveries := make([]github.Delivery, len(deliveries))
 	for i, d := range deliveries {
 		displayDeliveries[i] = d
+		displayDeliveries[i].ErrorClass = filter.Classify(d.StatusCode, d.Status)
 		// Handle status code 0 specially
 		if d.StatusCode == 0 && d.Status == "" {
 			displayDeliveries[i].Status = "delivery failed"
 		}
 	}
+	return displayDeliveries
+}
 
+func encodeJSON(v interface{}, w io.Writer) error {
 	encoder := json.NewEncoder(w)
 	encoder.SetIndent("", "  ")
-	return encoder.Encode(displayDeliveries)
+	return encoder.Encode(v)
 }
@@ -0,0 +1,91 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ohader/gh-hookmon/internal/probe"
+	"github.com/olekukonko/tablewriter"
+)
+
+// ProbeRow is the probe result of a target URL together with its delivery history
+type ProbeRow struct {
+	probe.Result
+	Hooks      int `json:"hooks"`
+	Deliveries int `json:"deliveries"`
+	Failures   int `json:"failures"`
+}
+
+// FormatProbeTable outputs endpoint probe results as an ASCII table
+func FormatProbeTable(rows []ProbeRow, w io.Writer) {
+	if len(rows) == 0 {
+		fmt.Fprintln(w, "No webhook target URLs found")
+		return
+	}
+
+	table := tablewriter.NewTable(w,
+		tablewriter.WithHeader([]string{
+			"URL",
+			"Hooks",
+			"Failed",
+			"DNS",
+			"Connect",
+			"TLS",
+			"HTTP",
+		}),
+	)
+
+	for _, r := range rows {
+		httpStatus := stageStatus(r.HTTP)
+		if r.StatusCode != 0 {
+			httpStatus = colorize(fmt.Sprintf("%d", r.StatusCode), true)
+		}
+
+		tlsStatus := stageStatus(r.TLS)
+		if r.CertExpiry != nil {
+			tlsStatus = colorize(fmt.Sprintf("ok (expires %s)", r.CertExpiry.Format(time.DateOnly)), true)
+		}
+
+		table.Append([]string{
+			r.URL,
+			fmt.Sprintf("%d", r.Hooks),
+			fmt.Sprintf("%d/%d", r.Failures, r.Deliveries),
+			stageStatus(r.DNS),
+			stageStatus(r.Connect),
+			tlsStatus,
+			httpStatus,
+		})
+	}
+
+	table.Render()
+	table.Close()
+}
+
+// FormatProbeJSON outputs endpoint probe results in JSON format
+func FormatProbeJSON(rows []ProbeRow, w io.Writer) error {
+	if rows == nil {
+		rows = []ProbeRow{}
+	}
+	return encodeJSON(rows, w)
+}
+
+// stageStatus renders the status of a probe stage, skipped stages are shown as "-"
+func stageStatus(status string) string {
+	switch status {
+	case "", "-":
+		return "-"
+	case probe.StatusOK:
+		return colorize(status, true)
+	default:
+		return colorize(status, false)
+	}
+}
+
+// colorize renders text in the success color if ok, in the error color otherwise
+func colorize(text string, ok bool) string {
+	if ok {
+		return paint(text, theme.Success)
+	}
+	return paint(text, theme.Error)
+}
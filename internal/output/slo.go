@@ -0,0 +1,104 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ohader/gh-hookmon/internal/stats"
+	"github.com/olekukonko/tablewriter"
+)
+
+// FormatSLOTable outputs SLO evaluation results as an ASCII table
+func FormatSLOTable(results []stats.SLOResult, w io.Writer) {
+	if len(results) == 0 {
+		fmt.Fprintln(w, "No SLOs defined in the config file")
+		return
+	}
+
+	table := tablewriter.NewTable(w,
+		tablewriter.WithHeader([]string{
+			"SLO",
+			"Window",
+			"Hooks",
+			"Deliveries",
+			"Availability",
+			"Target",
+			"Budget Left",
+			"Burn Rate",
+			"Latency",
+			"Status",
+		}),
+	)
+
+	for _, r := range results {
+		target := "-"
+		budget := "-"
+		burnRate := "-"
+		if r.AvailabilityTarget > 0 {
+			target = formatPercent(r.AvailabilityTarget)
+			budget = formatPercent(r.BudgetRemaining)
+			burnRate = fmt.Sprintf("%.2fx", r.BurnRate)
+		}
+
+		latency := "-"
+		if r.LatencyThreshold > 0 {
+			latency = fmt.Sprintf("%s within %s (target %s)", formatPercent(r.WithinLatency), r.LatencyThreshold, formatPercent(r.LatencyTarget))
+		}
+
+		status := paint("met", theme.Success)
+		if !r.Met {
+			status = paint("breached", theme.Error)
+		}
+
+		table.Append([]string{
+			r.Name,
+			formatWindow(r.Window),
+			fmt.Sprintf("%d", r.Hooks),
+			fmt.Sprintf("%d", r.Deliveries),
+			formatPercent(r.Availability),
+			target,
+			budget,
+			burnRate,
+			latency,
+			status,
+		})
+	}
+
+	table.Render()
+	table.Close()
+}
+
+// FormatSLOJSON outputs SLO evaluation results in JSON format
+// Durations are given in seconds
+func FormatSLOJSON(results []stats.SLOResult, w io.Writer) error {
+	type jsonResult struct {
+		stats.SLOResult
+		WindowSeconds           float64 `json:"window_seconds"`
+		LatencyThresholdSeconds float64 `json:"latency_threshold_seconds"`
+	}
+
+	display := make([]jsonResult, len(results))
+	for i, r := range results {
+		display[i] = jsonResult{
+			SLOResult:               r,
+			WindowSeconds:           r.Window.Seconds(),
+			LatencyThresholdSeconds: r.LatencyThreshold.Seconds(),
+		}
+	}
+	return encodeJSON(display, w)
+}
+
+// formatPercent formats a fraction as a percentage
+func formatPercent(fraction float64) string {
+	return fmt.Sprintf("%.2f%%", fraction*100)
+}
+
+// formatWindow formats a window in days if it is a whole number of days
+func formatWindow(d time.Duration) string {
+	day := 24 * time.Hour
+	if d >= day && d%day == 0 {
+		return fmt.Sprintf("%dd", d/day)
+	}
+	return d.String()
+}
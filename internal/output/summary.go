@@ -0,0 +1,141 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ohader/gh-hookmon/internal/stats"
+	"github.com/olekukonko/tablewriter"
+	"github.com/olekukonko/tablewriter/tw"
+)
+
+// FormatRepositorySummaryTable outputs one row per repository as an ASCII table
+func FormatRepositorySummaryTable(summaries []stats.RepositorySummary, w io.Writer) {
+	if len(summaries) == 0 {
+		fmt.Fprintln(w, "No matching webhook deliveries found")
+		return
+	}
+
+	table := tablewriter.NewTable(w,
+		tablewriter.WithHeader([]string{"Repository", "Hooks", "Deliveries", "Failures", "Failure Rate", "Last Failure"}),
+	)
+	for _, s := range summaries {
+		rate := formatPercent(s.FailureRate())
+		if s.Failures > 0 {
+			rate = paint(rate, theme.Error)
+		}
+		table.Append([]string{
+			s.Repository,
+			fmt.Sprintf("%d", s.Hooks),
+			fmt.Sprintf("%d", s.Deliveries),
+			fmt.Sprintf("%d", s.Failures),
+			rate,
+			formatLastFailure(s.LastFailureAt),
+		})
+	}
+	table.Render()
+	table.Close()
+}
+
+// FormatRepositorySummaryJSON outputs one entry per repository in JSON format
+func FormatRepositorySummaryJSON(summaries []stats.RepositorySummary, w io.Writer) error {
+	type jsonSummary struct {
+		Repository    string     `json:"repository"`
+		Hooks         int        `json:"hooks"`
+		Deliveries    int        `json:"deliveries"`
+		Failures      int        `json:"failures"`
+		FailureRate   float64    `json:"failure_rate"`
+		LastFailureAt *time.Time `json:"last_failure_at,omitempty"`
+	}
+
+	display := make([]jsonSummary, len(summaries))
+	for i, s := range summaries {
+		display[i] = jsonSummary{
+			Repository:  s.Repository,
+			Hooks:       s.Hooks,
+			Deliveries:  s.Deliveries,
+			Failures:    s.Failures,
+			FailureRate: s.FailureRate(),
+		}
+		if !s.LastFailureAt.IsZero() {
+			lastFailure := s.LastFailureAt
+			display[i].LastFailureAt = &lastFailure
+		}
+	}
+	return encodeJSON(display, w)
+}
+
+// FormatHookSummaryTable outputs one row per hook as an ASCII table
+func FormatHookSummaryTable(summaries []stats.HookSummary, w io.Writer) {
+	if len(summaries) == 0 {
+		fmt.Fprintln(w, "No matching webhook deliveries found")
+		return
+	}
+
+	table := tablewriter.NewTable(w,
+		tablewriter.WithHeaderAutoFormat(tw.Off),
+		tablewriter.WithHeader([]string{"REPOSITORY", "HOOK ID", "URL", "DELIVERIES", "FAILURES", "FAILURE RATE", "P95", "LAST DELIVERY"}),
+	)
+	for _, s := range summaries {
+		url := s.URL
+		if url == "" {
+			url = "-"
+		} else if len(url) > 50 {
+			url = url[:47] + "..."
+		}
+		rate := formatPercent(s.FailureRate())
+		if s.Failures > 0 {
+			rate = paint(rate, theme.Error)
+		}
+		table.Append([]string{
+			s.Repository,
+			fmt.Sprintf("%d", s.HookID),
+			url,
+			fmt.Sprintf("%d", s.Deliveries),
+			fmt.Sprintf("%d", s.Failures),
+			rate,
+			fmt.Sprintf("%.2fs", s.Percentile(95)),
+			s.LastAt.Format(time.RFC3339),
+		})
+	}
+	table.Render()
+	table.Close()
+}
+
+// FormatHookSummaryJSON outputs one entry per hook in JSON format
+func FormatHookSummaryJSON(summaries []stats.HookSummary, w io.Writer) error {
+	type jsonSummary struct {
+		Repository     string    `json:"repository"`
+		HookID         int       `json:"hook_id"`
+		URL            string    `json:"url"`
+		Deliveries     int       `json:"deliveries"`
+		Failures       int       `json:"failures"`
+		FailureRate    float64   `json:"failure_rate"`
+		P95Seconds     float64   `json:"p95_seconds"`
+		LastDeliveryAt time.Time `json:"last_delivery_at"`
+	}
+
+	display := make([]jsonSummary, len(summaries))
+	for i, s := range summaries {
+		display[i] = jsonSummary{
+			Repository:     s.Repository,
+			HookID:         s.HookID,
+			URL:            s.URL,
+			Deliveries:     s.Deliveries,
+			Failures:       s.Failures,
+			FailureRate:    s.FailureRate(),
+			P95Seconds:     s.Percentile(95),
+			LastDeliveryAt: s.LastAt,
+		}
+	}
+	return encodeJSON(display, w)
+}
+
+// formatLastFailure formats the time of the last failure, - if there was none
+func formatLastFailure(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format(time.RFC3339)
+}
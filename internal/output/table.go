@@ -1,52 +1,111 @@
 package output
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/ohader/gh-hookmon/internal/filter"
 	"github.com/ohader/gh-hookmon/internal/github"
 	"github.com/olekukonko/tablewriter"
+	"github.com/olekukonko/tablewriter/tw"
 )
 
+// TableOptions controls the layout of the delivery table
+type TableOptions struct {
+	Width        int      // Drop or shorten low-priority columns until the table fits, 0 keeps all columns
+	Compact      bool     // Show a status glyph and the status code instead of the status text
+	ASCII        bool     // Use ASCII status glyphs, e.g. for terminals without UTF-8
+	RelativeTime bool     // Show timestamps relative to now, e.g. 3m ago, instead of RFC3339
+	Fields       []string // Paths of extracted payload fields, shown as extra columns
+	Details      bool     // Show the payload size and the response summary of detailed deliveries
+	Delta        bool     // Show the difference of the duration to the rolling median of the hook
+	Flatten      bool     // JSON, NDJSON and YAML: flat objects including the repository and hook, see flattenDeliveries
+}
+
 // FormatTable outputs deliveries as an ASCII table
-func FormatTable(deliveries []github.Delivery, w io.Writer) {
+func FormatTable(deliveries []github.Delivery, w io.Writer, opts TableOptions) {
 	if len(deliveries) == 0 {
 		fmt.Fprintln(w, "No matching webhook deliveries found")
 		return
 	}
 
-	table := tablewriter.NewTable(w,
-		tablewriter.WithHeader([]string{
-			"Delivery ID",
-			"Repository",
-			"Hook ID",
-			"Timestamp",
-			"Status",
-			"Code",
-			"Event",
-			"Action",
-			"URL",
-		}),
-	)
-
+	header := []string{
+		"Delivery ID",
+		"Repository",
+		"Hook ID",
+		"Timestamp",
+		"Status",
+		"Code",
+		"Class",
+		"Event",
+		"Action",
+		"URL",
+	}
+	// In compact mode the status column shows the glyph and the code
+	if opts.Compact {
+		header = append(header[:5:5], header[6:]...)
+	}
+	// The response column is only shown if excerpts were fetched or recorded, or with details
+	withExcerpts := opts.Details
+	for _, d := range deliveries {
+		if d.ResponseExcerpt != "" {
+			withExcerpts = true
+			break
+		}
+	}
+	// The attempts column is only shown if an event was redelivered
+	withAttempts := false
+	for _, d := range deliveries {
+		if d.Attempts > 1 {
+			withAttempts = true
+			break
+		}
+	}
+	if withAttempts {
+		header = append(header, "Attempts")
+	}
+	if opts.Delta {
+		header = append(header, "vs Median")
+	}
+	if opts.Details {
+		header = append(header, "Size")
+	}
+	if withExcerpts {
+		header = append(header, "Response")
+	}
+	// The payload column is only shown if payloads were validated
+	withValidation := false
 	for _, d := range deliveries {
-		// Color code status based on HTTP status code
+		if d.PayloadValid != nil {
+			withValidation = true
+			break
+		}
+	}
+	if withValidation {
+		header = append(header, "Payload")
+	}
+	header = append(header, opts.Fields...)
+	now := time.Now()
+	rows := make([][]string, 0, len(deliveries))
+	for _, d := range deliveries {
+		// Color code status based on HTTP status code, see the theme's warning and error codes
 		// Handle status code 0 specially
 		status := d.Status
 		if d.StatusCode == 0 {
 			// Status code 0 means delivery failed (no response)
-			status = "delivery failed"
-			status = fmt.Sprintf("\033[31m%s\033[0m", status) // Red
+			status = paint("delivery failed", statusColor(0))
 		} else if d.Status == "" {
 			// Fallback if status is empty but status code exists
 			status = "-"
-		} else if d.StatusCode >= 200 && d.StatusCode < 300 {
-			status = fmt.Sprintf("\033[32m%s\033[0m", status) // Green
-		} else if d.StatusCode >= 400 {
-			status = fmt.Sprintf("\033[31m%s\033[0m", status) // Red
-		} else if d.StatusCode >= 300 && d.StatusCode < 400 {
-			status = fmt.Sprintf("\033[33m%s\033[0m", status) // Yellow
+		} else {
+			status = paint(status, statusColor(d.StatusCode))
+		}
+		if opts.Compact {
+			status = paint(fmt.Sprintf("%s %d", statusGlyph(d.StatusCode, opts.ASCII), d.StatusCode), statusColor(d.StatusCode))
 		}
 
 		// Truncate long URLs for display
@@ -59,6 +118,14 @@ func FormatTable(deliveries []github.Delivery, w io.Writer) {
 
 		// Format timestamp
 		timestamp := d.DeliveredAt.Format(time.RFC3339)
+		if opts.RelativeTime {
+			timestamp = relativeTime(d.DeliveredAt, now)
+		}
+
+		class := filter.Classify(d.StatusCode, d.Status)
+		if class == "" {
+			class = "-"
+		}
 
 		// Format action (may be empty)
 		action := d.Action
@@ -66,19 +133,182 @@ func FormatTable(deliveries []github.Delivery, w io.Writer) {
 			action = "-"
 		}
 
-		table.Append([]string{
+		// Organization hooks are marked, their deliveries are listed with the repository they belong to
+		hook := fmt.Sprintf("%d", d.HookID)
+		if d.Org != "" {
+			hook += " (org)"
+		}
+
+		row := []string{
 			fmt.Sprintf("%d", d.ID),
 			d.Repository,
-			fmt.Sprintf("%d", d.HookID),
+			hook,
 			timestamp,
 			status,
 			fmt.Sprintf("%d", d.StatusCode),
+			class,
 			d.Event,
 			action,
 			urlDisplay,
-		})
+		}
+		if opts.Compact {
+			row = append(row[:5:5], row[6:]...)
+		}
+		if withAttempts {
+			attempts := "-"
+			if d.Attempts > 1 {
+				attempts = paint(fmt.Sprintf("%d", d.Attempts), theme.Warning)
+			} else if d.Attempts == 1 {
+				attempts = "1"
+			}
+			row = append(row, attempts)
+		}
+		if opts.Delta {
+			row = append(row, formatDelta(d.DurationDelta))
+		}
+		if opts.Details {
+			size := "-"
+			if d.PayloadSize > 0 {
+				size = FormatBytes(int64(d.PayloadSize))
+			}
+			row = append(row, size)
+		}
+		if withExcerpts {
+			// Excerpts of failed deliveries are longer than the summary, so they take precedence
+			excerpt := d.ResponseExcerpt
+			if excerpt == "" {
+				excerpt = d.ResponseSummary
+			}
+			row = append(row, formatExcerpt(excerpt))
+		}
+		if withValidation {
+			row = append(row, formatValidation(d))
+		}
+		for _, path := range opts.Fields {
+			value := fieldText(d.Fields[path])
+			if value == "" {
+				value = "-"
+			}
+			row = append(row, value)
+		}
+		rows = append(rows, row)
 	}
 
+	header, rows = fitColumns(header, rows, opts.Width)
+	// Automatic formatting would split the field paths at dots and underscores, so headers are upper-cased here instead
+	for i := range header {
+		header[i] = strings.ToUpper(header[i])
+	}
+	table := tablewriter.NewTable(w,
+		tablewriter.WithHeaderAutoFormat(tw.Off),
+		tablewriter.WithHeader(header),
+	)
+	for _, row := range rows {
+		table.Append(row)
+	}
 	table.Render()
 	table.Close()
+
+	if withValidation {
+		formatPayloadProblems(deliveries, w)
+	}
+}
+
+// formatDelta formats the difference of a duration to the rolling median, e.g. +340%
+// Deliveries that took at least twice as long as the median are shown in the error color
+func formatDelta(delta *float64) string {
+	if delta == nil {
+		return "-"
+	}
+	text := fmt.Sprintf("%+.0f%%", *delta*100)
+	if *delta >= 1 {
+		return paint(text, theme.Error)
+	}
+	return text
+}
+
+// fieldText returns an extracted payload value as text, objects and arrays as compact JSON
+func fieldText(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	}
+}
+
+// relativeTime returns how long before now t was in its largest whole unit, e.g. 3m ago or 2d ago
+// Times in the future, e.g. due to clock skew, are shown as just now
+func relativeTime(t, now time.Time) string {
+	age := now.Sub(t)
+	switch {
+	case age < time.Second:
+		return "just now"
+	case age < time.Minute:
+		return fmt.Sprintf("%ds ago", int(age/time.Second))
+	case age < time.Hour:
+		return fmt.Sprintf("%dm ago", int(age/time.Minute))
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(age/time.Hour))
+	default:
+		return fmt.Sprintf("%dd ago", int(age/(24*time.Hour)))
+	}
+}
+
+// formatValidation summarizes the payload validation result of a delivery for the table
+func formatValidation(d github.Delivery) string {
+	switch {
+	case d.PayloadValid == nil:
+		return "-"
+	case *d.PayloadValid:
+		return paint("valid", theme.Success)
+	case len(d.PayloadProblems) == 1:
+		return paint("1 problem", theme.Error)
+	default:
+		return paint(fmt.Sprintf("%d problems", len(d.PayloadProblems)), theme.Error)
+	}
+}
+
+// formatPayloadProblems lists the payload problems of all deliveries below the table
+func formatPayloadProblems(deliveries []github.Delivery, w io.Writer) {
+	header := false
+	for _, d := range deliveries {
+		if len(d.PayloadProblems) == 0 {
+			continue
+		}
+		if !header {
+			fmt.Fprintln(w)
+			fmt.Fprintln(w, "Payload problems:")
+			header = true
+		}
+		event := d.Event
+		if d.Action != "" {
+			event += "." + d.Action
+		}
+		for _, problem := range d.PayloadProblems {
+			fmt.Fprintf(w, "  %d (%s): %s\n", d.ID, event, problem)
+		}
+	}
+}
+
+// formatExcerpt shortens a response excerpt to a single line for the table
+func formatExcerpt(excerpt string) string {
+	excerpt = strings.Join(strings.Fields(excerpt), " ")
+	if excerpt == "" {
+		return "-"
+	}
+	if len(excerpt) > 40 {
+		return excerpt[:37] + "..."
+	}
+	return excerpt
 }
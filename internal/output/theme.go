@@ -0,0 +1,203 @@
+package output
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ohader/gh-hookmon/internal/config"
+)
+
+// Theme holds the colors of table output as ANSI SGR parameters, e.g. 31 for red, empty for no color
+type Theme struct {
+	Success      string   // Successful deliveries, valid payloads, met objectives
+	Warning      string   // Redirects, inactive hooks
+	Error        string   // Failed deliveries, breached objectives, rising latency
+	WarningCodes []string // Status code patterns shown in the warning color, e.g. 3xx or 429
+	ErrorCodes   []string // Status code patterns shown in the error color, 0 is a delivery without response
+}
+
+// Presets lists the built-in themes by name
+var Presets = map[string]Theme{
+	"default": {
+		Success:      "32", // Green
+		Warning:      "33", // Yellow
+		Error:        "31", // Red
+		WarningCodes: []string{"3xx"},
+		ErrorCodes:   []string{"0", "4xx", "5xx"},
+	},
+	// Blue and orange stay distinguishable with the common forms of color blindness, errors are bold as well
+	"colorblind": {
+		Success:      "34",         // Blue
+		Warning:      "33",         // Yellow
+		Error:        "1;38;5;208", // Bold orange
+		WarningCodes: []string{"3xx"},
+		ErrorCodes:   []string{"0", "4xx", "5xx"},
+	},
+	"none": {
+		WarningCodes: []string{"3xx"},
+		ErrorCodes:   []string{"0", "4xx", "5xx"},
+	},
+}
+
+// theme is the theme of all table output
+var theme = Presets["default"]
+
+// SetTheme changes the theme of all table output
+func SetTheme(t Theme) {
+	theme = t
+}
+
+// NewTheme creates a theme from the theme section of the config file, based on its preset
+func NewTheme(c config.Theme) (Theme, error) {
+	preset := c.Preset
+	if preset == "" {
+		preset = "default"
+	}
+	t, ok := Presets[preset]
+	if !ok {
+		return Theme{}, fmt.Errorf("unknown preset %q, expected default, colorblind or none", c.Preset)
+	}
+
+	colors := []struct {
+		name  string
+		value string
+		color *string
+	}{
+		{"success", c.Success, &t.Success},
+		{"warning", c.Warning, &t.Warning},
+		{"error", c.Error, &t.Error},
+	}
+	for _, entry := range colors {
+		if entry.value == "" {
+			continue
+		}
+		color, err := ParseColor(entry.value)
+		if err != nil {
+			return Theme{}, fmt.Errorf("%s: %w", entry.name, err)
+		}
+		*entry.color = color
+	}
+
+	if c.WarningCodes != nil {
+		if err := validateCodePatterns(c.WarningCodes); err != nil {
+			return Theme{}, fmt.Errorf("warning_codes: %w", err)
+		}
+		t.WarningCodes = c.WarningCodes
+	}
+	if c.ErrorCodes != nil {
+		if err := validateCodePatterns(c.ErrorCodes); err != nil {
+			return Theme{}, fmt.Errorf("error_codes: %w", err)
+		}
+		t.ErrorCodes = c.ErrorCodes
+	}
+	return t, nil
+}
+
+// colorNames maps color names to ANSI SGR parameters
+var colorNames = map[string]string{
+	"black":     "30",
+	"red":       "31",
+	"green":     "32",
+	"yellow":    "33",
+	"blue":      "34",
+	"magenta":   "35",
+	"cyan":      "36",
+	"white":     "37",
+	"gray":      "90",
+	"orange":    "38;5;208",
+	"bold":      "1",
+	"underline": "4",
+}
+
+// sgrPattern matches raw ANSI SGR parameters, e.g. 38;5;208
+var sgrPattern = regexp.MustCompile(`^[0-9]{1,3}(;[0-9]{1,3})*$`)
+
+// ParseColor converts a color given by names, e.g. "bold red", or raw SGR parameters, e.g. "38;5;208"
+// "none" disables the color
+func ParseColor(spec string) (string, error) {
+	if strings.TrimSpace(spec) == "none" {
+		return "", nil
+	}
+	var params []string
+	for _, word := range strings.Fields(strings.ToLower(spec)) {
+		if param, ok := colorNames[word]; ok {
+			params = append(params, param)
+		} else if sgrPattern.MatchString(word) {
+			params = append(params, word)
+		} else {
+			return "", fmt.Errorf("invalid color %q, expected color names like \"bold red\", SGR parameters like 38;5;208 or none", spec)
+		}
+	}
+	if len(params) == 0 {
+		return "", fmt.Errorf("invalid color %q", spec)
+	}
+	return strings.Join(params, ";"), nil
+}
+
+// codePattern matches status code patterns: an exact code like 429 or 0, or a class like 4xx
+var codePattern = regexp.MustCompile(`^([0-9]{1,3}|[1-5]xx)$`)
+
+func validateCodePatterns(patterns []string) error {
+	for _, p := range patterns {
+		if !codePattern.MatchString(p) {
+			return fmt.Errorf("invalid status code %q, expected a code like 429 or a class like 4xx", p)
+		}
+	}
+	return nil
+}
+
+// matchesCode reports whether a status code matches any of the patterns
+func matchesCode(code int, patterns []string) bool {
+	for _, p := range patterns {
+		if strings.HasSuffix(p, "xx") {
+			if code/100 == int(p[0]-'0') {
+				return true
+			}
+		} else if fmt.Sprint(code) == p {
+			return true
+		}
+	}
+	return false
+}
+
+// statusColor returns the color of a delivery status code
+// Warning codes take precedence over error codes, so single codes can be exempted from an error class
+func statusColor(code int) string {
+	switch {
+	case matchesCode(code, theme.WarningCodes):
+		return theme.Warning
+	case matchesCode(code, theme.ErrorCodes):
+		return theme.Error
+	case code >= 200 && code < 300:
+		return theme.Success
+	default:
+		return ""
+	}
+}
+
+// statusGlyph returns the glyph of a delivery status code in compact tables, following the status colors
+func statusGlyph(code int, ascii bool) string {
+	glyphs := [4]string{"✓", "⚠", "✗", "·"}
+	if ascii {
+		glyphs = [4]string{"+", "!", "x", "-"}
+	}
+	switch {
+	case matchesCode(code, theme.WarningCodes):
+		return glyphs[1]
+	case matchesCode(code, theme.ErrorCodes):
+		return glyphs[2]
+	case code >= 200 && code < 300:
+		return glyphs[0]
+	default:
+		return glyphs[3]
+	}
+}
+
+// paint renders text in a color, text is returned as is without a color
+func paint(text, color string) string {
+	if color == "" {
+		return text
+	}
+	return "\033[" + color + "m" + text + "\033[0m"
+}
@@ -0,0 +1,85 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ohader/gh-hookmon/internal/stats"
+	"github.com/olekukonko/tablewriter"
+	"github.com/olekukonko/tablewriter/tw"
+)
+
+// timelineBarWidth is the length of the volume bar of the busiest time bucket
+const timelineBarWidth = 30
+
+// FormatTimelineTable outputs the time buckets of a timeline as an ASCII table, oldest first
+// A bar per bucket charts the volume of deliveries with the failed part in the error color
+func FormatTimelineTable(groups []stats.Group, dim stats.Dimension, w io.Writer, ascii bool) {
+	if len(groups) == 0 {
+		fmt.Fprintln(w, "No deliveries found")
+		return
+	}
+
+	header := append(append([]string(nil), dim.Columns...), "Deliveries", "Failures", "Failure Rate", "Median", "P95", "Volume")
+	// Automatic formatting would turn P95 into P 95, so headers are upper-cased here instead
+	for i := range header {
+		header[i] = strings.ToUpper(header[i])
+	}
+	table := tablewriter.NewTable(w,
+		tablewriter.WithHeaderAutoFormat(tw.Off),
+		tablewriter.WithHeader(header),
+	)
+
+	busiest := 0
+	for _, g := range groups {
+		busiest = max(busiest, g.Deliveries)
+	}
+
+	for _, g := range groups {
+		rate, median, p95 := "-", "-", "-"
+		if g.Deliveries > 0 {
+			rate = formatPercent(g.FailureRate())
+			if g.Failures > 0 {
+				rate = paint(rate, theme.Error)
+			}
+			median = fmt.Sprintf("%.2fs", g.Percentile(50))
+			p95 = fmt.Sprintf("%.2fs", g.Percentile(95))
+		}
+		table.Append([]string{
+			g.Values[0],
+			fmt.Sprintf("%d", g.Deliveries),
+			fmt.Sprintf("%d", g.Failures),
+			rate,
+			median,
+			p95,
+			volumeBar(g.Deliveries, g.Failures, busiest, ascii),
+		})
+	}
+
+	table.Render()
+	table.Close()
+}
+
+// volumeBar draws the deliveries of a bucket relative to the busiest bucket, failures at the end
+// Any failure is drawn at least one character wide, so single failures are not rounded away
+func volumeBar(deliveries, failures, busiest int, ascii bool) string {
+	if deliveries == 0 || busiest == 0 {
+		return ""
+	}
+	length := max(deliveries*timelineBarWidth/busiest, 1)
+	failed := 0
+	if failures > 0 {
+		failed = min(max(failures*length/deliveries, 1), length)
+	}
+
+	okGlyph, failedGlyph := "█", "▓"
+	if ascii {
+		okGlyph, failedGlyph = "#", "x"
+	}
+	bar := strings.Repeat(okGlyph, length-failed)
+	if failed > 0 {
+		bar += paint(strings.Repeat(failedGlyph, failed), theme.Error)
+	}
+	return bar
+}
@@ -0,0 +1,138 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ohader/gh-hookmon/internal/stats"
+	"github.com/olekukonko/tablewriter"
+)
+
+// FormatTrendTable outputs the median latency of every hook per period as an ASCII table
+// Arrows compare each period with the previous one, rising latency is shown in red
+func FormatTrendTable(trends []stats.Trend, starts []time.Time, period stats.Period, w io.Writer) {
+	if len(trends) == 0 {
+		fmt.Fprintln(w, "No recorded deliveries in the selected periods")
+		return
+	}
+
+	header := []string{"Repository", "Hook", "URL"}
+	for _, start := range starts {
+		header = append(header, periodLabel(start, period))
+	}
+	header = append(header, "Trend")
+	table := tablewriter.NewTable(w, tablewriter.WithHeader(header))
+
+	for _, t := range trends {
+		url := t.Hook.URL
+		if url == "" {
+			url = "-"
+		} else if len(url) > 40 {
+			url = url[:37] + "..."
+		}
+
+		row := []string{t.Hook.Repository, fmt.Sprintf("%d", t.Hook.HookID), url}
+		for _, p := range t.Periods {
+			if p.Deliveries == 0 {
+				row = append(row, "-")
+				continue
+			}
+			cell := fmt.Sprintf("%.2fs", p.Median)
+			if arrow := directionArrow(p.Direction); arrow != "" {
+				cell += " " + arrow
+			}
+			row = append(row, cell)
+		}
+
+		trend := "-"
+		if t.Direction != stats.DirectionNone {
+			trend = fmt.Sprintf("%s %+.0f%%", directionArrow(t.Direction), t.Change*100)
+		}
+		row = append(row, trend)
+		table.Append(row)
+	}
+
+	table.Render()
+	table.Close()
+}
+
+// FormatTrendJSON outputs the median latency of every hook per period in JSON format
+func FormatTrendJSON(trends []stats.Trend, w io.Writer) error {
+	type jsonPeriod struct {
+		Start      time.Time `json:"start"`
+		Deliveries int       `json:"deliveries"`
+		Median     *float64  `json:"median_seconds"` // null without deliveries
+		Direction  string    `json:"direction,omitempty"`
+	}
+	type jsonTrend struct {
+		Repository string       `json:"repository"`
+		HookID     int          `json:"hook_id"`
+		URL        string       `json:"url"`
+		Periods    []jsonPeriod `json:"periods"`
+		Change     float64      `json:"change"`
+		Direction  string       `json:"direction,omitempty"`
+	}
+
+	display := make([]jsonTrend, len(trends))
+	for i, t := range trends {
+		periods := make([]jsonPeriod, len(t.Periods))
+		for j, p := range t.Periods {
+			periods[j] = jsonPeriod{
+				Start:      p.Start,
+				Deliveries: p.Deliveries,
+				Direction:  directionName(p.Direction),
+			}
+			if p.Deliveries > 0 {
+				median := p.Median
+				periods[j].Median = &median
+			}
+		}
+		display[i] = jsonTrend{
+			Repository: t.Hook.Repository,
+			HookID:     t.Hook.HookID,
+			URL:        t.Hook.URL,
+			Periods:    periods,
+			Change:     t.Change,
+			Direction:  directionName(t.Direction),
+		}
+	}
+	return encodeJSON(display, w)
+}
+
+// periodLabel returns the column header of a period, e.g. Oct 14 or Week 42
+func periodLabel(start time.Time, period stats.Period) string {
+	if period == stats.PeriodWeek {
+		_, week := start.ISOWeek()
+		return fmt.Sprintf("Week %d", week)
+	}
+	return start.Format("Jan 2")
+}
+
+// directionArrow returns the arrow of a direction, in the error color for rising and the success color for falling latency
+func directionArrow(d stats.Direction) string {
+	switch d {
+	case stats.DirectionUp:
+		return paint("↑", theme.Error)
+	case stats.DirectionDown:
+		return paint("↓", theme.Success)
+	case stats.DirectionFlat:
+		return "→"
+	default:
+		return ""
+	}
+}
+
+// directionName returns the JSON name of a direction, empty if there was nothing to compare
+func directionName(d stats.Direction) string {
+	switch d {
+	case stats.DirectionUp:
+		return "up"
+	case stats.DirectionDown:
+		return "down"
+	case stats.DirectionFlat:
+		return "flat"
+	default:
+		return ""
+	}
+}
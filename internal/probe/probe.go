@@ -0,0 +1,142 @@
+package probe
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Options configures an endpoint probe
+type Options struct {
+	Method  string        // HTTP method of the request, e.g. HEAD
+	Timeout time.Duration // Timeout of each stage
+}
+
+// Result holds the outcome of each stage of an endpoint probe
+// A stage is empty if it was skipped because an earlier stage failed
+type Result struct {
+	URL        string     `json:"url"`
+	DNS        string     `json:"dns"`
+	Connect    string     `json:"connect"`
+	TLS        string     `json:"tls"`
+	CertExpiry *time.Time `json:"cert_expiry,omitempty"`
+	HTTP       string     `json:"http"`
+	StatusCode int        `json:"status_code,omitempty"`
+}
+
+// StatusOK marks a stage that succeeded
+const StatusOK = "ok"
+
+// Reachable reports whether the endpoint returned any HTTP response
+func (r Result) Reachable() bool {
+	return r.HTTP == StatusOK
+}
+
+// Probe checks DNS resolution, TCP connectivity, the TLS handshake and an HTTP request
+// against the endpoint, stopping at the first stage that fails
+func Probe(ctx context.Context, rawURL string, opts Options) Result {
+	result := Result{URL: rawURL}
+
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Hostname() == "" {
+		result.DNS = "invalid URL"
+		return result
+	}
+
+	host := u.Hostname()
+	port := u.Port()
+	if port == "" {
+		port = "443"
+		if u.Scheme == "http" {
+			port = "80"
+		}
+	}
+
+	// DNS resolution
+	dnsCtx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	_, err = net.DefaultResolver.LookupHost(dnsCtx, host)
+	cancel()
+	if err != nil {
+		result.DNS = describe(err)
+		return result
+	}
+	result.DNS = StatusOK
+
+	// TCP connectivity
+	dialer := &net.Dialer{Timeout: opts.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(host, port))
+	if err != nil {
+		result.Connect = describe(err)
+		return result
+	}
+	result.Connect = StatusOK
+
+	// TLS handshake with certificate verification
+	if u.Scheme == "https" {
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: host})
+		tlsConn.SetDeadline(time.Now().Add(opts.Timeout))
+		err := tlsConn.Handshake()
+		if err != nil {
+			conn.Close()
+			result.TLS = describe(err)
+			return result
+		}
+		certs := tlsConn.ConnectionState().PeerCertificates
+		if len(certs) > 0 {
+			expiry := certs[0].NotAfter
+			result.CertExpiry = &expiry
+		}
+		result.TLS = StatusOK
+		tlsConn.Close()
+	} else {
+		conn.Close()
+		result.TLS = "-"
+	}
+
+	// HTTP request, any response counts as reachable
+	client := &http.Client{
+		Timeout: opts.Timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	req, err := http.NewRequestWithContext(ctx, opts.Method, rawURL, nil)
+	if err != nil {
+		result.HTTP = describe(err)
+		return result
+	}
+	req.Header.Set("User-Agent", "gh-hookmon-probe")
+	resp, err := client.Do(req)
+	if err != nil {
+		result.HTTP = describe(err)
+		return result
+	}
+	resp.Body.Close()
+	result.HTTP = StatusOK
+	result.StatusCode = resp.StatusCode
+
+	return result
+}
+
+// describe shortens common network errors to a readable message
+func describe(err error) string {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		if dnsErr.IsNotFound {
+			return "no such host"
+		}
+		if dnsErr.IsTimeout {
+			return "DNS timeout"
+		}
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return "timeout"
+	}
+	return fmt.Sprintf("%v", err)
+}
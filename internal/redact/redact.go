@@ -0,0 +1,29 @@
+package redact
+
+import "strings"
+
+// Placeholder replaces redacted values
+const Placeholder = "[REDACTED]"
+
+// sensitiveHeaders are request headers carrying secrets or values derived from them, in lower case
+var sensitiveHeaders = map[string]bool{
+	"authorization":       true,
+	"x-hub-signature":     true,
+	"x-hub-signature-256": true,
+}
+
+// Headers returns a copy of the headers with the values of sensitive headers replaced by the placeholder
+// Header names are matched case-insensitively
+func Headers(headers map[string]string) map[string]string {
+	if headers == nil {
+		return nil
+	}
+	redacted := make(map[string]string, len(headers))
+	for name, value := range headers {
+		if sensitiveHeaders[strings.ToLower(name)] && value != "" {
+			value = Placeholder
+		}
+		redacted[name] = value
+	}
+	return redacted
+}
@@ -0,0 +1,33 @@
+package redact
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHeaders(t *testing.T) {
+	headers := map[string]string{
+		"Authorization":       "token abc",
+		"X-Hub-Signature":     "sha1=abc",
+		"x-hub-signature-256": "sha256=abc",
+		"X-GitHub-Event":      "push",
+		"X-Empty-Signature":   "",
+	}
+	want := map[string]string{
+		"Authorization":       Placeholder,
+		"X-Hub-Signature":     Placeholder,
+		"x-hub-signature-256": Placeholder,
+		"X-GitHub-Event":      "push",
+		"X-Empty-Signature":   "",
+	}
+	got := Headers(headers)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Headers() = %v, want %v", got, want)
+	}
+	if headers["Authorization"] != "token abc" {
+		t.Errorf("Headers modified its input")
+	}
+	if Headers(nil) != nil {
+		t.Errorf("Headers(nil) must be nil")
+	}
+}
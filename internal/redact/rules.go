@@ -0,0 +1,106 @@
+package redact
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Rules redact sensitive values in payloads by field path and regular expression
+// A nil *Rules leaves everything unchanged
+type Rules struct {
+	fields   [][]string
+	patterns []*regexp.Regexp
+}
+
+// NewRules compiles redaction rules
+// Field paths are dot-separated keys, e.g. sender.email, where * matches any key
+// Arrays are transparent, so commits.author.email matches the author of every commit
+// Patterns are regular expressions whose matches are replaced in every string value
+func NewRules(fields, patterns []string) (*Rules, error) {
+	r := &Rules{}
+	for _, field := range fields {
+		path := strings.Split(field, ".")
+		for _, key := range path {
+			if key == "" {
+				return nil, fmt.Errorf("invalid field path %q", field)
+			}
+		}
+		r.fields = append(r.fields, path)
+	}
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+		r.patterns = append(r.patterns, re)
+	}
+	return r, nil
+}
+
+// Payload returns a redacted copy of a decoded JSON payload
+// Values at matching field paths are replaced by the placeholder as a whole
+func (r *Rules) Payload(payload interface{}) interface{} {
+	if r == nil {
+		return payload
+	}
+	return r.walk(payload, nil)
+}
+
+// Text returns the text with all pattern matches replaced by the placeholder
+func (r *Rules) Text(text string) string {
+	if r == nil {
+		return text
+	}
+	for _, re := range r.patterns {
+		text = re.ReplaceAllString(text, Placeholder)
+	}
+	return text
+}
+
+// walk redacts a value found at path
+func (r *Rules) walk(value interface{}, path []string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		redacted := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			childPath := append(path[:len(path):len(path)], key)
+			if r.matchesField(childPath) {
+				redacted[key] = Placeholder
+				continue
+			}
+			redacted[key] = r.walk(child, childPath)
+		}
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(v))
+		for i, child := range v {
+			redacted[i] = r.walk(child, path)
+		}
+		return redacted
+	case string:
+		return r.Text(v)
+	default:
+		return value
+	}
+}
+
+// matchesField reports whether path matches one of the field paths
+func (r *Rules) matchesField(path []string) bool {
+	for _, field := range r.fields {
+		if len(field) != len(path) {
+			continue
+		}
+		matches := true
+		for i, key := range field {
+			if key != "*" && key != path[i] {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
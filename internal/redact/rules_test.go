@@ -0,0 +1,150 @@
+package redact
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// decode parses a JSON payload as the deliveries API returns it
+func decode(t *testing.T, payload string) interface{} {
+	t.Helper()
+	var v interface{}
+	if err := json.Unmarshal([]byte(payload), &v); err != nil {
+		t.Fatalf("invalid test payload %s: %v", payload, err)
+	}
+	return v
+}
+
+func encode(t *testing.T, v interface{}) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("failed to encode %v: %v", v, err)
+	}
+	return string(data)
+}
+
+func TestRulesPayload(t *testing.T) {
+	tests := []struct {
+		name     string
+		fields   []string
+		patterns []string
+		payload  string
+		want     string
+	}{
+		{
+			name:    "field path",
+			fields:  []string{"sender.email"},
+			payload: `{"sender":{"email":"jane@example.com","login":"jane"}}`,
+			want:    `{"sender":{"email":"[REDACTED]","login":"jane"}}`,
+		},
+		{
+			name:    "field replaced as a whole whatever its type",
+			fields:  []string{"sender"},
+			payload: `{"action":"opened","sender":{"login":"jane","id":1}}`,
+			want:    `{"action":"opened","sender":"[REDACTED]"}`,
+		},
+		{
+			name:    "wildcard key",
+			fields:  []string{"*.token"},
+			payload: `{"a":{"token":"t1"},"b":{"token":"t2","keep":"x"},"token":"top"}`,
+			want:    `{"a":{"token":"[REDACTED]"},"b":{"keep":"x","token":"[REDACTED]"},"token":"top"}`,
+		},
+		{
+			name:    "arrays are transparent",
+			fields:  []string{"commits.author.email"},
+			payload: `{"commits":[{"author":{"email":"a@example.com"}},{"author":{"email":"b@example.com","name":"B"}}]}`,
+			want:    `{"commits":[{"author":{"email":"[REDACTED]"}},{"author":{"email":"[REDACTED]","name":"B"}}]}`,
+		},
+		{
+			name:    "paths only match at their full depth",
+			fields:  []string{"email"},
+			payload: `{"email":"top@example.com","sender":{"email":"nested@example.com"}}`,
+			want:    `{"email":"[REDACTED]","sender":{"email":"nested@example.com"}}`,
+		},
+		{
+			name:     "patterns apply to every string value",
+			patterns: []string{`ghp_[A-Za-z0-9]+`},
+			payload:  `{"body":"token ghp_abc123 leaked","list":["ghp_x","ok"],"count":3}`,
+			want:     `{"body":"token [REDACTED] leaked","count":3,"list":["[REDACTED]","ok"]}`,
+		},
+		{
+			name:     "fields and patterns combined",
+			fields:   []string{"pusher"},
+			patterns: []string{`\d{4}-\d{4}`},
+			payload:  `{"pusher":{"name":"jane"},"ref":"card 1234-5678"}`,
+			want:     `{"pusher":"[REDACTED]","ref":"card [REDACTED]"}`,
+		},
+		{
+			name:    "no rules",
+			payload: `{"sender":{"email":"jane@example.com"}}`,
+			want:    `{"sender":{"email":"jane@example.com"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := NewRules(tt.fields, tt.patterns)
+			if err != nil {
+				t.Fatalf("NewRules returned error: %v", err)
+			}
+			payload := decode(t, tt.payload)
+			if got := encode(t, rules.Payload(payload)); got != encode(t, decode(t, tt.want)) {
+				t.Errorf("Payload(%s) = %s, want %s", tt.payload, got, tt.want)
+			}
+			if got := encode(t, payload); got != encode(t, decode(t, tt.payload)) {
+				t.Errorf("Payload modified its input to %s", got)
+			}
+		})
+	}
+}
+
+func TestRulesText(t *testing.T) {
+	rules, err := NewRules([]string{"sender.email"}, []string{`password=\S+`, `[a-z]+@example\.com`})
+	if err != nil {
+		t.Fatalf("NewRules returned error: %v", err)
+	}
+	tests := []struct {
+		text string
+		want string
+	}{
+		{text: "", want: ""},
+		{text: "no secrets here", want: "no secrets here"},
+		{text: "login failed: password=hunter2", want: "login failed: [REDACTED]"},
+		{text: "jane@example.com and joe@example.com", want: "[REDACTED] and [REDACTED]"},
+		{text: `{"sender":{"email":"x"}}`, want: `{"sender":{"email":"x"}}`},
+	}
+	for _, tt := range tests {
+		if got := rules.Text(tt.text); got != tt.want {
+			t.Errorf("Text(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestNilRules(t *testing.T) {
+	var rules *Rules
+	payload := map[string]interface{}{"sender": map[string]interface{}{"email": "jane@example.com"}}
+	if got := rules.Payload(payload); encode(t, got) != encode(t, payload) {
+		t.Errorf("nil rules changed the payload to %v", got)
+	}
+	if got := rules.Text("password=hunter2"); got != "password=hunter2" {
+		t.Errorf("nil rules changed the text to %q", got)
+	}
+}
+
+func TestNewRulesInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		fields   []string
+		patterns []string
+	}{
+		{name: "empty field", fields: []string{""}},
+		{name: "empty key", fields: []string{"sender..email"}},
+		{name: "trailing dot", fields: []string{"sender."}},
+		{name: "invalid pattern", patterns: []string{"("}},
+	}
+	for _, tt := range tests {
+		if _, err := NewRules(tt.fields, tt.patterns); err == nil {
+			t.Errorf("%s: NewRules(%q, %q) returned no error", tt.name, tt.fields, tt.patterns)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package s3
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Credentials are AWS access keys together with the region requests are signed for
+type Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string // Temporary credentials only
+	Region          string
+}
+
+// defaultRegion is used when neither the environment nor the config file set a region
+const defaultRegion = "us-east-1"
+
+// LoadCredentials reads credentials the way the AWS CLI does: AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and
+// AWS_SESSION_TOKEN first, then the AWS_PROFILE (default: default) profile of ~/.aws/credentials
+// The region comes from AWS_REGION, AWS_DEFAULT_REGION or the profile in ~/.aws/config
+func LoadCredentials() (Credentials, error) {
+	profile := os.Getenv("AWS_PROFILE")
+	if profile == "" {
+		profile = "default"
+	}
+
+	c := Credentials{
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		Region:          firstNonEmpty(os.Getenv("AWS_REGION"), os.Getenv("AWS_DEFAULT_REGION")),
+	}
+
+	if c.AccessKeyID == "" || c.SecretAccessKey == "" {
+		path, err := sharedFile("AWS_SHARED_CREDENTIALS_FILE", "credentials")
+		if err != nil {
+			return c, err
+		}
+		section, err := readSection(path, profile)
+		if err != nil {
+			return c, err
+		}
+		c.AccessKeyID = section["aws_access_key_id"]
+		c.SecretAccessKey = section["aws_secret_access_key"]
+		c.SessionToken = section["aws_session_token"]
+	}
+	if c.AccessKeyID == "" || c.SecretAccessKey == "" {
+		return c, fmt.Errorf("no AWS credentials found, set AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY or configure profile %q in ~/.aws/credentials", profile)
+	}
+
+	if c.Region == "" {
+		path, err := sharedFile("AWS_CONFIG_FILE", "config")
+		if err != nil {
+			return c, err
+		}
+		// Profiles other than the default one are named "profile NAME" in the config file
+		name := profile
+		if name != "default" {
+			name = "profile " + name
+		}
+		section, err := readSection(path, name)
+		if err != nil {
+			return c, err
+		}
+		c.Region = section["region"]
+	}
+	if c.Region == "" {
+		c.Region = defaultRegion
+	}
+	return c, nil
+}
+
+// sharedFile returns the path of a shared AWS file, overridden by the environment variable
+func sharedFile(env, name string) (string, error) {
+	if path := os.Getenv(env); path != "" {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine home directory: %w", err)
+	}
+	return filepath.Join(home, ".aws", name), nil
+}
+
+// readSection returns the keys of a section of an INI file, a missing file has no sections
+func readSection(path, name string) (map[string]string, error) {
+	values := make(map[string]string)
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return values, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	defer file.Close()
+
+	inSection := false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			inSection = strings.TrimSpace(line[1:len(line)-1]) == name
+			continue
+		}
+		if !inSection {
+			continue
+		}
+		if key, value, ok := strings.Cut(line, "="); ok {
+			values[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return values, nil
+}
+
+// firstNonEmpty returns the first value that is not empty
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,135 @@
+package s3
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+// Location is an object in a bucket, parsed from an s3://bucket/key URL
+type Location struct {
+	Bucket string
+	Key    string
+}
+
+// ParseURL parses an s3://bucket/key URL
+// A key ending with a slash is a prefix, the uploaded file name is appended to it
+func ParseURL(raw string) (Location, error) {
+	rest, ok := strings.CutPrefix(raw, "s3://")
+	if !ok {
+		return Location{}, fmt.Errorf("invalid S3 URL %q, expected s3://bucket/path/", raw)
+	}
+	bucket, key, _ := strings.Cut(rest, "/")
+	if bucket == "" {
+		return Location{}, fmt.Errorf("invalid S3 URL %q, bucket is missing", raw)
+	}
+	return Location{Bucket: bucket, Key: key}, nil
+}
+
+// ObjectKey returns the key a file is uploaded to
+func (l Location) ObjectKey(file string) string {
+	if l.Key == "" || strings.HasSuffix(l.Key, "/") {
+		return l.Key + path.Base(file)
+	}
+	return l.Key
+}
+
+// Client uploads objects to S3 or an S3-compatible object storage
+type Client struct {
+	HTTP        *http.Client
+	Credentials Credentials
+	Endpoint    string // S3-compatible endpoint, e.g. https://minio.example.com (empty = AWS)
+}
+
+// EndpointFromEnv returns the S3-compatible endpoint configured by AWS_ENDPOINT_URL_S3 or AWS_ENDPOINT_URL
+func EndpointFromEnv() string {
+	return firstNonEmpty(os.Getenv("AWS_ENDPOINT_URL_S3"), os.Getenv("AWS_ENDPOINT_URL"))
+}
+
+// Upload stores the file as an object and returns its s3:// URL
+func (c *Client) Upload(file string, loc Location) (string, error) {
+	key := loc.ObjectKey(file)
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	if err := c.put(loc.Bucket, key, data); err != nil {
+		return "", err
+	}
+	return "s3://" + loc.Bucket + "/" + key, nil
+}
+
+// put sends a PutObject request signed with AWS Signature Version 4
+func (c *Client) put(bucket, key string, data []byte) error {
+	target, err := c.objectURL(bucket, key)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequest(http.MethodPut, target.String(), bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create upload request: %w", err)
+	}
+	request.ContentLength = int64(len(data))
+	request.Header.Set("X-Amz-Content-Sha256", hashHex(data))
+	if c.Credentials.SessionToken != "" {
+		request.Header.Set("X-Amz-Security-Token", c.Credentials.SessionToken)
+	}
+	sign(request, data, c.Credentials, "s3", time.Now().UTC())
+
+	response, err := c.HTTP.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to upload to s3://%s/%s: %w", bucket, key, err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+		return fmt.Errorf("failed to upload to s3://%s/%s: %s: %s", bucket, key, response.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// objectURL returns the URL of an object, virtual-hosted style on AWS and path style on other endpoints
+// Buckets whose names are no valid host name label, e.g. with dots or uppercase letters, are addressed path style on AWS too
+func (c *Client) objectURL(bucket, key string) (*url.URL, error) {
+	escaped := escapePath(key)
+	if c.Endpoint == "" {
+		if !virtualHostable(bucket) {
+			return url.Parse(fmt.Sprintf("https://s3.%s.amazonaws.com/%s/%s", c.Credentials.Region, escapePath(bucket), escaped))
+		}
+		return url.Parse(fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", bucket, c.Credentials.Region, escaped))
+	}
+	base, err := url.Parse(strings.TrimSuffix(c.Endpoint, "/"))
+	if err != nil || base.Host == "" {
+		return nil, fmt.Errorf("invalid S3 endpoint %q", c.Endpoint)
+	}
+	return url.Parse(fmt.Sprintf("%s/%s/%s", base.String(), escapePath(bucket), escaped))
+}
+
+// virtualHostable reports whether a bucket can be addressed as a subdomain of the S3 endpoint
+// The name must be a single DNS label of lowercase letters, digits and hyphens: dots would not match
+// the wildcard certificate of the endpoint, legacy buckets may have uppercase letters and underscores
+func virtualHostable(bucket string) bool {
+	if len(bucket) < 3 || len(bucket) > 63 || bucket[0] == '-' || bucket[len(bucket)-1] == '-' {
+		return false
+	}
+	for i := 0; i < len(bucket); i++ {
+		c := bucket[i]
+		if !('a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// escapePath URI-encodes every segment of an object key as required by Signature Version 4
+func escapePath(key string) string {
+	return uriEncode(key, false)
+}
@@ -0,0 +1,69 @@
+package s3
+
+import "testing"
+
+func TestObjectURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		endpoint string
+		bucket   string
+		key      string
+		want     string
+	}{
+		{
+			name:   "virtual-hosted style on AWS",
+			bucket: "hookmon-exports",
+			key:    "daily/report 1.json",
+			want:   "https://hookmon-exports.s3.eu-central-1.amazonaws.com/daily/report%201.json",
+		},
+		{
+			name:   "path style for bucket names with dots",
+			bucket: "exports.example.com",
+			key:    "report.json",
+			want:   "https://s3.eu-central-1.amazonaws.com/exports.example.com/report.json",
+		},
+		{
+			name:   "path style for legacy bucket names",
+			bucket: "Legacy_Exports",
+			key:    "report.json",
+			want:   "https://s3.eu-central-1.amazonaws.com/Legacy_Exports/report.json",
+		},
+		{
+			name:     "path style on S3-compatible endpoints",
+			endpoint: "https://minio.example.com/",
+			bucket:   "hookmon-exports",
+			key:      "report.json",
+			want:     "https://minio.example.com/hookmon-exports/report.json",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Client{Credentials: Credentials{Region: "eu-central-1"}, Endpoint: tt.endpoint}
+			got, err := c.objectURL(tt.bucket, tt.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("objectURL(%q, %q) = %s, want %s", tt.bucket, tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVirtualHostable(t *testing.T) {
+	tests := map[string]bool{
+		"hookmon-exports":     true,
+		"abc":                 true,
+		"ab":                  false,
+		"exports.example.com": false,
+		"Exports":             false,
+		"my_exports":          false,
+		"-exports":            false,
+		"exports-":            false,
+	}
+	for bucket, want := range tests {
+		if got := virtualHostable(bucket); got != want {
+			t.Errorf("virtualHostable(%q) = %v, want %v", bucket, got, want)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package s3
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// sign adds the AWS Signature Version 4 Authorization and X-Amz-Date headers to a request
+// All headers set on the request so far are signed together with the host, headers added later
+// by the HTTP client, e.g. User-Agent, are not
+// See https://docs.aws.amazon.com/IAM/latest/UserGuide/reference_sigv-create-signed-request.html
+func sign(request *http.Request, payload []byte, credentials Credentials, service string, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	request.Header.Set("X-Amz-Date", amzDate)
+
+	headers, signedHeaders := canonicalHeaders(request)
+	canonicalRequest := strings.Join([]string{
+		request.Method,
+		canonicalPath(request.URL),
+		canonicalQuery(request.URL.RawQuery),
+		headers,
+		signedHeaders,
+		hashHex(payload),
+	}, "\n")
+
+	scope := strings.Join([]string{date, credentials.Region, service, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, hashHex([]byte(canonicalRequest))}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+credentials.SecretAccessKey), date)
+	key = hmacSHA256(key, credentials.Region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	request.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		credentials.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// canonicalHeaders returns the canonical headers of a request and the list of their names
+// Names are lowercased and sorted, values trimmed with inner runs of spaces collapsed,
+// and the values of a repeated header joined with commas
+func canonicalHeaders(request *http.Request) (string, string) {
+	host := request.Host
+	if host == "" {
+		host = request.URL.Host
+	}
+	values := map[string]string{"host": host}
+	for name, vs := range request.Header {
+		if strings.EqualFold(name, "Authorization") {
+			continue
+		}
+		trimmed := make([]string, len(vs))
+		for i, v := range vs {
+			trimmed[i] = strings.Join(strings.Fields(v), " ")
+		}
+		values[strings.ToLower(name)] = strings.Join(trimmed, ",")
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		b.WriteString(name + ":" + values[name] + "\n")
+	}
+	return b.String(), strings.Join(names, ";")
+}
+
+// canonicalPath returns the URI-encoded path of a request, S3 paths are signed as sent without normalization
+func canonicalPath(u *url.URL) string {
+	if p := u.EscapedPath(); p != "" {
+		return p
+	}
+	return "/"
+}
+
+// canonicalQuery returns the query parameters URI-encoded and sorted by name, then value
+func canonicalQuery(raw string) string {
+	type param struct{ name, value string }
+	var params []param
+	for _, pair := range strings.Split(raw, "&") {
+		if pair == "" {
+			continue
+		}
+		name, value, _ := strings.Cut(pair, "=")
+		if unescaped, err := url.QueryUnescape(name); err == nil {
+			name = unescaped
+		}
+		if unescaped, err := url.QueryUnescape(value); err == nil {
+			value = unescaped
+		}
+		params = append(params, param{uriEncode(name, true), uriEncode(value, true)})
+	}
+	sort.Slice(params, func(i, j int) bool {
+		if params[i].name != params[j].name {
+			return params[i].name < params[j].name
+		}
+		return params[i].value < params[j].value
+	})
+
+	pairs := make([]string, len(params))
+	for i, p := range params {
+		pairs[i] = p.name + "=" + p.value
+	}
+	return strings.Join(pairs, "&")
+}
+
+// uriEncode encodes every byte except letters, digits and -_.~ as required by Signature Version 4
+// Slashes are kept unless encodeSlash is set, as for query parameters
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || strings.IndexByte("-_.~", c) >= 0 || c == '/' && !encodeSlash {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// hashHex returns the hex-encoded SHA-256 hash of data
+func hashHex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// hmacSHA256 returns the HMAC-SHA256 of data with key
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
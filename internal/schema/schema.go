@@ -0,0 +1,299 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// DefaultURL is where the octokit/webhooks project publishes the bundled webhook event schemas
+const DefaultURL = "https://unpkg.com/@octokit/webhooks-schemas/schema.json"
+
+// Problem is a deviation of a payload from its event schema
+type Problem struct {
+	Path    string // JSON pointer of the value, empty for the payload itself
+	Message string
+}
+
+// String returns the problem prefixed with its path, e.g. /sender: missing required property "login"
+func (p Problem) String() string {
+	if p.Path == "" {
+		return p.Message
+	}
+	return p.Path + ": " + p.Message
+}
+
+// Set holds the schemas of all webhook events
+// Only the keywords used by the octokit/webhooks schemas are supported:
+// $ref, type, enum, const, properties, required, additionalProperties, items, anyOf, oneOf, allOf and deprecated
+type Set struct {
+	definitions map[string]interface{}
+}
+
+// Parse reads the bundled schema, whose definitions contain one schema per event and action
+// named event$action, e.g. pull_request$opened, or event$event for events without actions
+func Parse(data []byte) (*Set, error) {
+	var root struct {
+		Definitions map[string]interface{} `json:"definitions"`
+	}
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse schema: %w", err)
+	}
+	if len(root.Definitions) == 0 {
+		return nil, fmt.Errorf("schema has no definitions, expected the bundled octokit/webhooks schema")
+	}
+	return &Set{definitions: root.Definitions}, nil
+}
+
+// Validate checks a decoded payload against the schema of its event and action
+// Properties missing from the schema are reported as unexpected, properties marked as deprecated as deprecated
+func (s *Set) Validate(event, action string, payload interface{}) []Problem {
+	def, ok := s.lookup(event, action)
+	if !ok {
+		name := event
+		if action != "" {
+			name += "." + action
+		}
+		return []Problem{{Message: fmt.Sprintf("no schema for event %s", name)}}
+	}
+	return s.validate(def, payload, "")
+}
+
+// lookup returns the schema of an event and action
+func (s *Set) lookup(event, action string) (interface{}, bool) {
+	names := []string{event + "$event", event}
+	if action != "" {
+		names = append([]string{event + "$" + action}, names...)
+	}
+	for _, name := range names {
+		if def, ok := s.definitions[name]; ok {
+			return def, true
+		}
+	}
+	return nil, false
+}
+
+// validate checks value at path against a schema node
+func (s *Set) validate(node interface{}, value interface{}, path string) []Problem {
+	if allowed, ok := node.(bool); ok {
+		if !allowed {
+			return []Problem{{Path: path, Message: "unexpected value"}}
+		}
+		return nil
+	}
+	schema, ok := node.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	if ref, ok := schema["$ref"].(string); ok {
+		// Unknown references are not checked rather than reported, they are a problem of the schema
+		target, ok := s.resolve(ref)
+		if !ok {
+			return nil
+		}
+		return s.validate(target, value, path)
+	}
+
+	var problems []Problem
+	if deprecated, _ := schema["deprecated"].(bool); deprecated {
+		problems = append(problems, Problem{Path: path, Message: "deprecated"})
+	}
+
+	if t, ok := schema["type"]; ok && !matchesType(t, value) {
+		return append(problems, Problem{Path: path, Message: fmt.Sprintf("expected %s, got %s", typeNames(t), jsonType(value))})
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok && !contains(enum, value) {
+		problems = append(problems, Problem{Path: path, Message: fmt.Sprintf("unexpected value %s", encode(value))})
+	}
+	if c, ok := schema["const"]; ok && !reflect.DeepEqual(c, value) {
+		problems = append(problems, Problem{Path: path, Message: fmt.Sprintf("unexpected value %s, expected %s", encode(value), encode(c))})
+	}
+
+	for _, keyword := range []string{"anyOf", "oneOf"} {
+		if alternatives, ok := schema[keyword].([]interface{}); ok {
+			problems = append(problems, s.validateAlternatives(alternatives, value, path)...)
+		}
+	}
+	if all, ok := schema["allOf"].([]interface{}); ok {
+		for _, sub := range all {
+			problems = append(problems, s.validate(sub, value, path)...)
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		problems = append(problems, s.validateObject(schema, v, path)...)
+	case []interface{}:
+		if items, ok := schema["items"]; ok {
+			for i, item := range v {
+				problems = append(problems, s.validate(items, item, fmt.Sprintf("%s/%d", path, i))...)
+			}
+		}
+	}
+	return problems
+}
+
+// validateAlternatives checks value against anyOf or oneOf alternatives
+// If no alternative matches, the problems of the closest one are reported
+func (s *Set) validateAlternatives(alternatives []interface{}, value interface{}, path string) []Problem {
+	var closest []Problem
+	for i, alternative := range alternatives {
+		problems := s.validate(alternative, value, path)
+		if !hasErrors(problems) {
+			return problems
+		}
+		if i == 0 || len(problems) < len(closest) {
+			closest = problems
+		}
+	}
+	return closest
+}
+
+// validateObject checks the properties of an object
+func (s *Set) validateObject(schema map[string]interface{}, object map[string]interface{}, path string) []Problem {
+	var problems []Problem
+	properties, _ := schema["properties"].(map[string]interface{})
+
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, r := range required {
+			name, _ := r.(string)
+			if _, ok := object[name]; !ok {
+				problems = append(problems, Problem{Path: path, Message: fmt.Sprintf("missing required property %q", name)})
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		childPath := path + "/" + escapePointer(key)
+		if property, ok := properties[key]; ok {
+			problems = append(problems, s.validate(property, object[key], childPath)...)
+			continue
+		}
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				problems = append(problems, Problem{Path: path, Message: fmt.Sprintf("unexpected property %q", key)})
+			}
+		case map[string]interface{}:
+			problems = append(problems, s.validate(additional, object[key], childPath)...)
+		}
+	}
+	return problems
+}
+
+// resolve returns the definition a local reference such as #/definitions/user points to
+func (s *Set) resolve(ref string) (interface{}, bool) {
+	name, ok := strings.CutPrefix(ref, "#/definitions/")
+	if !ok {
+		return nil, false
+	}
+	def, ok := s.definitions[name]
+	return def, ok
+}
+
+// hasErrors reports whether problems contain more than deprecation notices
+func hasErrors(problems []Problem) bool {
+	for _, p := range problems {
+		if p.Message != "deprecated" {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesType reports whether value has the type, or one of the types, of a type keyword
+func matchesType(t interface{}, value interface{}) bool {
+	switch t := t.(type) {
+	case string:
+		return isType(t, value)
+	case []interface{}:
+		for _, name := range t {
+			if name, ok := name.(string); ok && isType(name, value) {
+				return true
+			}
+		}
+		return false
+	default:
+		return true
+	}
+}
+
+// isType reports whether a decoded JSON value has the named JSON Schema type
+func isType(name string, value interface{}) bool {
+	switch name {
+	case "integer":
+		n, ok := value.(float64)
+		return ok && n == math.Trunc(n)
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	default:
+		return jsonType(value) == name
+	}
+}
+
+// jsonType returns the JSON type of a decoded value
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// typeNames returns the types of a type keyword for messages, e.g. string or null
+func typeNames(t interface{}) string {
+	names, ok := t.([]interface{})
+	if !ok {
+		return fmt.Sprint(t)
+	}
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprint(name)
+	}
+	return strings.Join(parts, " or ")
+}
+
+// contains reports whether values contain value
+func contains(values []interface{}, value interface{}) bool {
+	for _, v := range values {
+		if reflect.DeepEqual(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// encode returns the JSON representation of a value for messages
+func encode(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// escapePointer escapes a key for use in a JSON pointer
+func escapePointer(key string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
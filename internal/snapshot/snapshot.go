@@ -0,0 +1,97 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ohader/gh-hookmon/internal/archive"
+	"github.com/ohader/gh-hookmon/internal/github"
+)
+
+// Version is the format version of snapshots written by this release
+const Version = 1
+
+// Snapshot is a complete fetched dataset of an organization or repository
+type Snapshot struct {
+	Version         int                 `json:"version"`
+	CreatedAt       time.Time           `json:"created_at"`
+	Org             string              `json:"org,omitempty"`
+	Repo            string              `json:"repo,omitempty"`
+	Team            string              `json:"team,omitempty"`             // Team ORG/TEAM whose repositories were scanned instead of all of Org
+	OrgRepositories []github.Repository `json:"org_repositories,omitempty"` // Repository list of Org, or of Team if set
+	Repositories    []Repository        `json:"repositories"`               // Successfully scanned repositories
+}
+
+// Repository holds the webhooks of a scanned repository
+type Repository struct {
+	Name  string `json:"name"`
+	Hooks []Hook `json:"hooks"`
+}
+
+// Hook is a webhook together with its recent deliveries
+// Deliveries are null if they could not be fetched
+type Hook struct {
+	Hook       github.Hook       `json:"hook"`
+	Deliveries []github.Delivery `json:"deliveries"`
+}
+
+// Target returns the team, organization or repository the snapshot was taken of
+// Snapshots of a repository list are described by their number of repositories
+func (s *Snapshot) Target() string {
+	if s.Team != "" {
+		return s.Team
+	}
+	if s.Org != "" {
+		return s.Org
+	}
+	if s.Repo == "" {
+		return fmt.Sprintf("%d repositories", len(s.Repositories))
+	}
+	return s.Repo
+}
+
+// Counts returns the number of repositories, hooks and deliveries
+func (s *Snapshot) Counts() (repositories, hooks, deliveries int) {
+	for _, r := range s.Repositories {
+		hooks += len(r.Hooks)
+		for _, h := range r.Hooks {
+			deliveries += len(h.Deliveries)
+		}
+	}
+	return len(s.Repositories), hooks, deliveries
+}
+
+// Save writes the snapshot to path, gzip-compressed if path ends with .gz
+func Save(s *Snapshot, path string) error {
+	w, err := archive.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot: %w", err)
+	}
+	if err := json.NewEncoder(w).Encode(s); err != nil {
+		w.Close()
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return nil
+}
+
+// Load reads a snapshot from path, compressed snapshots are detected by their content
+func Load(path string) (*Snapshot, error) {
+	r, err := archive.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open snapshot: %w", err)
+	}
+	defer r.Close()
+
+	var s Snapshot
+	if err := json.NewDecoder(r).Decode(&s); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot: %w", err)
+	}
+	if s.Version < 1 || s.Version > Version {
+		return nil, fmt.Errorf("unsupported snapshot version %d (supported: 1 to %d)", s.Version, Version)
+	}
+	return &s, nil
+}
@@ -0,0 +1,103 @@
+package stats
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/ohader/gh-hookmon/internal/github"
+)
+
+// AnomalyOptions configures failure spike detection
+type AnomalyOptions struct {
+	Window        time.Duration // Current window ending now
+	Baseline      time.Duration // Trailing window preceding the current window
+	MinRatio      float64       // Minimum ratio of current to baseline failure rate
+	MinZScore     float64       // Minimum z-score of the two-proportion test
+	MinDeliveries int           // Minimum deliveries in the current window
+}
+
+// Anomaly describes a hook whose failure rate spiked compared to its baseline
+type Anomaly struct {
+	Hook     HookKey
+	Current  *Summary
+	Baseline *Summary
+	Ratio    float64 // Current failure rate relative to the (smoothed) baseline failure rate
+	ZScore   float64 // Two-proportion z-score of the difference
+}
+
+// DetectAnomalies compares each hook's failure rate in the current window against
+// its trailing baseline and returns the hooks with a significant spike
+// Anomalies are ordered by descending z-score
+func DetectAnomalies(deliveries []github.Delivery, opts AnomalyOptions, now time.Time) []Anomaly {
+	windowStart := now.Add(-opts.Window)
+	baselineStart := windowStart.Add(-opts.Baseline)
+
+	current := make(map[HookKey]*Summary)
+	baseline := make(map[HookKey]*Summary)
+	for _, d := range deliveries {
+		key := ByHook(d)
+		var target map[HookKey]*Summary
+		switch {
+		case d.DeliveredAt.After(now):
+			continue
+		case !d.DeliveredAt.Before(windowStart):
+			target = current
+		case !d.DeliveredAt.Before(baselineStart):
+			target = baseline
+		default:
+			continue
+		}
+		if target[key] == nil {
+			target[key] = &Summary{}
+		}
+		target[key].Add(d)
+	}
+
+	var anomalies []Anomaly
+	for key, cur := range current {
+		if cur.Deliveries < opts.MinDeliveries || cur.Failures == 0 {
+			continue
+		}
+		// Without a baseline there is nothing to compare against
+		base := baseline[key]
+		if base == nil {
+			continue
+		}
+
+		// Laplace smoothing keeps the ratio finite for hooks that never failed before
+		smoothedBase := (float64(base.Failures) + 1) / (float64(base.Deliveries) + 2)
+		ratio := cur.FailureRate() / smoothedBase
+		z := zScore(cur, base)
+
+		if ratio >= opts.MinRatio && z >= opts.MinZScore {
+			anomalies = append(anomalies, Anomaly{
+				Hook:     key,
+				Current:  cur,
+				Baseline: base,
+				Ratio:    ratio,
+				ZScore:   z,
+			})
+		}
+	}
+
+	sort.Slice(anomalies, func(i, j int) bool {
+		return anomalies[i].ZScore > anomalies[j].ZScore
+	})
+
+	return anomalies
+}
+
+// zScore returns the two-proportion z-score of the current failure rate against the baseline
+func zScore(current, baseline *Summary) float64 {
+	n1 := float64(current.Deliveries)
+	n0 := float64(baseline.Deliveries)
+	p1 := current.FailureRate()
+	p0 := baseline.FailureRate()
+	pooled := (float64(current.Failures) + float64(baseline.Failures)) / (n1 + n0)
+	se := math.Sqrt(pooled * (1 - pooled) * (1/n1 + 1/n0))
+	if se == 0 {
+		return 0
+	}
+	return (p1 - p0) / se
+}
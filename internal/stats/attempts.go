@@ -0,0 +1,23 @@
+package stats
+
+import "github.com/ohader/gh-hookmon/internal/github"
+
+// Attempts counts the delivery attempts of every event, the original delivery and its redeliveries share the GUID
+// The result maps delivery IDs to the number of attempts of their event, including the delivery itself
+func Attempts(deliveries []github.Delivery) map[int]int {
+	type event struct {
+		repository string
+		hookID     int
+		guid       string
+	}
+	counts := make(map[event]int)
+	for _, d := range deliveries {
+		counts[event{d.Repository, d.HookID, d.GUID}]++
+	}
+
+	attempts := make(map[int]int, len(deliveries))
+	for _, d := range deliveries {
+		attempts[d.ID] = counts[event{d.Repository, d.HookID, d.GUID}]
+	}
+	return attempts
+}
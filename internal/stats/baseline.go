@@ -0,0 +1,121 @@
+package stats
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ohader/gh-hookmon/internal/github"
+)
+
+// HookSnapshot summarizes the health of a single hook at a point in time
+type HookSnapshot struct {
+	Repository  string  `json:"repository"`
+	HookID      int     `json:"hook_id"`
+	URL         string  `json:"url"`
+	Deliveries  int     `json:"deliveries"`
+	Failures    int     `json:"failures"`
+	FailureRate float64 `json:"failure_rate"`
+	P50         float64 `json:"p50_seconds"`
+	P95         float64 `json:"p95_seconds"`
+}
+
+// Snapshot is a stored health summary of all hooks used as a baseline
+type Snapshot struct {
+	CreatedAt time.Time      `json:"created_at"`
+	Window    string         `json:"window"`
+	Hooks     []HookSnapshot `json:"hooks"`
+}
+
+// NewSnapshot summarizes the deliveries per hook
+func NewSnapshot(deliveries []github.Delivery, window string, now time.Time) Snapshot {
+	groups := GroupBy(deliveries, ByHook)
+	snapshot := Snapshot{
+		CreatedAt: now.UTC(),
+		Window:    window,
+		Hooks:     make([]HookSnapshot, 0, len(groups)),
+	}
+	for _, key := range SortedHookKeys(groups) {
+		s := groups[key]
+		snapshot.Hooks = append(snapshot.Hooks, HookSnapshot{
+			Repository:  key.Repository,
+			HookID:      key.HookID,
+			URL:         key.URL,
+			Deliveries:  s.Deliveries,
+			Failures:    s.Failures,
+			FailureRate: s.FailureRate(),
+			P50:         s.Percentile(50),
+			P95:         s.Percentile(95),
+		})
+	}
+	return snapshot
+}
+
+// RegressionOptions configures when a change counts as a regression
+type RegressionOptions struct {
+	MaxFailureRateIncrease float64 // Allowed increase of the failure rate in percentage points (as fraction)
+	MaxLatencyIncrease     float64 // Allowed relative increase of the p95 latency (as fraction)
+}
+
+// Regression describes a hook whose health got worse compared to the baseline
+type Regression struct {
+	Before  HookSnapshot `json:"before"`
+	After   HookSnapshot `json:"after"`
+	Reasons []string     `json:"reasons"`
+}
+
+// SnapshotDiff is the comparison of a current snapshot against a baseline
+type SnapshotDiff struct {
+	Regressions  []Regression   `json:"regressions"`
+	AddedHooks   []HookSnapshot `json:"added_hooks"`
+	RemovedHooks []HookSnapshot `json:"removed_hooks"`
+}
+
+// CompareSnapshots reports regressions, added and removed hooks of after compared to before
+func CompareSnapshots(before, after Snapshot, opts RegressionOptions) SnapshotDiff {
+	type hookID struct {
+		repository string
+		hookID     int
+	}
+
+	previous := make(map[hookID]HookSnapshot, len(before.Hooks))
+	for _, h := range before.Hooks {
+		previous[hookID{h.Repository, h.HookID}] = h
+	}
+
+	diff := SnapshotDiff{
+		Regressions:  []Regression{},
+		AddedHooks:   []HookSnapshot{},
+		RemovedHooks: []HookSnapshot{},
+	}
+
+	seen := make(map[hookID]bool, len(after.Hooks))
+	for _, h := range after.Hooks {
+		id := hookID{h.Repository, h.HookID}
+		seen[id] = true
+
+		old, ok := previous[id]
+		if !ok {
+			diff.AddedHooks = append(diff.AddedHooks, h)
+			continue
+		}
+
+		var reasons []string
+		if h.FailureRate-old.FailureRate > opts.MaxFailureRateIncrease {
+			reasons = append(reasons, fmt.Sprintf("failure rate %.1f%% -> %.1f%%", old.FailureRate*100, h.FailureRate*100))
+		}
+		if old.P95 > 0 && (h.P95-old.P95)/old.P95 > opts.MaxLatencyIncrease {
+			reasons = append(reasons, fmt.Sprintf("p95 latency %.2fs -> %.2fs", old.P95, h.P95))
+		}
+		if len(reasons) > 0 {
+			diff.Regressions = append(diff.Regressions, Regression{Before: old, After: h, Reasons: reasons})
+		}
+	}
+
+	for _, h := range before.Hooks {
+		if !seen[hookID{h.Repository, h.HookID}] {
+			diff.RemovedHooks = append(diff.RemovedHooks, h)
+		}
+	}
+
+	return diff
+}
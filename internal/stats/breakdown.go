@@ -0,0 +1,187 @@
+package stats
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ohader/gh-hookmon/internal/filter"
+	"github.com/ohader/gh-hookmon/internal/github"
+)
+
+// Dimension is a way of grouping deliveries for a breakdown
+type Dimension struct {
+	Name    string   // Name used on the command line, e.g. event
+	Columns []string // Headers of the values identifying a group
+	Period  Period   // Length of the time buckets of a timeline, empty for other dimensions
+	values  func(d github.Delivery) []string
+}
+
+// Dimensions lists the supported breakdown dimensions
+var Dimensions = []Dimension{
+	{
+		Name:    "event",
+		Columns: []string{"Event", "Action"},
+		values: func(d github.Delivery) []string {
+			return []string{d.Event, d.Action}
+		},
+	},
+	{
+		Name:    "domain",
+		Columns: []string{"Domain"},
+		values: func(d github.Delivery) []string {
+			return []string{targetHost(d.URL)}
+		},
+	},
+	{
+		Name:    "class",
+		Columns: []string{"Class"},
+		values: func(d github.Delivery) []string {
+			return []string{filter.Classify(d.StatusCode, d.Status)}
+		},
+	},
+	{
+		Name:    "url",
+		Columns: []string{"URL"},
+		values: func(d github.Delivery) []string {
+			return []string{d.URL}
+		},
+	},
+	{Name: "hour", Columns: []string{"Hour"}, Period: PeriodHour},
+	{Name: "day", Columns: []string{"Day"}, Period: PeriodDay},
+	{Name: "week", Columns: []string{"Week"}, Period: PeriodWeek},
+}
+
+// DimensionByName returns the dimension with the given name
+func DimensionByName(name string) (Dimension, bool) {
+	for _, d := range Dimensions {
+		if d.Name == name {
+			return d, true
+		}
+	}
+	return Dimension{}, false
+}
+
+// DimensionNames returns the names of all supported dimensions
+func DimensionNames() []string {
+	names := make([]string, len(Dimensions))
+	for i, d := range Dimensions {
+		names[i] = d.Name
+	}
+	return names
+}
+
+// Group is the summary of the deliveries sharing the same values of a dimension
+type Group struct {
+	Values []string
+	Start  time.Time // Beginning of the time bucket of a timeline
+	*Summary
+}
+
+// Breakdown groups deliveries by a dimension
+// Groups are ordered by descending failure rate, then by descending number of deliveries
+// Time buckets are ordered chronologically instead, see Timeline
+func Breakdown(deliveries []github.Delivery, dim Dimension) []Group {
+	if dim.Period != "" {
+		return Timeline(deliveries, dim.Period)
+	}
+
+	index := make(map[string]int)
+	var groups []Group
+	for _, d := range deliveries {
+		values := dim.values(d)
+		key := strings.Join(values, "\x00")
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, Group{Values: values, Summary: &Summary{}})
+		}
+		groups[i].Add(d)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		a, b := groups[i], groups[j]
+		if a.FailureRate() != b.FailureRate() {
+			return a.FailureRate() > b.FailureRate()
+		}
+		if a.Deliveries != b.Deliveries {
+			return a.Deliveries > b.Deliveries
+		}
+		return strings.Join(a.Values, " ") < strings.Join(b.Values, " ")
+	})
+	return groups
+}
+
+// Timeline groups deliveries into consecutive time buckets from the first to the last delivery, oldest first
+// Buckets without deliveries are included, so outages show up as gaps
+func Timeline(deliveries []github.Delivery, period Period) []Group {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	first, last := deliveries[0].DeliveredAt, deliveries[0].DeliveredAt
+	for _, d := range deliveries {
+		if d.DeliveredAt.Before(first) {
+			first = d.DeliveredAt
+		}
+		if d.DeliveredAt.After(last) {
+			last = d.DeliveredAt
+		}
+	}
+
+	var groups []Group
+	index := make(map[time.Time]int)
+	for start := period.Start(first); !start.After(last); start = period.Next(start) {
+		index[start] = len(groups)
+		groups = append(groups, Group{Values: []string{period.Label(start)}, Start: start, Summary: &Summary{}})
+	}
+	for _, d := range deliveries {
+		groups[index[period.Start(d.DeliveredAt)]].Add(d)
+	}
+	return groups
+}
+
+// Ranking orders groups for a top list
+type Ranking string
+
+const (
+	RankByFailures Ranking = "failures" // Number of failed deliveries, then failure rate
+	RankByRate     Ranking = "rate"     // Failure rate, then number of failed deliveries
+)
+
+// Top returns the n groups with failures ranked highest, groups with fewer than minDeliveries deliveries are skipped
+func Top(groups []Group, by Ranking, n, minDeliveries int) []Group {
+	var top []Group
+	for _, g := range groups {
+		if g.Failures > 0 && g.Deliveries >= minDeliveries {
+			top = append(top, g)
+		}
+	}
+
+	sort.SliceStable(top, func(i, j int) bool {
+		a, b := top[i], top[j]
+		if by == RankByRate && a.FailureRate() != b.FailureRate() {
+			return a.FailureRate() > b.FailureRate()
+		}
+		if a.Failures != b.Failures {
+			return a.Failures > b.Failures
+		}
+		return a.FailureRate() > b.FailureRate()
+	})
+
+	if n > 0 && len(top) > n {
+		top = top[:n]
+	}
+	return top
+}
+
+// targetHost returns the lower-case hostname of a webhook target URL, empty if it is unknown
+func targetHost(target string) string {
+	u, err := url.Parse(target)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
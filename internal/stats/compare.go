@@ -0,0 +1,97 @@
+package stats
+
+import (
+	"sort"
+
+	"github.com/ohader/gh-hookmon/internal/github"
+)
+
+// OrgHealth summarizes the webhooks of an organization's repositories and their deliveries
+type OrgHealth struct {
+	Org           string
+	Repositories  int // Repositories with at least one webhook
+	Hooks         int
+	InactiveHooks int
+	*Summary
+	Endpoints map[string]*EndpointHealth // Keyed by target URL
+}
+
+// EndpointHealth summarizes the webhooks of an organization pointing to the same target URL
+type EndpointHealth struct {
+	URL   string
+	Hooks int
+	*Summary
+}
+
+// NewOrgHealth summarizes the webhooks of an organization and their deliveries
+func NewOrgHealth(org string, hooks []github.RepoHook, deliveries []github.Delivery) *OrgHealth {
+	h := &OrgHealth{Org: org, Summary: &Summary{}, Endpoints: make(map[string]*EndpointHealth)}
+	repos := make(map[string]bool)
+	urls := make(map[HookKey]string)
+	for _, hook := range hooks {
+		repos[hook.Repository] = true
+		h.Hooks++
+		if !hook.Active {
+			h.InactiveHooks++
+		}
+		url := hook.GetTargetURL()
+		urls[HookKey{Repository: hook.Repository, HookID: hook.ID}] = url
+		h.endpoint(url).Hooks++
+	}
+	h.Repositories = len(repos)
+
+	for _, d := range deliveries {
+		h.Add(d)
+		// Deliveries belong to the endpoint their hook points to now, even if it was changed since
+		url, ok := urls[HookKey{Repository: d.Repository, HookID: d.HookID}]
+		if !ok {
+			url = d.URL
+		}
+		h.endpoint(url).Add(d)
+	}
+	return h
+}
+
+// endpoint returns the summary of a target URL, creating it on first use
+func (h *OrgHealth) endpoint(url string) *EndpointHealth {
+	e, ok := h.Endpoints[url]
+	if !ok {
+		e = &EndpointHealth{URL: url, Summary: &Summary{}}
+		h.Endpoints[url] = e
+	}
+	return e
+}
+
+// SharedEndpoint is a target URL receiving webhooks of both compared organizations
+type SharedEndpoint struct {
+	URL  string
+	A, B *EndpointHealth
+}
+
+// CompareEndpoints splits the target URLs of two organizations into shared ones and those of only one organization
+// Shared endpoints are ordered by their combined number of failures, the others by URL
+func CompareEndpoints(a, b *OrgHealth) (shared []SharedEndpoint, onlyA, onlyB []string) {
+	for url, e := range a.Endpoints {
+		if other, ok := b.Endpoints[url]; ok {
+			shared = append(shared, SharedEndpoint{URL: url, A: e, B: other})
+		} else {
+			onlyA = append(onlyA, url)
+		}
+	}
+	for url := range b.Endpoints {
+		if _, ok := a.Endpoints[url]; !ok {
+			onlyB = append(onlyB, url)
+		}
+	}
+
+	sort.Slice(shared, func(i, j int) bool {
+		fi, fj := shared[i].A.Failures+shared[i].B.Failures, shared[j].A.Failures+shared[j].B.Failures
+		if fi != fj {
+			return fi > fj
+		}
+		return shared[i].URL < shared[j].URL
+	})
+	sort.Strings(onlyA)
+	sort.Strings(onlyB)
+	return shared, onlyA, onlyB
+}
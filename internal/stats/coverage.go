@@ -0,0 +1,90 @@
+package stats
+
+import (
+	"sort"
+
+	"github.com/ohader/gh-hookmon/internal/github"
+)
+
+// EventCoverage compares a hook's subscribed events with the events it delivered
+type EventCoverage struct {
+	Hook           HookKey
+	Subscribed     []string
+	Delivered      map[string]int // Deliveries per event
+	NeverDelivered []string       // Subscribed events without any delivery
+	Missed         []string       // Never delivered events although the repository had matching activity
+	Unexpected     []string       // Delivered events the hook is not subscribed to
+}
+
+// HasGaps checks if the hook has events that were never delivered or not expected
+func (c *EventCoverage) HasGaps() bool {
+	return len(c.NeverDelivered) > 0 || len(c.Missed) > 0 || len(c.Unexpected) > 0
+}
+
+// CoverageOf computes the event coverage of a hook from its delivery history
+// Activity is optional, only activity since the oldest delivery is considered
+// so that a truncated history does not count as missed events
+func CoverageOf(repo string, hook github.Hook, deliveries []github.Delivery, activity []github.RepoEvent) EventCoverage {
+	coverage := EventCoverage{
+		Hook:       HookKey{Repository: repo, HookID: hook.ID, URL: hook.GetTargetURL()},
+		Subscribed: hook.Events,
+		Delivered:  make(map[string]int),
+	}
+
+	for _, d := range deliveries {
+		coverage.Delivered[d.Event]++
+	}
+
+	subscribed := make(map[string]bool)
+	wildcard := false
+	for _, event := range hook.Events {
+		if event == "*" {
+			wildcard = true
+		}
+		subscribed[event] = true
+	}
+
+	// Activity since the oldest delivery that should have triggered the hook
+	active := make(map[string]bool)
+	if len(activity) > 0 {
+		var oldest *github.Delivery
+		for i := range deliveries {
+			if oldest == nil || deliveries[i].DeliveredAt.Before(oldest.DeliveredAt) {
+				oldest = &deliveries[i]
+			}
+		}
+		for _, e := range activity {
+			if oldest != nil && e.CreatedAt.Before(oldest.DeliveredAt) {
+				continue
+			}
+			active[e.WebhookEvent()] = true
+		}
+	}
+
+	if !wildcard {
+		for _, event := range hook.Events {
+			if coverage.Delivered[event] == 0 {
+				coverage.NeverDelivered = append(coverage.NeverDelivered, event)
+			}
+		}
+	}
+
+	for event := range active {
+		if (wildcard || subscribed[event]) && coverage.Delivered[event] == 0 {
+			coverage.Missed = append(coverage.Missed, event)
+		}
+	}
+
+	for event := range coverage.Delivered {
+		// GitHub sends a ping to every new hook regardless of its events
+		if wildcard || subscribed[event] || event == "ping" {
+			continue
+		}
+		coverage.Unexpected = append(coverage.Unexpected, event)
+	}
+
+	sort.Strings(coverage.NeverDelivered)
+	sort.Strings(coverage.Missed)
+	sort.Strings(coverage.Unexpected)
+	return coverage
+}
@@ -0,0 +1,43 @@
+package stats
+
+import (
+	"sort"
+
+	"github.com/ohader/gh-hookmon/internal/github"
+)
+
+// DurationDeltas compares the duration of every delivery with the rolling median of its hook,
+// the median duration of up to window deliveries of the same hook before it
+// The result maps delivery IDs to the relative difference, e.g. 3.4 for a delivery that took 340% longer than the median
+// Deliveries with fewer than minBaseline earlier deliveries are left out, as their median is not meaningful
+func DurationDeltas(deliveries []github.Delivery, window, minBaseline int) map[int]float64 {
+	type hook struct {
+		repository string
+		id         int
+	}
+	byHook := make(map[hook][]github.Delivery)
+	for _, d := range deliveries {
+		key := hook{d.Repository, d.HookID}
+		byHook[key] = append(byHook[key], d)
+	}
+
+	deltas := make(map[int]float64)
+	for _, hookDeliveries := range byHook {
+		sort.SliceStable(hookDeliveries, func(i, j int) bool {
+			return hookDeliveries[i].DeliveredAt.Before(hookDeliveries[j].DeliveredAt)
+		})
+		for i, d := range hookDeliveries {
+			baseline := &Summary{}
+			for _, earlier := range hookDeliveries[max(0, i-window):i] {
+				baseline.Durations = append(baseline.Durations, earlier.Duration)
+			}
+			if len(baseline.Durations) < max(minBaseline, 1) {
+				continue
+			}
+			if median := baseline.Percentile(50); median > 0 {
+				deltas[d.ID] = d.Duration/median - 1
+			}
+		}
+	}
+	return deltas
+}
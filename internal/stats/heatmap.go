@@ -0,0 +1,80 @@
+package stats
+
+import (
+	"time"
+
+	"github.com/ohader/gh-hookmon/internal/filter"
+	"github.com/ohader/gh-hookmon/internal/github"
+)
+
+// HeatmapMetric is the value shaded in the cells of a heatmap
+type HeatmapMetric string
+
+const (
+	HeatmapVolume      HeatmapMetric = "volume"       // Number of deliveries
+	HeatmapFailureRate HeatmapMetric = "failure-rate" // Fraction of failed deliveries
+)
+
+// HeatmapCell counts the deliveries of one hour of a weekday
+type HeatmapCell struct {
+	Deliveries int
+	Failures   int
+}
+
+// FailureRate returns the fraction of failed deliveries (0 for an empty cell)
+func (c HeatmapCell) FailureRate() float64 {
+	if c.Deliveries == 0 {
+		return 0
+	}
+	return float64(c.Failures) / float64(c.Deliveries)
+}
+
+// Heatmap counts deliveries per weekday and hour of the day
+// Rows start on Monday, so weekends end up next to each other at the bottom
+type Heatmap struct {
+	Cells    [7][24]HeatmapCell
+	Location *time.Location // Time zone the weekdays and hours are counted in
+}
+
+// HeatmapWeekdays are the weekdays of the heatmap rows
+var HeatmapWeekdays = [7]time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday}
+
+// NewHeatmap counts deliveries per weekday and hour in the time zone loc
+func NewHeatmap(deliveries []github.Delivery, loc *time.Location) *Heatmap {
+	h := &Heatmap{Location: loc}
+	for _, d := range deliveries {
+		t := d.DeliveredAt.In(loc)
+		// Sunday is day 0, move it to the end of the week
+		cell := &h.Cells[(int(t.Weekday())+6)%7][t.Hour()]
+		cell.Deliveries++
+		if filter.IsFailed(d.StatusCode) {
+			cell.Failures++
+		}
+	}
+	return h
+}
+
+// Value returns the metric of a cell, failure rates of cells with fewer than minDeliveries deliveries are 0
+func (h *Heatmap) Value(day, hour int, metric HeatmapMetric, minDeliveries int) float64 {
+	cell := h.Cells[day][hour]
+	if metric == HeatmapFailureRate {
+		if cell.Deliveries < max(minDeliveries, 1) {
+			return 0
+		}
+		return cell.FailureRate()
+	}
+	return float64(cell.Deliveries)
+}
+
+// Peak returns the weekday and hour with the highest value of the metric, ok is false if all values are 0
+func (h *Heatmap) Peak(metric HeatmapMetric, minDeliveries int) (day, hour int, ok bool) {
+	var peak float64
+	for d := range h.Cells {
+		for hr := range h.Cells[d] {
+			if v := h.Value(d, hr, metric, minDeliveries); v > peak {
+				peak, day, hour, ok = v, d, hr, true
+			}
+		}
+	}
+	return day, hour, ok
+}
@@ -0,0 +1,114 @@
+package stats
+
+import (
+	"time"
+
+	"github.com/ohader/gh-hookmon/internal/config"
+	"github.com/ohader/gh-hookmon/internal/filter"
+	"github.com/ohader/gh-hookmon/internal/github"
+)
+
+// SLOResult is the evaluation of an SLO over its window
+type SLOResult struct {
+	Name               string        `json:"name"`
+	Window             time.Duration `json:"-"`
+	Hooks              int           `json:"hooks"`
+	Deliveries         int           `json:"deliveries"`
+	Failures           int           `json:"failures"`
+	Availability       float64       `json:"availability"`        // Observed fraction of successful deliveries
+	AvailabilityTarget float64       `json:"availability_target"` // 0 if no availability objective is set
+	BudgetRemaining    float64       `json:"budget_remaining"`    // Fraction of the error budget left, negative when exhausted
+	BurnRate           float64       `json:"burn_rate"`           // Observed error rate relative to the allowed error rate
+	LatencyThreshold   time.Duration `json:"-"`                   // 0 if no latency objective is set
+	WithinLatency      float64       `json:"within_latency"`      // Observed fraction of deliveries within the latency threshold
+	LatencyTarget      float64       `json:"latency_target"`
+	Met                bool          `json:"met"`
+}
+
+// MatchesSLO checks if a delivery was sent by a hook selected by the SLO
+func MatchesSLO(slo config.SLO, d github.Delivery) bool {
+	if slo.Repository != "" && slo.Repository != d.Repository {
+		return false
+	}
+	if slo.HookID != 0 && slo.HookID != d.HookID {
+		return false
+	}
+	return filter.MatchesPattern(d.URL, slo.URL)
+}
+
+// EvaluateSLO evaluates an SLO against the deliveries within its window ending at now
+// An SLO without deliveries in its window is considered met
+func EvaluateSLO(slo config.SLO, deliveries []github.Delivery, now time.Time) (SLOResult, error) {
+	window, err := slo.GetWindow()
+	if err != nil {
+		return SLOResult{}, err
+	}
+	target, err := slo.GetAvailability()
+	if err != nil {
+		return SLOResult{}, err
+	}
+	threshold, latencyTarget, err := slo.GetLatency()
+	if err != nil {
+		return SLOResult{}, err
+	}
+
+	result := SLOResult{
+		Name:               slo.Name,
+		Window:             window,
+		AvailabilityTarget: target,
+		LatencyThreshold:   threshold,
+		LatencyTarget:      latencyTarget,
+		Availability:       1,
+		BudgetRemaining:    1,
+		WithinLatency:      1,
+		Met:                true,
+	}
+
+	since := now.Add(-window)
+	hooks := make(map[HookKey]bool)
+	within := 0
+	for _, d := range deliveries {
+		if d.DeliveredAt.Before(since) || d.DeliveredAt.After(now) || !MatchesSLO(slo, d) {
+			continue
+		}
+		hooks[ByHook(d)] = true
+		result.Deliveries++
+		if filter.IsFailed(d.StatusCode) {
+			result.Failures++
+		}
+		if time.Duration(d.Duration*float64(time.Second)) <= threshold {
+			within++
+		}
+	}
+	result.Hooks = len(hooks)
+
+	if result.Deliveries == 0 {
+		return result, nil
+	}
+
+	errorRate := float64(result.Failures) / float64(result.Deliveries)
+	result.Availability = 1 - errorRate
+
+	if target > 0 {
+		allowed := 1 - target
+		if allowed > 0 {
+			result.BurnRate = errorRate / allowed
+			result.BudgetRemaining = 1 - result.BurnRate
+		} else if result.Failures > 0 {
+			// A 100% target leaves no error budget at all
+			result.BudgetRemaining = 0
+		}
+		if result.Availability < target {
+			result.Met = false
+		}
+	}
+
+	if threshold > 0 {
+		result.WithinLatency = float64(within) / float64(result.Deliveries)
+		if result.WithinLatency < latencyTarget {
+			result.Met = false
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,113 @@
+package stats
+
+import (
+	"sort"
+	"time"
+
+	"github.com/ohader/gh-hookmon/internal/filter"
+	"github.com/ohader/gh-hookmon/internal/github"
+)
+
+// Summary aggregates a group of deliveries
+type Summary struct {
+	Deliveries    int
+	Failures      int
+	FirstAt       time.Time
+	LastAt        time.Time
+	LastFailureAt time.Time
+	Durations     []float64 // Delivery durations in seconds
+}
+
+// Add includes a delivery in the summary
+func (s *Summary) Add(d github.Delivery) {
+	s.Deliveries++
+	if s.FirstAt.IsZero() || d.DeliveredAt.Before(s.FirstAt) {
+		s.FirstAt = d.DeliveredAt
+	}
+	if d.DeliveredAt.After(s.LastAt) {
+		s.LastAt = d.DeliveredAt
+	}
+	if filter.IsFailed(d.StatusCode) {
+		s.Failures++
+		if d.DeliveredAt.After(s.LastFailureAt) {
+			s.LastFailureAt = d.DeliveredAt
+		}
+	}
+	s.Durations = append(s.Durations, d.Duration)
+}
+
+// FailureRate returns the fraction of failed deliveries (0 for an empty summary)
+func (s *Summary) FailureRate() float64 {
+	if s.Deliveries == 0 {
+		return 0
+	}
+	return float64(s.Failures) / float64(s.Deliveries)
+}
+
+// Percentile returns the p-th percentile (0-100) of the delivery durations in seconds
+// Uses the nearest-rank method, returns 0 for an empty summary
+func (s *Summary) Percentile(p float64) float64 {
+	if len(s.Durations) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), s.Durations...)
+	sort.Float64s(sorted)
+
+	rank := int(p/100*float64(len(sorted))+0.5) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return sorted[rank]
+}
+
+// Summarize aggregates all deliveries into a single summary
+func Summarize(deliveries []github.Delivery) *Summary {
+	s := &Summary{}
+	for _, d := range deliveries {
+		s.Add(d)
+	}
+	return s
+}
+
+// GroupBy aggregates deliveries into one summary per key
+func GroupBy[K comparable](deliveries []github.Delivery, key func(github.Delivery) K) map[K]*Summary {
+	groups := make(map[K]*Summary)
+	for _, d := range deliveries {
+		k := key(d)
+		if groups[k] == nil {
+			groups[k] = &Summary{}
+		}
+		groups[k].Add(d)
+	}
+	return groups
+}
+
+// HookKey identifies a webhook of a repository
+type HookKey struct {
+	Repository string
+	HookID     int
+	URL        string
+}
+
+// ByHook returns the key identifying the webhook a delivery was sent by
+func ByHook(d github.Delivery) HookKey {
+	return HookKey{Repository: d.Repository, HookID: d.HookID, URL: d.URL}
+}
+
+// SortedHookKeys returns the keys of a per-hook grouping ordered by repository and hook ID
+func SortedHookKeys[V any](groups map[HookKey]V) []HookKey {
+	keys := make([]HookKey, 0, len(groups))
+	for k := range groups {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Repository != keys[j].Repository {
+			return keys[i].Repository < keys[j].Repository
+		}
+		return keys[i].HookID < keys[j].HookID
+	})
+	return keys
+}
@@ -0,0 +1,90 @@
+package stats
+
+import (
+	"sort"
+
+	"github.com/ohader/gh-hookmon/internal/github"
+)
+
+// RepositorySummary aggregates the deliveries of all hooks of a repository
+type RepositorySummary struct {
+	Repository string
+	Hooks      int // Hooks with at least one delivery
+	*Summary
+}
+
+// SummarizeRepositories aggregates deliveries into one summary per repository
+// Repositories are ordered by descending number of failures, then by descending failure rate and name
+func SummarizeRepositories(deliveries []github.Delivery) []RepositorySummary {
+	index := make(map[string]int)
+	hooks := make(map[HookKey]bool)
+	var summaries []RepositorySummary
+	for _, d := range deliveries {
+		i, ok := index[d.Repository]
+		if !ok {
+			i = len(summaries)
+			index[d.Repository] = i
+			summaries = append(summaries, RepositorySummary{Repository: d.Repository, Summary: &Summary{}})
+		}
+		summaries[i].Add(d)
+
+		key := HookKey{Repository: d.Repository, HookID: d.HookID}
+		if !hooks[key] {
+			hooks[key] = true
+			summaries[i].Hooks++
+		}
+	}
+
+	sort.SliceStable(summaries, func(i, j int) bool {
+		a, b := summaries[i], summaries[j]
+		if a.Failures != b.Failures {
+			return a.Failures > b.Failures
+		}
+		if a.FailureRate() != b.FailureRate() {
+			return a.FailureRate() > b.FailureRate()
+		}
+		return github.CompareNatural(a.Repository, b.Repository) < 0
+	})
+	return summaries
+}
+
+// HookSummary aggregates the deliveries of a hook
+type HookSummary struct {
+	HookKey // The URL is the target of the most recent delivery
+	*Summary
+}
+
+// SummarizeHooks aggregates deliveries into one summary per hook
+// Hooks are ordered by descending number of failures, then by descending failure rate, repository and hook ID
+func SummarizeHooks(deliveries []github.Delivery) []HookSummary {
+	index := make(map[HookKey]int)
+	var summaries []HookSummary
+	for _, d := range deliveries {
+		key := HookKey{Repository: d.Repository, HookID: d.HookID}
+		i, ok := index[key]
+		if !ok {
+			i = len(summaries)
+			index[key] = i
+			summaries = append(summaries, HookSummary{HookKey: key, Summary: &Summary{}})
+		}
+		if !d.DeliveredAt.Before(summaries[i].LastAt) {
+			summaries[i].URL = d.URL
+		}
+		summaries[i].Add(d)
+	}
+
+	sort.SliceStable(summaries, func(i, j int) bool {
+		a, b := summaries[i], summaries[j]
+		if a.Failures != b.Failures {
+			return a.Failures > b.Failures
+		}
+		if a.FailureRate() != b.FailureRate() {
+			return a.FailureRate() > b.FailureRate()
+		}
+		if a.Repository != b.Repository {
+			return github.CompareNatural(a.Repository, b.Repository) < 0
+		}
+		return a.HookID < b.HookID
+	})
+	return summaries
+}
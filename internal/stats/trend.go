@@ -0,0 +1,174 @@
+package stats
+
+import (
+	"time"
+
+	"github.com/ohader/gh-hookmon/internal/github"
+)
+
+// Period is the length of a trend bucket
+type Period string
+
+const (
+	PeriodHour Period = "hour"
+	PeriodDay  Period = "day"
+	PeriodWeek Period = "week"
+)
+
+// Start returns the beginning of the period containing t in UTC, weeks start on Monday
+func (p Period) Start(t time.Time) time.Time {
+	t = t.UTC()
+	if p == PeriodHour {
+		return t.Truncate(time.Hour)
+	}
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	if p == PeriodWeek {
+		// Sunday is day 0, move it to the end of the week
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset)
+	}
+	return day
+}
+
+// Next returns the beginning of the period following the one starting at start
+func (p Period) Next(start time.Time) time.Time {
+	switch p {
+	case PeriodHour:
+		return start.Add(time.Hour)
+	case PeriodWeek:
+		return start.AddDate(0, 0, 7)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
+// Label formats the beginning of a period, e.g. 2026-01-13 for a day or 2026-01-13 14:00 for an hour
+func (p Period) Label(start time.Time) string {
+	if p == PeriodHour {
+		return start.Format("2006-01-02 15:04")
+	}
+	return start.Format("2006-01-02")
+}
+
+// Direction is the movement of the median latency between two periods
+type Direction int
+
+const (
+	DirectionNone Direction = iota // Not enough data to compare
+	DirectionFlat
+	DirectionUp
+	DirectionDown
+)
+
+// TrendOptions configures the latency trend
+type TrendOptions struct {
+	Period    Period
+	Periods   int     // Number of periods ending with the current one
+	Threshold float64 // Minimum relative change of the median to count as up or down, e.g. 0.1
+}
+
+// TrendPeriod is the median latency of a hook within a period
+type TrendPeriod struct {
+	Start      time.Time
+	Deliveries int
+	Median     float64   // Median duration in seconds, 0 without deliveries
+	Direction  Direction // Compared to the previous period with deliveries
+}
+
+// Trend is the median latency of a hook per period
+type Trend struct {
+	Hook      HookKey
+	Periods   []TrendPeriod
+	Change    float64   // Relative change from the first to the last period with deliveries
+	Direction Direction // Direction of Change
+}
+
+// PeriodStarts returns the beginnings of the periods covered by the trend, oldest first
+func PeriodStarts(opts TrendOptions, now time.Time) []time.Time {
+	starts := make([]time.Time, opts.Periods)
+	start := opts.Period.Start(now)
+	for i := opts.Periods - 1; i >= 0; i-- {
+		starts[i] = start
+		start = opts.Period.Start(start.Add(-time.Nanosecond))
+	}
+	return starts
+}
+
+// ComputeTrends returns the median latency per period of every hook with deliveries in the covered periods
+// Trends are ordered by repository and hook ID
+func ComputeTrends(deliveries []github.Delivery, opts TrendOptions, now time.Time) []Trend {
+	starts := PeriodStarts(opts, now)
+	if len(starts) == 0 {
+		return nil
+	}
+	index := make(map[time.Time]int, len(starts))
+	for i, start := range starts {
+		index[start] = i
+	}
+
+	buckets := make(map[HookKey][]Summary)
+	for _, d := range deliveries {
+		i, ok := index[opts.Period.Start(d.DeliveredAt)]
+		if !ok || d.DeliveredAt.After(now) {
+			continue
+		}
+		key := ByHook(d)
+		if buckets[key] == nil {
+			buckets[key] = make([]Summary, len(starts))
+		}
+		buckets[key][i].Add(d)
+	}
+
+	trends := make([]Trend, 0, len(buckets))
+	for _, key := range SortedHookKeys(buckets) {
+		summaries := buckets[key]
+		trend := Trend{Hook: key, Periods: make([]TrendPeriod, len(starts))}
+		first, last := -1.0, -1.0
+		for i := range summaries {
+			period := TrendPeriod{Start: starts[i], Deliveries: summaries[i].Deliveries}
+			if period.Deliveries > 0 {
+				period.Median = summaries[i].Percentile(50)
+				if last >= 0 {
+					period.Direction = direction(last, period.Median, opts.Threshold)
+				}
+				if first < 0 {
+					first = period.Median
+				}
+				last = period.Median
+			}
+			trend.Periods[i] = period
+		}
+		if first > 0 {
+			trend.Change = (last - first) / first
+		}
+		if first >= 0 && last >= 0 && countPeriodsWithDeliveries(trend.Periods) > 1 {
+			trend.Direction = direction(first, last, opts.Threshold)
+		}
+		trends = append(trends, trend)
+	}
+	return trends
+}
+
+// direction classifies the change from before to after
+func direction(before, after, threshold float64) Direction {
+	switch {
+	case before == 0 && after == 0:
+		return DirectionFlat
+	case before == 0 || after > before*(1+threshold):
+		return DirectionUp
+	case after < before*(1-threshold):
+		return DirectionDown
+	default:
+		return DirectionFlat
+	}
+}
+
+// countPeriodsWithDeliveries returns the number of periods with at least one delivery
+func countPeriodsWithDeliveries(periods []TrendPeriod) int {
+	n := 0
+	for _, p := range periods {
+		if p.Deliveries > 0 {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,157 @@
+package statsd
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// Flavor is the wire format metrics are sent in
+type Flavor string
+
+const (
+	FlavorDogStatsD Flavor = "dogstatsd" // Tags as |#key:value, latencies as distributions
+	FlavorStatsD    Flavor = "statsd"    // Tag values folded into the metric name, latencies as timers
+)
+
+// maxPacketSize keeps packets below the usual MTU, so they are not fragmented or dropped
+const maxPacketSize = 1432
+
+// Tag is a dimension a metric is reported by
+type Tag struct {
+	Key   string
+	Value string
+}
+
+// Client buffers metrics and sends them to a StatsD server over UDP in as few packets as possible
+type Client struct {
+	conn   net.Conn
+	prefix string
+	flavor Flavor
+	lines  []string
+}
+
+// New creates a client sending to address (HOST:PORT), metric names are prefixed with prefix and a dot
+func New(address, prefix string, flavor Flavor) (*Client, error) {
+	if flavor != FlavorDogStatsD && flavor != FlavorStatsD {
+		return nil, fmt.Errorf("invalid StatsD flavor %q, expected dogstatsd or statsd", flavor)
+	}
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		return nil, fmt.Errorf("invalid StatsD address %q, expected HOST:PORT", address)
+	}
+	conn, err := net.Dial("udp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to StatsD at %s: %w", address, err)
+	}
+	return &Client{conn: conn, prefix: strings.TrimSuffix(prefix, "."), flavor: flavor}, nil
+}
+
+// Count adds value to a counter
+func (c *Client) Count(name string, value int, tags ...Tag) {
+	c.add(name, strconv.Itoa(value), "c", tags)
+}
+
+// Gauge sets a gauge to value
+func (c *Client) Gauge(name string, value float64, tags ...Tag) {
+	c.add(name, strconv.FormatFloat(value, 'f', -1, 64), "g", tags)
+}
+
+// Timing records a duration in milliseconds, as a distribution with DogStatsD and as a timer with StatsD
+func (c *Client) Timing(name string, milliseconds float64, tags ...Tag) {
+	kind := "ms"
+	if c.flavor == FlavorDogStatsD {
+		kind = "d"
+	}
+	c.add(name, strconv.FormatFloat(milliseconds, 'f', -1, 64), kind, tags)
+}
+
+// Flush sends all buffered metrics, several metrics share a packet separated by newlines
+func (c *Client) Flush() error {
+	var packet strings.Builder
+	send := func() error {
+		if packet.Len() == 0 {
+			return nil
+		}
+		_, err := c.conn.Write([]byte(packet.String()))
+		packet.Reset()
+		return err
+	}
+
+	for _, line := range c.lines {
+		if packet.Len() > 0 && packet.Len()+1+len(line) > maxPacketSize {
+			if err := send(); err != nil {
+				return fmt.Errorf("failed to send metrics: %w", err)
+			}
+		}
+		if packet.Len() > 0 {
+			packet.WriteByte('\n')
+		}
+		packet.WriteString(line)
+	}
+	c.lines = nil
+	if err := send(); err != nil {
+		return fmt.Errorf("failed to send metrics: %w", err)
+	}
+	return nil
+}
+
+// Close releases the connection, unsent metrics are discarded
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// add buffers a metric line in the wire format of the flavor
+func (c *Client) add(name, value, kind string, tags []Tag) {
+	parts := []string{}
+	if c.prefix != "" {
+		parts = append(parts, c.prefix)
+	}
+	parts = append(parts, name)
+
+	var suffix string
+	if c.flavor == FlavorStatsD {
+		for _, t := range tags {
+			parts = append(parts, sanitize(t.Value, "_"))
+		}
+	} else if len(tags) > 0 {
+		pairs := make([]string, len(tags))
+		for i, t := range tags {
+			pairs[i] = sanitize(t.Key, "_") + ":" + sanitizeTag(t.Value)
+		}
+		suffix = "|#" + strings.Join(pairs, ",")
+	}
+	c.lines = append(c.lines, fmt.Sprintf("%s:%s|%s%s", strings.Join(parts, "."), value, kind, suffix))
+}
+
+// sanitize replaces everything but letters, digits, underscores and dashes, e.g. the slash of a repository
+func sanitize(s, replacement string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '-':
+			b.WriteRune(r)
+		default:
+			b.WriteString(replacement)
+		}
+	}
+	if b.Len() == 0 {
+		return "none"
+	}
+	return b.String()
+}
+
+// sanitizeTag removes the characters separating metrics, tags and fields from a DogStatsD tag value
+func sanitizeTag(s string) string {
+	s = strings.Map(func(r rune) rune {
+		switch r {
+		case ',', '|', '#', '\n', ' ':
+			return '_'
+		}
+		return r
+	}, s)
+	if s == "" {
+		return "none"
+	}
+	return s
+}